@@ -1,15 +1,28 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/YashIIT0909/TRexT/internal/components"
+	"github.com/YashIIT0909/TRexT/internal/graphql"
 	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/openapi"
+	"github.com/YashIIT0909/TRexT/internal/postman"
 	"github.com/YashIIT0909/TRexT/internal/storage"
+	"github.com/YashIIT0909/TRexT/internal/utils"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+// historyLimit is the number of entries shown in the history panel
+const historyLimit = 100
+
 // App is the main application structure
 type App struct {
 	// tview application
@@ -21,22 +34,52 @@ type App struct {
 	mainLayout *tview.Flex
 
 	// Components
-	collections  *components.CollectionsList
-	requestPanel *components.RequestPanel
-	responseView *components.ResponseView
-	helpBar      *components.HelpBar
-	saveDialog   *components.SaveDialog
+	collections            *components.CollectionsList
+	history                *components.HistoryPanel
+	requestPanel           *components.RequestPanel
+	responseView           *components.ResponseView
+	helpBar                *components.HelpBar
+	requestTabs            *components.RequestTabs
+	saveDialog             *components.SaveDialog
+	envSelect              *components.EnvironmentSelect
+	envDialog              *components.EnvironmentDialog
+	importDialog           *components.ImportDialog
+	exportDialog           *components.ExportDialog
+	saveResponseDialog     *components.SaveResponseDialog
+	historyDialog          *components.HistoryDialog
+	clearHistoryDialog     *components.ConfirmDialog
+	promptDialog           *components.PromptDialog
+	collectionDialog       *components.CollectionDialog
+	deleteCollectionDialog *components.ConfirmDialog
+	deleteFolderDialog     *components.ConfirmDialog
 
 	// Services
 	httpClient *http.Client
-	db         *storage.DB
+	db         storage.Store
 	config     *storage.Config
 
 	// State
-	currentRequest   *http.Request
-	currentRequestID int64
-	focusIndex       int
-	focusables       []tview.Primitive
+	currentRequest      *http.Request
+	currentRequestID    int64
+	currentCollectionID int64
+	currentFolderID     int64
+	savedCollections    []*storage.Collection
+	savedFolders        []*storage.Folder
+	environments        []*storage.Environment
+	tabs                []*requestTab
+	currentTab          int
+	focusIndex          int
+	focusables          []tview.Primitive
+
+	// Files in the temp directory that response bodies were streamed to,
+	// removed once no tab shows their response
+	bodyFiles map[string]bool
+
+	// GraphQL schemas fetched this session, by endpoint, and the fetch in
+	// flight, if any
+	schemas      map[string]*graphql.Schema
+	schemaFetch  context.Context
+	cancelSchema context.CancelFunc
 }
 
 // New creates a new App instance
@@ -50,23 +93,39 @@ func New() (*App, error) {
 	// Apply theme
 	ApplyTheme(GetTheme(config.Theme))
 
-	// Initialize database
-	db, err := storage.NewDB()
+	// Build the HTTP client from the network settings
+	httpClient, err := http.NewClient(config.TransportConfig())
+	if err != nil {
+		return nil, fmt.Errorf("invalid network configuration: %w", err)
+	}
+	httpClient.SetDownloadConfig(config.DownloadConfig())
+
+	// Open the configured storage backend
+	db, err := storage.NewStore(config)
 	if err != nil {
 		return nil, err
 	}
 
 	app := &App{
-		tviewApp:       tview.NewApplication(),
-		httpClient:     http.NewClient(),
-		db:             db,
-		config:         config,
-		currentRequest: http.NewRequest(),
+		tviewApp:            tview.NewApplication(),
+		httpClient:          httpClient,
+		db:                  db,
+		config:              config,
+		currentRequest:      http.NewRequest(),
+		currentCollectionID: storage.DefaultCollectionID,
+		tabs:                []*requestTab{newRequestTab()},
+		bodyFiles:           make(map[string]bool),
+		schemas:             make(map[string]*graphql.Schema),
 	}
 
 	app.buildUI()
 	app.setupHandlers()
+	app.loadCollections()
+	app.loadFolders()
 	app.loadSavedRequests()
+	app.loadEnvironments()
+	app.pruneHistory()
+	app.loadHistory()
 
 	return app, nil
 }
@@ -75,22 +134,36 @@ func New() (*App, error) {
 func (a *App) buildUI() {
 	// Create components
 	a.collections = components.NewCollectionsList()
+	a.history = components.NewHistoryPanel()
 	a.requestPanel = components.NewRequestPanel()
 	a.responseView = components.NewResponseView()
 	a.helpBar = components.NewHelpBar()
+	a.requestTabs = components.NewRequestTabs()
 	a.saveDialog = components.NewSaveDialog()
+	a.envSelect = components.NewEnvironmentSelect()
+	a.envDialog = components.NewEnvironmentDialog()
+	a.importDialog = components.NewImportDialog()
+	a.exportDialog = components.NewExportDialog()
+	a.saveResponseDialog = components.NewSaveResponseDialog()
+	a.historyDialog = components.NewHistoryDialog()
+	a.clearHistoryDialog = components.NewConfirmDialog("Delete all history entries?")
+	a.promptDialog = components.NewPromptDialog()
+	a.collectionDialog = components.NewCollectionDialog()
+	a.deleteCollectionDialog = components.NewConfirmDialog("")
+	a.deleteFolderDialog = components.NewConfirmDialog("")
 
 	// Set initial state
 	a.responseView.Clear()
+	a.refreshTabs()
 
 	// Create layout:
 	// ┌─────────────┬───────────────────────────────────────┐
 	// │             │ Request (URL/Method) - spans full     │
 	// │ Collections ├─────────────────────┬─────────────────┤
 	// │             │ Headers             │                 │
-	// │             ├─────────────────────┤    Response     │
+	// ├─────────────┼─────────────────────┤    Response     │
 	// │             │ Body                │                 │
-	// │             ├─────────────────────┤                 │
+	// │ History     ├─────────────────────┤                 │
 	// │             │ [Send Button]       │                 │
 	// └─────────────┴─────────────────────┴─────────────────┘
 
@@ -100,16 +173,30 @@ func (a *App) buildUI() {
 		AddItem(a.requestPanel.BodyContainer, 0, 1, false).
 		AddItem(a.responseView.Container, 0, 1, false)
 
-	// Right panel: Request URL on top, middle section below
+	// Top section: Request URL/Method | Environment switcher
+	topSection := tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(a.requestPanel.TopRow, 0, 1, true).
+		AddItem(a.envSelect.Container, 26, 0, false)
+
+	// Right panel: Open request tabs and the request URL on top, middle
+	// section below
 	rightPanel := tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(a.requestPanel.TopRow, 3, 0, true).
+		AddItem(a.requestTabs.View, 1, 0, false).
+		AddItem(topSection, 3, 0, true).
 		AddItem(middleSection, 0, 1, false)
 
-	// Main layout: Collections | Right panel
+	// Sidebar: Collections above History
+	sidebar := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(a.collections.Container, 0, 1, true).
+		AddItem(a.history.Container, 0, 1, false)
+
+	// Main layout: Sidebar | Right panel
 	a.mainLayout = tview.NewFlex().
 		SetDirection(tview.FlexColumn).
-		AddItem(a.collections.Container, 0, 1, true).
+		AddItem(sidebar, 0, 1, true).
 		AddItem(rightPanel, 0, 4, false)
 
 	// Root layout with help bar at bottom
@@ -121,21 +208,23 @@ func (a *App) buildUI() {
 	// Pages for modal dialogs
 	a.pages = tview.NewPages().
 		AddPage("main", a.rootFlex, true, true).
-		AddPage("save", a.saveDialog.Container, true, false)
+		AddPage("save", a.saveDialog.Container, true, false).
+		AddPage("environments", a.envDialog.Container, true, false).
+		AddPage("import", a.importDialog.Container, true, false).
+		AddPage("export", a.exportDialog.Container, true, false).
+		AddPage("saveResponse", a.saveResponseDialog.Container, true, false).
+		AddPage("history", a.historyDialog.Container, true, false).
+		AddPage("clearHistory", a.clearHistoryDialog.Container, true, false).
+		AddPage("prompt", a.promptDialog.Container, true, false).
+		AddPage("collection", a.collectionDialog.Container, true, false).
+		AddPage("deleteCollection", a.deleteCollectionDialog.Container, true, false).
+		AddPage("deleteFolder", a.deleteFolderDialog.Container, true, false)
 
 	// Set focusable items for navigation
-	a.focusables = []tview.Primitive{
-		a.collections.List,
-		a.requestPanel.MethodSelect,
-		a.requestPanel.URLInput,
-		a.requestPanel.HeadersInput,
-		a.requestPanel.BodyInput,
-		a.requestPanel.SendButton,
-		a.responseView.BodyView,
-	}
+	a.refreshFocusables()
 
 	a.tviewApp.SetRoot(a.pages, true)
-	a.tviewApp.SetFocus(a.collections.List)
+	a.tviewApp.SetFocus(a.collections.Tree)
 }
 
 // setupHandlers configures event handlers
@@ -146,39 +235,315 @@ func (a *App) setupHandlers() {
 	// Enable mouse support
 	a.tviewApp.EnableMouse(true)
 
+	// Deliver pasted text in one piece so curl commands can be detected
+	a.tviewApp.EnablePaste(true)
+
 	// Setup mouse click handlers for focusable components
 	a.setupMouseHandlers()
 
+	// OAuth 2.0 authorization code flow: send the user to the browser
+	a.httpClient.SetAuthorizationHandler(func(authURL string) {
+		_ = utils.OpenBrowser(authURL)
+		a.tviewApp.QueueUpdateDraw(func() {
+			a.responseView.StatusBar.SetText("[yellow]Complete authorization in your browser:[-] " + tview.Escape(authURL))
+		})
+	})
+
 	// Request panel handlers
 	a.requestPanel.SetOnSend(a.executeRequest)
+	a.requestPanel.SetOnFetchSchema(a.fetchSchema)
+	a.requestPanel.SetSchemaSource(func(url string) *graphql.Schema {
+		return a.schemas[a.schemaKey(url)]
+	})
+	a.requestPanel.SetOnTabChange(func(name string) {
+		a.refreshFocusables()
+	})
+	a.requestPanel.SetOnFocus(func(p tview.Primitive) {
+		a.setFocus(p)
+	})
+	a.responseView.SetOnTabChange(func(name string) {
+		a.refreshFocusables()
+	})
+
+	a.responseView.SetOnFilterDone(func(filter string) {
+		a.saveFilter(filter)
+	})
+	a.responseView.SetQueueUpdate(func(fn func()) {
+		a.tviewApp.QueueUpdateDraw(fn)
+	})
+
+	a.responseView.SetOnFocus(func(p tview.Primitive) {
+		a.tviewApp.SetFocus(p)
+	})
+
+	a.responseView.JSONTree.SetOnCopy(func(text string) {
+		if err := utils.CopyToClipboard(text); err != nil {
+			a.responseView.JSONTree.SetMessage("[red]" + tview.Escape(err.Error()) + "[-]")
+			return
+		}
+		a.responseView.JSONTree.SetMessage("[green]Copied to clipboard[-]")
+	})
+
+	a.responseView.SetOnSave(a.showSaveResponseDialog)
+	a.requestPanel.SetOnCurl(func(command string) {
+		// Defer until the URL field has finished handling the paste
+		go a.tviewApp.QueueUpdateDraw(func() {
+			if err := a.importCurl(command); err != nil {
+				a.responseView.SetError(err)
+			}
+		})
+	})
+
+	// Request tab handlers
+	a.requestTabs.SetOnSelect(func(index int) {
+		a.showTab(index)
+	})
 
 	// Collections list handlers
-	a.collections.SetOnSelect(func(req *http.Request) {
+	a.collections.SetOnSelect(func(req *http.Request, collectionID, folderID int64) {
+		// A request already open in a tab is shown there
+		if i := a.findTab(req.ID); i >= 0 {
+			a.showTab(i)
+			a.tviewApp.SetFocus(a.requestPanel.URLInput)
+			return
+		}
+
+		a.freeTab()
 		a.currentRequest = req
 		a.currentRequestID = req.ID
+		a.currentCollectionID = collectionID
+		a.currentFolderID = folderID
 		a.requestPanel.SetRequest(req)
+		a.responseView.Clear()
+		a.responseView.SetFilter(req.Filter)
+		a.refreshTabs()
 		a.tviewApp.SetFocus(a.requestPanel.URLInput)
 	})
 
-	a.collections.SetOnNew(func() {
+	a.collections.SetOnNew(func(collectionID, folderID int64) {
 		a.newRequest()
+		a.currentCollectionID = collectionID
+		a.currentFolderID = folderID
 	})
 
 	a.collections.SetOnDelete(func(id int64) {
 		a.deleteRequest(id)
 	})
 
+	a.collections.SetOnToggleHistory(func(collectionID int64) {
+		a.toggleCollectionHistory(collectionID)
+	})
+
+	a.collections.SetOnNewCollection(func() {
+		a.showPrompt("New Collection", "", func(name string) {
+			a.saveCollection(&storage.Collection{Name: name, Variables: map[string]string{}})
+		})
+	})
+
+	a.collections.SetOnRenameCollection(func(c *storage.Collection) {
+		a.showPrompt("Rename Collection", c.Name, func(name string) {
+			renamed := *c
+			renamed.Name = name
+			a.saveCollection(&renamed)
+		})
+	})
+
+	a.collections.SetOnDeleteCollection(func(c *storage.Collection) {
+		a.showDeleteCollectionDialog(c)
+	})
+
+	a.collections.SetOnNewFolder(func(collectionID, parentID int64) {
+		a.showPrompt("New Folder", "", func(name string) {
+			a.saveFolder(&storage.Folder{CollectionID: collectionID, ParentID: parentID, Name: name})
+		})
+	})
+
+	a.collections.SetOnRenameFolder(func(f *storage.Folder) {
+		a.showPrompt("Rename Folder", f.Name, func(name string) {
+			renamed := *f
+			renamed.Name = name
+			a.saveFolder(&renamed)
+		})
+	})
+
+	a.collections.SetOnDeleteFolder(func(f *storage.Folder, folders, requests int) {
+		a.showDeleteFolderDialog(f, folders, requests)
+	})
+
+	a.collections.SetOnRenameRequest(func(req *storage.SavedRequest) {
+		a.showPrompt("Rename Request", req.Name, func(name string) {
+			renamed := *req
+			renamed.Name = name
+			a.updateSavedRequest(&renamed)
+		})
+	})
+
+	a.collections.SetOnMoveRequest(func(req *storage.SavedRequest) {
+		a.showCollectionDialog("Move Request", req.CollectionID, req.FolderID, func(collectionID, folderID int64) {
+			moved := *req
+			moved.CollectionID = collectionID
+			moved.FolderID = folderID
+			a.updateSavedRequest(&moved)
+		})
+	})
+
+	a.collections.SetOnCopyRequest(func(req *storage.SavedRequest) {
+		a.showCollectionDialog("Copy Request", req.CollectionID, req.FolderID, func(collectionID, folderID int64) {
+			copied := *req
+			copied.ID = 0
+			copied.CollectionID = collectionID
+			copied.FolderID = folderID
+			a.updateSavedRequest(&copied)
+		})
+	})
+
+	a.promptDialog.SetOnCancel(func() {
+		a.pages.HidePage("prompt")
+		a.tviewApp.SetFocus(a.collections.Tree)
+	})
+
+	a.collectionDialog.SetOnCancel(func() {
+		a.pages.HidePage("collection")
+		a.tviewApp.SetFocus(a.collections.Tree)
+	})
+
+	a.deleteCollectionDialog.SetOnCancel(func() {
+		a.pages.HidePage("deleteCollection")
+		a.tviewApp.SetFocus(a.collections.Tree)
+	})
+
+	a.deleteFolderDialog.SetOnCancel(func() {
+		a.pages.HidePage("deleteFolder")
+		a.tviewApp.SetFocus(a.collections.Tree)
+	})
+
+	// History handlers
+	a.history.SetOnSearch(func(query string) {
+		a.loadHistory()
+	})
+
+	a.history.SetOnOpen(func(id int64) {
+		a.showHistoryDialog(id)
+	})
+
+	a.history.SetOnReplay(func(id int64) {
+		if entry, err := a.db.GetHistoryEntry(id); err == nil {
+			a.replayHistory(entry)
+		}
+	})
+
+	a.history.SetOnSave(func(id int64) {
+		if entry, err := a.db.GetHistoryEntry(id); err == nil {
+			a.saveHistory(entry)
+		}
+	})
+
+	a.history.SetOnClear(func() {
+		a.pages.ShowPage("clearHistory")
+		a.tviewApp.SetFocus(a.clearHistoryDialog.Modal)
+	})
+
+	a.clearHistoryDialog.SetOnConfirm(func() {
+		a.clearHistory()
+		a.pages.HidePage("clearHistory")
+		a.tviewApp.SetFocus(a.history.List)
+	})
+
+	a.clearHistoryDialog.SetOnCancel(func() {
+		a.pages.HidePage("clearHistory")
+		a.tviewApp.SetFocus(a.history.List)
+	})
+
+	a.historyDialog.SetOnReplay(func(entry *storage.HistoryEntry) {
+		a.pages.HidePage("history")
+		a.replayHistory(entry)
+	})
+
+	a.historyDialog.SetOnSave(func(entry *storage.HistoryEntry) {
+		a.pages.HidePage("history")
+		a.saveHistory(entry)
+	})
+
+	a.historyDialog.SetOnClose(func() {
+		a.pages.HidePage("history")
+		a.tviewApp.SetFocus(a.history.List)
+	})
+
 	// Save dialog handlers
-	a.saveDialog.SetOnSave(func(name string) {
-		a.saveRequest(name)
+	a.saveDialog.SetOnSave(func(name string, collectionID, folderID int64) {
+		a.saveRequest(name, collectionID, folderID)
 		a.pages.HidePage("save")
-		a.tviewApp.SetFocus(a.collections.List)
+		a.tviewApp.SetFocus(a.collections.Tree)
 	})
 
 	a.saveDialog.SetOnCancel(func() {
 		a.pages.HidePage("save")
 		a.tviewApp.SetFocus(a.requestPanel.URLInput)
 	})
+
+	// Environment handlers
+	a.envSelect.SetOnChange(func(env *storage.Environment) {
+		a.setActiveEnvironment(env)
+	})
+
+	a.envDialog.SetOnSave(func(env *storage.Environment) {
+		a.saveEnvironment(env)
+	})
+
+	a.envDialog.SetOnDelete(func(id int64) {
+		a.deleteEnvironment(id)
+	})
+
+	a.envDialog.SetOnClose(func() {
+		a.pages.HidePage("environments")
+		a.tviewApp.SetFocus(a.envSelect.DropDown)
+	})
+
+	a.envDialog.SetOnFocus(func(p tview.Primitive) {
+		a.tviewApp.SetFocus(p)
+	})
+
+	// Import/export handlers
+	a.importDialog.SetOnImport(func(text string) {
+		if err := a.importText(text); err != nil {
+			a.importDialog.SetError(err)
+			return
+		}
+		a.pages.HidePage("import")
+		a.tviewApp.SetFocus(a.requestPanel.URLInput)
+	})
+
+	a.importDialog.SetOnCancel(func() {
+		a.pages.HidePage("import")
+		a.tviewApp.SetFocus(a.requestPanel.URLInput)
+	})
+
+	a.exportDialog.SetOnCopy(func(text string) {
+		if err := utils.CopyToClipboard(text); err != nil {
+			a.exportDialog.SetMessage("[red]" + tview.Escape(err.Error()) + "[-]")
+			return
+		}
+		a.exportDialog.SetMessage("[green]Copied to clipboard[-]")
+	})
+
+	a.exportDialog.SetOnSaveCollection(func(path string) {
+		path = utils.ExpandPath(path)
+		if err := a.exportCollection(path); err != nil {
+			a.exportDialog.SetMessage("[red]" + tview.Escape(err.Error()) + "[-]")
+			return
+		}
+		a.exportDialog.SetMessage("[green]Saved collection to " + tview.Escape(path) + "[-]")
+	})
+
+	a.exportDialog.SetOnClose(func() {
+		a.pages.HidePage("export")
+		a.tviewApp.SetFocus(a.requestPanel.URLInput)
+	})
+
+	a.saveResponseDialog.SetOnCancel(func() {
+		a.pages.HidePage("saveResponse")
+		a.tviewApp.SetFocus(a.responseView.CurrentView())
+	})
 }
 
 // handleGlobalKeys handles global keyboard shortcuts
@@ -194,6 +559,39 @@ func (a *App) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 		a.Stop()
 		return nil
 
+	case event.Key() == tcell.KeyEscape || event.Key() == tcell.KeyCtrlC:
+		// Cancel the request or schema fetch in flight; Ctrl+C quits when
+		// there is none, cleaning up like Ctrl+Q
+		if a.cancelRequest() || a.cancelSchemaFetch() {
+			return nil
+		}
+		if event.Key() == tcell.KeyCtrlC {
+			a.Stop()
+			return nil
+		}
+		return event
+
+	case event.Key() == tcell.KeyCtrlT:
+		// Open a new request tab
+		a.newTab()
+		a.setFocus(a.requestPanel.URLInput)
+		return nil
+
+	case event.Key() == tcell.KeyCtrlW:
+		// Close the current request tab
+		a.closeTab()
+		return nil
+
+	case event.Key() == tcell.KeyPgDn && event.Modifiers()&tcell.ModCtrl != 0:
+		// Next request tab
+		a.nextTab(1)
+		return nil
+
+	case event.Key() == tcell.KeyPgUp && event.Modifiers()&tcell.ModCtrl != 0:
+		// Previous request tab
+		a.nextTab(-1)
+		return nil
+
 	case event.Key() == tcell.KeyCtrlN:
 		// New request
 		a.newRequest()
@@ -204,6 +602,26 @@ func (a *App) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 		a.showSaveDialog()
 		return nil
 
+	case event.Key() == tcell.KeyCtrlE:
+		// Manage environments
+		a.showEnvironmentDialog()
+		return nil
+
+	case event.Key() == tcell.KeyCtrlO:
+		// Import a curl command
+		a.showImportDialog()
+		return nil
+
+	case event.Key() == tcell.KeyCtrlG:
+		// Export the current request as curl
+		a.showExportDialog()
+		return nil
+
+	case event.Key() == tcell.KeyCtrlR:
+		// Focus history
+		a.setFocus(a.history.List)
+		return nil
+
 	case event.Key() == tcell.KeyEnter && event.Modifiers()&tcell.ModCtrl != 0:
 		// Send request (Ctrl+Enter)
 		a.executeRequest()
@@ -221,26 +639,63 @@ func (a *App) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 
 	case event.Key() == tcell.KeyCtrlH:
 		// Focus collections (left)
-		a.tviewApp.SetFocus(a.collections.List)
+		a.tviewApp.SetFocus(a.collections.Tree)
 		a.focusIndex = 0
 		return nil
 
 	case event.Key() == tcell.KeyCtrlL:
 		// Focus response (right)
-		a.tviewApp.SetFocus(a.responseView.BodyView)
-		a.focusIndex = len(a.focusables) - 1
+		a.setFocus(a.responseView.CurrentView())
 		return nil
 
 	case event.Key() == tcell.KeyCtrlU:
 		// Focus URL input
-		a.tviewApp.SetFocus(a.requestPanel.URLInput)
-		a.focusIndex = 1
+		a.setFocus(a.requestPanel.URLInput)
 		return nil
 	}
 
 	return event
 }
 
+// refreshFocusables rebuilds the navigation order; the request panel
+// contributes only the items of its visible tab
+func (a *App) refreshFocusables() {
+	a.focusables = []tview.Primitive{
+		a.collections.Tree,
+	}
+	a.focusables = append(a.focusables, a.history.GetFocusableItems()...)
+	a.focusables = append(a.focusables,
+		a.requestPanel.MethodSelect,
+		a.requestPanel.URLInput,
+		a.envSelect.DropDown,
+	)
+	a.focusables = append(a.focusables, a.requestPanel.GetTabFocusableItems()...)
+	a.focusables = append(a.focusables,
+		a.requestPanel.SendButton,
+	)
+	a.focusables = append(a.focusables, a.responseView.GetFocusableItems()...)
+
+	// Keep the index in sync with the focused widget
+	focused := a.tviewApp.GetFocus()
+	for i, primitive := range a.focusables {
+		if primitive == focused {
+			a.focusIndex = i
+			return
+		}
+	}
+}
+
+// setFocus focuses a widget and keeps the navigation index in sync
+func (a *App) setFocus(p tview.Primitive) {
+	a.tviewApp.SetFocus(p)
+	for i, primitive := range a.focusables {
+		if primitive == p {
+			a.focusIndex = i
+			return
+		}
+	}
+}
+
 // focusNext focuses the next widget
 func (a *App) focusNext() {
 	a.focusIndex = (a.focusIndex + 1) % len(a.focusables)
@@ -293,71 +748,428 @@ func (a *App) executeRequest() {
 		return
 	}
 
+	// History keeps the request as written besides the one sent, so it can
+	// be saved back without its variables resolved
+	template := req
+
+	// Substitute {{variables}} from the collection and active environment
+	req, err := req.Resolve(a.activeVariables())
+	if err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+	req.Environment = a.config.ActiveEnvironment
+	record := a.shouldRecordHistory()
+	maxBodySize := a.config.History.MaxBodySize
+
+	// Sending again from the same tab replaces the request in flight
+	tab := a.tabs[a.currentTab]
+	if tab.sending != nil {
+		tab.sending.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &sending{cancel: cancel, started: time.Now()}
+	tab.sending = s
+	ctx = http.WithProgress(ctx, func(received, total int64) {
+		s.received.Store(received)
+		s.total.Store(total)
+	})
+
 	// Update status
-	a.responseView.StatusBar.SetText("[yellow]Sending request...[-]")
-	a.tviewApp.ForceDraw()
+	a.responseView.SetSending(0, 0, 0)
+	a.refreshTabs()
+	go a.tickSending(ctx, tab, s)
 
 	// Execute in goroutine to not block UI
 	go func() {
-		resp := a.httpClient.Execute(req)
+		resp := a.httpClient.Execute(ctx, req)
+		cancel()
+
+		// Add to history, failures but not cancellations included, and
+		// apply retention
+		recorded := false
+		if record && !errors.Is(resp.Error, context.Canceled) {
+			entry := storage.NewHistoryEntry(req, template, resp, maxBodySize)
+			if err := a.db.AddToHistory(entry); err == nil {
+				recorded = true
+				a.pruneHistory()
+			}
+		}
 
-		// Update UI in main thread
+		// Update UI in main thread; the response goes to the tab that sent
+		// the request, unless it was closed or sent again since
 		a.tviewApp.QueueUpdateDraw(func() {
-			a.responseView.SetResponse(resp)
-
-			// Add to history
-			if a.config.History.Enabled && resp.Error == nil {
-				entry := &storage.HistoryEntry{
-					URL:        req.URL,
-					Method:     req.Method,
-					StatusCode: resp.StatusCode,
-					Duration:   resp.Duration.Milliseconds(),
-					Timestamp:  time.Now().Unix(),
+			if recorded {
+				a.loadHistory()
+			}
+			streamed := resp.BodyFile != "" && a.config.Download.Dir == ""
+			if tab.sending != s {
+				if streamed {
+					os.Remove(resp.BodyFile)
 				}
-				_ = a.db.AddToHistory(entry)
+				return
+			}
+			tab.sending = nil
+			tab.response = resp
+			if streamed {
+				a.bodyFiles[resp.BodyFile] = true
+			}
+			if a.tabs[a.currentTab] == tab {
+				a.responseView.SetResponse(resp)
+			}
+			a.refreshTabs()
+		})
+	}()
+}
+
+// fetchSchema introspects the GraphQL endpoint of the current request with
+// its headers and auth, and caches the schema for the endpoint
+func (a *App) fetchSchema() {
+	req := a.requestPanel.GetRequest()
+	if req.URL == "" {
+		a.requestPanel.SetSchemaResult(errors.New("the request has no URL"))
+		return
+	}
+	key := a.schemaKey(req.URL)
+	req, err := graphql.NewIntrospectionRequest(req).Resolve(a.activeVariables())
+	if err != nil {
+		a.requestPanel.SetSchemaResult(err)
+		return
+	}
+	req.Environment = a.config.ActiveEnvironment
+
+	// A new fetch replaces the one in flight
+	a.cancelSchemaFetch()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.schemaFetch, a.cancelSchema = ctx, cancel
+	go func() {
+		schema, err := graphql.Introspect(ctx, a.httpClient, req)
+		a.tviewApp.QueueUpdateDraw(func() {
+			if a.schemaFetch != ctx {
+				// Replaced by a newer fetch, which reports instead
+				return
 			}
+			a.schemaFetch, a.cancelSchema = nil, nil
+			cancel()
+			if err == nil {
+				a.schemas[key] = schema
+			}
+			a.requestPanel.SetSchemaResult(err)
 		})
 	}()
 }
 
-// newRequest creates a new empty request
+// cancelSchemaFetch aborts fetching a GraphQL schema, reporting whether a
+// fetch was in flight
+func (a *App) cancelSchemaFetch() bool {
+	if a.cancelSchema == nil {
+		return false
+	}
+	a.cancelSchema()
+	return true
+}
+
+// schemaKey returns the endpoint a GraphQL schema is cached for: the URL with
+// its variables substituted, without the query string
+func (a *App) schemaKey(rawURL string) string {
+	if resolved, err := (&http.Request{URL: rawURL}).Resolve(a.activeVariables()); err == nil {
+		rawURL = resolved.URL
+	}
+	endpoint, _, _ := strings.Cut(rawURL, "?")
+	endpoint, _, _ = strings.Cut(endpoint, "#")
+	return endpoint
+}
+
+// shouldRecordHistory reports whether requests of the current collection
+// are recorded in history
+func (a *App) shouldRecordHistory() bool {
+	if !a.config.History.Enabled {
+		return false
+	}
+	c := a.currentCollection()
+	return c == nil || !c.SkipHistory
+}
+
+// pruneHistory applies the configured history retention
+func (a *App) pruneHistory() {
+	maxAge := time.Duration(a.config.History.MaxAgeDays) * 24 * time.Hour
+	_ = a.db.PruneHistory(a.config.History.MaxItems, maxAge)
+}
+
+// clearHistory deletes all history entries
+func (a *App) clearHistory() {
+	if err := a.db.ClearHistory(); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+	a.loadHistory()
+}
+
+// toggleCollectionHistory turns history recording on or off for a collection
+func (a *App) toggleCollectionHistory(collectionID int64) {
+	var c *storage.Collection
+	for _, candidate := range a.savedCollections {
+		if candidate.ID == collectionID {
+			c = candidate
+			break
+		}
+	}
+	if c == nil {
+		return
+	}
+
+	c.SkipHistory = !c.SkipHistory
+	if err := a.db.SaveCollection(c); err != nil {
+		c.SkipHistory = !c.SkipHistory
+		a.responseView.SetError(err)
+		return
+	}
+	a.collections.SetCollections(a.savedCollections)
+
+	if c.SkipHistory {
+		a.responseView.StatusBar.SetText(fmt.Sprintf("[yellow]Requests in %q are no longer recorded in history[-]", c.Name))
+	} else {
+		a.responseView.StatusBar.SetText(fmt.Sprintf("[green]Requests in %q are recorded in history[-]", c.Name))
+	}
+}
+
+// newRequest creates a new empty request, in a new tab when the current one
+// is waiting for a response
 func (a *App) newRequest() {
+	a.freeTab()
 	a.currentRequest = http.NewRequest()
 	a.currentRequestID = 0
+	a.currentCollectionID = storage.DefaultCollectionID
+	a.currentFolderID = 0
 	a.requestPanel.Clear()
 	a.responseView.Clear()
+	a.responseView.SetFilter("")
+	a.refreshTabs()
 	a.tviewApp.SetFocus(a.requestPanel.URLInput)
 }
 
 // showSaveDialog shows the save request dialog
 func (a *App) showSaveDialog() {
-	req := a.requestPanel.GetRequest()
-	if req.Name != "" {
-		a.saveDialog.SetName(req.Name)
+	if a.currentRequest.Name != "" {
+		a.saveDialog.SetName(a.currentRequest.Name)
 	} else {
 		a.saveDialog.Reset()
 	}
+	a.saveDialog.SetLocations(a.savedCollections, a.savedFolders, a.currentCollectionID, a.currentFolderID)
 	a.pages.ShowPage("save")
 	a.tviewApp.SetFocus(a.saveDialog.Modal)
 }
 
-// saveRequest saves the current request
-func (a *App) saveRequest(name string) {
+// saveRequest saves the current request into a collection or folder
+func (a *App) saveRequest(name string, collectionID, folderID int64) {
 	req := a.requestPanel.GetRequest()
 	req.Name = name
 	req.ID = a.currentRequestID
+	req.Filter = a.responseView.Filter()
 
-	savedReq := storage.FromHTTPRequest(req, 1) // Default collection
+	savedReq := storage.FromHTTPRequest(req, collectionID)
+	savedReq.FolderID = folderID
 
 	if err := a.db.SaveRequest(savedReq); err != nil {
-		// TODO: Show error
+		a.responseView.SetError(err)
 		return
 	}
 
+	a.currentRequest = req
 	a.currentRequestID = savedReq.ID
+	a.currentCollectionID = collectionID
+	a.currentFolderID = folderID
+	a.refreshTabs()
+	a.expandLocation(collectionID, folderID)
+	a.loadSavedRequests()
+}
+
+// saveFilter remembers the response filter of the current request, storing
+// it with the request when it has been saved
+func (a *App) saveFilter(filter string) {
+	a.currentRequest.Filter = filter
+	if a.currentRequestID == 0 {
+		return
+	}
+	if err := a.db.SaveRequestFilter(a.currentRequestID, filter); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
 	a.loadSavedRequests()
 }
 
+// updateSavedRequest stores a renamed, moved or copied request from the
+// collections tree
+func (a *App) updateSavedRequest(req *storage.SavedRequest) {
+	if err := a.db.SaveRequest(req); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+
+	if req.ID == a.currentRequestID {
+		a.currentRequest.Name = req.Name
+		a.currentCollectionID = req.CollectionID
+		a.currentFolderID = req.FolderID
+	}
+	for i, tab := range a.tabs {
+		if i != a.currentTab && tab.requestID == req.ID {
+			tab.request.Name = req.Name
+			tab.collectionID = req.CollectionID
+			tab.folderID = req.FolderID
+		}
+	}
+	a.refreshTabs()
+	a.expandLocation(req.CollectionID, req.FolderID)
+	a.loadSavedRequests()
+}
+
+// expandLocation opens a collection, or a folder inside one, in the tree
+func (a *App) expandLocation(collectionID, folderID int64) {
+	if folderID != 0 {
+		a.collections.ExpandFolder(folderID)
+		return
+	}
+	a.collections.Expand(collectionID)
+}
+
+// showPrompt asks for a name, calling fn with it unless it is blank
+func (a *App) showPrompt(title, text string, fn func(name string)) {
+	a.promptDialog.SetPrompt(title, text)
+	a.promptDialog.SetOnSubmit(func(name string) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return
+		}
+		a.pages.HidePage("prompt")
+		a.tviewApp.SetFocus(a.collections.Tree)
+		fn(name)
+	})
+	a.pages.ShowPage("prompt")
+	a.tviewApp.SetFocus(a.promptDialog.Modal)
+}
+
+// showCollectionDialog asks for a collection or folder, calling fn with its
+// collection and folder IDs
+func (a *App) showCollectionDialog(title string, collectionID, folderID int64, fn func(collectionID, folderID int64)) {
+	a.collectionDialog.SetLocations(title, a.savedCollections, a.savedFolders, collectionID, folderID)
+	a.collectionDialog.SetOnSubmit(func(collectionID, folderID int64) {
+		a.pages.HidePage("collection")
+		a.tviewApp.SetFocus(a.collections.Tree)
+		fn(collectionID, folderID)
+	})
+	a.pages.ShowPage("collection")
+	a.tviewApp.SetFocus(a.collectionDialog.Modal)
+}
+
+// saveCollection stores a new or renamed collection
+func (a *App) saveCollection(c *storage.Collection) {
+	if err := a.db.SaveCollection(c); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+	a.loadCollections()
+}
+
+// showDeleteCollectionDialog asks before deleting a collection and its
+// requests. The default collection holds unsorted requests and is kept.
+func (a *App) showDeleteCollectionDialog(c *storage.Collection) {
+	if c.ID == storage.DefaultCollectionID {
+		a.responseView.StatusBar.SetText(fmt.Sprintf("[yellow]The %q collection can't be deleted[-]", c.Name))
+		return
+	}
+
+	requests, err := a.db.GetRequestsByCollection(c.ID)
+	if err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+	a.deleteCollectionDialog.SetMessage(fmt.Sprintf("Delete collection %q and its %d requests?", c.Name, len(requests)))
+	a.deleteCollectionDialog.SetOnConfirm(func() {
+		a.pages.HidePage("deleteCollection")
+		a.tviewApp.SetFocus(a.collections.Tree)
+		a.deleteCollection(c.ID)
+	})
+	a.pages.ShowPage("deleteCollection")
+	a.tviewApp.SetFocus(a.deleteCollectionDialog.Modal)
+}
+
+// deleteCollection deletes a collection and its requests
+func (a *App) deleteCollection(id int64) {
+	if err := a.db.DeleteCollection(id); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+
+	if a.currentCollectionID == id {
+		a.newRequest()
+		a.setFocus(a.collections.Tree)
+	}
+
+	a.loadCollections()
+	a.loadFolders()
+	a.loadSavedRequests()
+}
+
+// saveFolder stores a new or renamed folder
+func (a *App) saveFolder(f *storage.Folder) {
+	if err := a.db.SaveFolder(f); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+	a.loadFolders()
+	a.expandLocation(f.CollectionID, f.ParentID)
+}
+
+// showDeleteFolderDialog asks before deleting a folder, saying how many
+// subfolders and requests go with it
+func (a *App) showDeleteFolderDialog(f *storage.Folder, folders, requests int) {
+	message := fmt.Sprintf("Delete empty folder %q?", f.Name)
+	if folders > 0 || requests > 0 {
+		message = fmt.Sprintf("Delete folder %q with its %d subfolders and %d requests?", f.Name, folders, requests)
+	}
+	a.deleteFolderDialog.SetMessage(message)
+	a.deleteFolderDialog.SetOnConfirm(func() {
+		a.pages.HidePage("deleteFolder")
+		a.tviewApp.SetFocus(a.collections.Tree)
+		a.deleteFolder(f.ID)
+	})
+	a.pages.ShowPage("deleteFolder")
+	a.tviewApp.SetFocus(a.deleteFolderDialog.Modal)
+}
+
+// deleteFolder deletes a folder with its subfolders and their requests
+func (a *App) deleteFolder(id int64) {
+	if err := a.db.DeleteFolder(id); err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+
+	if a.insideFolder(a.currentFolderID, id) {
+		a.newRequest()
+		a.setFocus(a.collections.Tree)
+	}
+
+	a.loadFolders()
+	a.loadSavedRequests()
+}
+
+// insideFolder reports whether a folder is the given ancestor or nested in it
+func (a *App) insideFolder(folderID, ancestorID int64) bool {
+	for folderID != 0 {
+		if folderID == ancestorID {
+			return true
+		}
+		parentID := int64(0)
+		for _, f := range a.savedFolders {
+			if f.ID == folderID {
+				parentID = f.ParentID
+			}
+		}
+		folderID = parentID
+	}
+	return false
+}
+
 // deleteRequest deletes a saved request
 func (a *App) deleteRequest(id int64) {
 	if err := a.db.DeleteRequest(id); err != nil {
@@ -367,6 +1179,13 @@ func (a *App) deleteRequest(id int64) {
 	if a.currentRequestID == id {
 		a.newRequest()
 	}
+	// Tabs still holding the request keep it as an unsaved one
+	for _, tab := range a.tabs {
+		if tab.requestID == id {
+			tab.requestID = 0
+			tab.request.ID = 0
+		}
+	}
 
 	a.loadSavedRequests()
 }
@@ -380,6 +1199,348 @@ func (a *App) loadSavedRequests() {
 	a.collections.SetRequests(requests)
 }
 
+// loadHistory loads the most recent history entries matching the search
+func (a *App) loadHistory() {
+	var (
+		entries []*storage.HistoryEntry
+		err     error
+	)
+	if query := a.history.Query(); query != "" {
+		entries, err = a.db.SearchHistory(query, historyLimit)
+	} else {
+		entries, err = a.db.GetHistory(historyLimit)
+	}
+	if err != nil {
+		return
+	}
+	a.history.SetEntries(entries)
+}
+
+// showHistoryDialog shows a history entry in full
+func (a *App) showHistoryDialog(id int64) {
+	entry, err := a.db.GetHistoryEntry(id)
+	if err != nil {
+		a.responseView.SetError(err)
+		return
+	}
+	a.historyDialog.SetEntry(entry)
+	a.pages.ShowPage("history")
+	a.tviewApp.SetFocus(a.historyDialog.Form)
+}
+
+// replayHistory loads a history entry into the request panel as a new
+// request, showing the response it got at the time
+func (a *App) replayHistory(entry *storage.HistoryEntry) {
+	a.openHistoryRequest(entry.ToHTTPRequest(), entry)
+}
+
+// saveHistory loads the request of a history entry as it was written, with
+// its {{variables}}, and asks where to save it
+func (a *App) saveHistory(entry *storage.HistoryEntry) {
+	a.openHistoryRequest(entry.Template(), entry)
+	a.showSaveDialog()
+}
+
+// openHistoryRequest loads req into the request panel as a new request,
+// showing the response the entry got at the time
+func (a *App) openHistoryRequest(req *http.Request, entry *storage.HistoryEntry) {
+	a.freeTab()
+	a.currentRequest = req
+	a.currentRequestID = 0
+	a.currentCollectionID = storage.DefaultCollectionID
+	a.currentFolderID = 0
+	a.requestPanel.SetRequest(req)
+	a.responseView.Clear()
+	a.responseView.SetFilter("")
+	a.responseView.SetResponse(entry.ToHTTPResponse())
+	a.refreshTabs()
+	a.setFocus(a.requestPanel.URLInput)
+}
+
+// showImportDialog shows the import dialog
+func (a *App) showImportDialog() {
+	a.importDialog.Reset()
+	a.pages.ShowPage("import")
+	a.tviewApp.SetFocus(a.importDialog.Form)
+}
+
+// importText imports a curl command, or the collection or OpenAPI file at the given path
+func (a *App) importText(text string) error {
+	if http.LooksLikeCurl(text) {
+		return a.importCurl(text)
+	}
+
+	path := utils.ExpandPath(text)
+	if path == "" {
+		return fmt.Errorf("paste a curl command or enter a file path")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case postman.IsCollection(data):
+		return a.importPostman(data)
+	case openapi.IsSpec(data):
+		return a.importOpenAPI(data)
+	default:
+		return fmt.Errorf("%s is not a Postman collection or OpenAPI 3 spec", filepath.Base(path))
+	}
+}
+
+// importCurl loads a curl command into the request panel as a new request
+func (a *App) importCurl(command string) error {
+	req, err := http.ParseCurl(command)
+	if err != nil {
+		return err
+	}
+
+	a.freeTab()
+	a.currentRequest = req
+	a.currentRequestID = 0
+	a.currentCollectionID = storage.DefaultCollectionID
+	a.currentFolderID = 0
+	a.requestPanel.SetRequest(req)
+	a.responseView.Clear()
+	a.responseView.SetFilter("")
+	a.refreshTabs()
+	return nil
+}
+
+// importPostman creates a collection from a Postman collection file
+func (a *App) importPostman(data []byte) error {
+	imported, err := postman.Import(data)
+	if err != nil {
+		return err
+	}
+	return a.saveImported(imported.Collection, imported.Folders, imported.Requests)
+}
+
+// importOpenAPI creates a collection with one request per operation of an
+// OpenAPI 3 spec
+func (a *App) importOpenAPI(data []byte) error {
+	imported, err := openapi.Import(data)
+	if err != nil {
+		return err
+	}
+	return a.saveImported(imported.Collection, imported.Folders, imported.Requests)
+}
+
+// saveImported stores an imported collection and refreshes the sidebar
+func (a *App) saveImported(c *storage.Collection, folders []*storage.Folder, requests []*storage.SavedRequest) error {
+	if err := a.db.ImportCollection(c, folders, requests); err != nil {
+		return err
+	}
+
+	a.loadCollections()
+	a.loadFolders()
+	a.collections.Expand(c.ID)
+	a.loadSavedRequests()
+	a.responseView.StatusBar.SetText(fmt.Sprintf("[green]Imported %d requests into %q[-]",
+		len(requests), c.Name))
+	return nil
+}
+
+// showExportDialog shows the current request as a curl command. Variables are
+// substituted when the active environment defines them all.
+func (a *App) showExportDialog() {
+	req := a.requestPanel.GetRequest()
+	if resolved, err := req.Resolve(a.activeVariables()); err == nil {
+		req = resolved
+	}
+
+	a.exportDialog.SetText(req.ToCurl())
+	if c := a.currentCollection(); c != nil {
+		a.exportDialog.SetPath(collectionFileName(c.Name))
+	}
+	a.pages.ShowPage("export")
+	a.tviewApp.SetFocus(a.exportDialog.Form)
+}
+
+// exportCollection writes the current request's collection as a Postman collection
+func (a *App) exportCollection(path string) error {
+	c := a.currentCollection()
+	if c == nil {
+		return fmt.Errorf("collection not found")
+	}
+	requests, err := a.db.GetRequestsByCollection(c.ID)
+	if err != nil {
+		return err
+	}
+	var folders []*storage.Folder
+	for _, f := range a.savedFolders {
+		if f.CollectionID == c.ID {
+			folders = append(folders, f)
+		}
+	}
+
+	data, err := postman.Export(c, folders, requests)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// showSaveResponseDialog asks where to save a response body, suggesting the
+// file name from the response in the download directory
+func (a *App) showSaveResponseDialog(resp *http.Response) {
+	req := a.requestPanel.GetRequest()
+	if resolved, err := req.Resolve(a.activeVariables()); err == nil {
+		req = resolved
+	}
+	name := http.DownloadName(resp.Headers, req.URL)
+	if dir := a.config.Download.Dir; dir != "" {
+		name = filepath.Join(dir, name)
+	}
+
+	a.saveResponseDialog.SetPath(name)
+	a.saveResponseDialog.SetOnSave(func(path string) {
+		path = utils.ExpandPath(path)
+		if err := resp.SaveBody(path); err != nil {
+			a.saveResponseDialog.SetError(err)
+			return
+		}
+		if sameFile(path, resp.BodyFile) {
+			// Saved where it was streamed to, so it's the user's now
+			delete(a.bodyFiles, resp.BodyFile)
+		}
+		a.pages.HidePage("saveResponse")
+		a.tviewApp.SetFocus(a.responseView.CurrentView())
+		a.responseView.StatusBar.SetText("[green]Saved response body to " + tview.Escape(path) + "[-]")
+	})
+	a.pages.ShowPage("saveResponse")
+	a.tviewApp.SetFocus(a.saveResponseDialog.Form)
+}
+
+// collectionFileName returns the conventional Postman file name for a collection
+func collectionFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	return name + ".postman_collection.json"
+}
+
+// sameFile reports whether two paths name the same existing file
+func sameFile(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	aInfo, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	bInfo, err := os.Stat(b)
+	return err == nil && os.SameFile(aInfo, bInfo)
+}
+
+// showEnvironmentDialog shows the environment manager
+func (a *App) showEnvironmentDialog() {
+	a.envDialog.SetEnvironments(a.environments)
+	a.pages.ShowPage("environments")
+	a.tviewApp.SetFocus(a.envDialog.List)
+}
+
+// saveEnvironment saves an environment and refreshes the switcher
+func (a *App) saveEnvironment(env *storage.Environment) {
+	// Keep the active environment selected if it was renamed
+	if active := a.envSelect.Active(); active != nil && active.ID == env.ID && env.ID != 0 {
+		a.config.ActiveEnvironment = env.Name
+		_ = storage.SaveConfig(a.config)
+	}
+
+	if err := a.db.SaveEnvironment(env); err != nil {
+		return
+	}
+
+	a.loadEnvironments()
+	a.envDialog.SetEnvironments(a.environments)
+}
+
+// deleteEnvironment deletes an environment
+func (a *App) deleteEnvironment(id int64) {
+	if err := a.db.DeleteEnvironment(id); err != nil {
+		return
+	}
+
+	a.loadEnvironments()
+	a.envDialog.SetEnvironments(a.environments)
+}
+
+// setActiveEnvironment switches the environment used for variable substitution
+func (a *App) setActiveEnvironment(env *storage.Environment) {
+	name := ""
+	if env != nil {
+		name = env.Name
+	}
+	if name == a.config.ActiveEnvironment {
+		return
+	}
+
+	a.config.ActiveEnvironment = name
+	_ = storage.SaveConfig(a.config)
+}
+
+// activeVariables returns the variables of the current collection, overridden
+// by those of the active environment
+func (a *App) activeVariables() map[string]string {
+	vars := make(map[string]string)
+	if c := a.currentCollection(); c != nil {
+		for name, value := range c.Variables {
+			vars[name] = value
+		}
+	}
+	if env := a.envSelect.Active(); env != nil {
+		for name, value := range env.Variables {
+			vars[name] = value
+		}
+	}
+	return vars
+}
+
+// currentCollection returns the collection of the current request
+func (a *App) currentCollection() *storage.Collection {
+	for _, c := range a.savedCollections {
+		if c.ID == a.currentCollectionID {
+			return c
+		}
+	}
+	return nil
+}
+
+// loadCollections loads all collections
+func (a *App) loadCollections() {
+	collections, err := a.db.GetCollections()
+	if err != nil {
+		return
+	}
+	a.savedCollections = collections
+	a.collections.SetCollections(collections)
+}
+
+// loadFolders loads the folders of all collections
+func (a *App) loadFolders() {
+	folders, err := a.db.GetFolders()
+	if err != nil {
+		return
+	}
+	a.savedFolders = folders
+	a.collections.SetFolders(folders)
+}
+
+// loadEnvironments loads all environments into the switcher
+func (a *App) loadEnvironments() {
+	envs, err := a.db.GetEnvironments()
+	if err != nil {
+		return
+	}
+	a.environments = envs
+	a.envSelect.SetEnvironments(envs, a.config.ActiveEnvironment)
+}
+
 // Run starts the application
 func (a *App) Run() error {
 	return a.tviewApp.Run()
@@ -387,6 +1548,9 @@ func (a *App) Run() error {
 
 // Stop stops the application
 func (a *App) Stop() {
+	for path := range a.bodyFiles {
+		os.Remove(path)
+	}
 	if a.db != nil {
 		a.db.Close()
 	}
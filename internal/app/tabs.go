@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/YashIIT0909/TRexT/internal/components"
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+)
+
+// requestTab is an open request. The current tab is shown in the request
+// panel and tracked by the App's current* fields; the others keep their
+// state here until they are shown again.
+type requestTab struct {
+	request      *http.Request
+	requestID    int64
+	collectionID int64
+	folderID     int64
+	response     *http.Response
+	sending      *sending // the request in flight, if any
+}
+
+// sending is a request in flight from a tab
+type sending struct {
+	cancel  context.CancelFunc
+	started time.Time
+
+	// Bytes of the response body received, and its size or -1 if unknown
+	received atomic.Int64
+	total    atomic.Int64
+}
+
+// newRequestTab returns a tab holding an empty request
+func newRequestTab() *requestTab {
+	return &requestTab{
+		request:      http.NewRequest(),
+		collectionID: storage.DefaultCollectionID,
+	}
+}
+
+// stashTab stores the request and response of the current tab
+func (a *App) stashTab() {
+	tab := a.tabs[a.currentTab]
+	req := a.requestPanel.GetRequest()
+	req.Name = a.currentRequest.Name
+	req.ID = a.currentRequestID
+	req.Filter = a.responseView.Filter()
+	tab.request = req
+	tab.requestID = a.currentRequestID
+	tab.collectionID = a.currentCollectionID
+	tab.folderID = a.currentFolderID
+	tab.response = a.responseView.Response()
+}
+
+// restoreTab shows the current tab in the request panel and response view
+func (a *App) restoreTab() {
+	tab := a.tabs[a.currentTab]
+	a.currentRequest = tab.request
+	a.currentRequestID = tab.requestID
+	a.currentCollectionID = tab.collectionID
+	a.currentFolderID = tab.folderID
+	a.requestPanel.SetRequest(tab.request)
+	a.responseView.Clear()
+	a.responseView.SetFilter(tab.request.Filter)
+	if tab.response != nil {
+		a.responseView.SetResponse(tab.response)
+	}
+	if tab.sending != nil {
+		a.responseView.SetSending(time.Since(tab.sending.started), tab.sending.received.Load(), tab.sending.total.Load())
+	}
+	a.refreshTabs()
+}
+
+// showTab switches to the tab at index
+func (a *App) showTab(index int) {
+	if index == a.currentTab || index < 0 || index >= len(a.tabs) {
+		return
+	}
+	a.stashTab()
+	a.currentTab = index
+	a.restoreTab()
+}
+
+// nextTab switches to the tab on the right, or on the left for delta -1,
+// wrapping around
+func (a *App) nextTab(delta int) {
+	a.showTab((a.currentTab + delta + len(a.tabs)) % len(a.tabs))
+}
+
+// newTab opens an empty request in a new tab
+func (a *App) newTab() {
+	a.stashTab()
+	a.tabs = append(a.tabs, newRequestTab())
+	a.currentTab = len(a.tabs) - 1
+	a.restoreTab()
+}
+
+// freeTab opens a new tab when the current one is waiting for a response, so
+// loading another request doesn't take over the tab it reports back to
+func (a *App) freeTab() {
+	if a.tabs[a.currentTab].sending != nil {
+		a.newTab()
+	}
+}
+
+// closeTab closes the current tab, cancelling its request in flight. Closing
+// the last tab leaves an empty one.
+func (a *App) closeTab() {
+	if tab := a.tabs[a.currentTab]; tab.sending != nil {
+		tab.sending.cancel()
+		tab.sending = nil
+	}
+
+	a.tabs = append(a.tabs[:a.currentTab], a.tabs[a.currentTab+1:]...)
+	if len(a.tabs) == 0 {
+		a.tabs = append(a.tabs, newRequestTab())
+	}
+	if a.currentTab >= len(a.tabs) {
+		a.currentTab = len(a.tabs) - 1
+	}
+	a.restoreTab()
+}
+
+// findTab returns the index of the tab holding a saved request, or -1
+func (a *App) findTab(requestID int64) int {
+	if requestID == 0 {
+		return -1
+	}
+	for i, tab := range a.tabs {
+		id := tab.requestID
+		if i == a.currentTab {
+			id = a.currentRequestID
+		}
+		if id == requestID {
+			return i
+		}
+	}
+	return -1
+}
+
+// refreshTabs updates the tab strip; the current tab is labelled from the
+// request panel. Streamed bodies no tab shows any more are removed.
+func (a *App) refreshTabs() {
+	tabs := make([]components.RequestTab, len(a.tabs))
+	for i, tab := range a.tabs {
+		req := tab.request
+		if i == a.currentTab {
+			req = a.requestPanel.GetRequest()
+			req.Name = a.currentRequest.Name
+		}
+		tabs[i] = components.RequestTab{Request: req, Sending: tab.sending != nil}
+	}
+	a.requestTabs.SetTabs(tabs, a.currentTab)
+	a.removeBodyFiles()
+}
+
+// removeBodyFiles removes the streamed bodies in the temp directory whose
+// responses were replaced or closed with their tab
+func (a *App) removeBodyFiles() {
+	if len(a.bodyFiles) == 0 {
+		return
+	}
+	shown := make(map[string]bool)
+	for i, tab := range a.tabs {
+		resp := tab.response
+		if i == a.currentTab {
+			resp = a.responseView.Response()
+		}
+		if resp != nil && resp.BodyFile != "" {
+			shown[resp.BodyFile] = true
+		}
+	}
+	for path := range a.bodyFiles {
+		if !shown[path] {
+			os.Remove(path)
+			delete(a.bodyFiles, path)
+		}
+	}
+}
+
+// cancelRequest aborts the request in flight from the current tab, reporting
+// whether there was one
+func (a *App) cancelRequest() bool {
+	tab := a.tabs[a.currentTab]
+	if tab.sending == nil {
+		return false
+	}
+	tab.sending.cancel()
+	return true
+}
+
+// tickSending shows the elapsed time of a request in flight while its tab is
+// the current one, until ctx is done
+func (a *App) tickSending(ctx context.Context, tab *requestTab, s *sending) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.tviewApp.QueueUpdateDraw(func() {
+				if tab.sending == s && a.tabs[a.currentTab] == tab {
+					a.responseView.SetSending(time.Since(s.started), s.received.Load(), s.total.Load())
+				}
+			})
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"github.com/YashIIT0909/TRexT/internal/components"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -18,6 +19,7 @@ type Theme struct {
 	Error      tcell.Color
 	SelectedBg tcell.Color
 	SelectedFg tcell.Color
+	Syntax     components.SyntaxColors
 }
 
 // DefaultTheme returns the default color theme
@@ -34,6 +36,16 @@ func DefaultTheme() *Theme {
 		Error:      tcell.ColorRed,
 		SelectedBg: tcell.ColorDarkCyan,
 		SelectedFg: tcell.ColorWhite,
+		Syntax: components.SyntaxColors{
+			Key:       tcell.ColorDarkCyan,
+			String:    tcell.ColorGreen,
+			Number:    tcell.ColorOrange,
+			Keyword:   tcell.ColorFuchsia,
+			Tag:       tcell.ColorDodgerBlue,
+			Attribute: tcell.ColorDarkCyan,
+			Comment:   tcell.ColorGray,
+			Match:     tcell.ColorOlive,
+		},
 	}
 }
 
@@ -51,6 +63,16 @@ func DraculaTheme() *Theme {
 		Error:      tcell.NewRGBColor(255, 85, 85),
 		SelectedBg: tcell.NewRGBColor(68, 71, 90),
 		SelectedFg: tcell.NewRGBColor(248, 248, 242),
+		Syntax: components.SyntaxColors{
+			Key:       tcell.NewRGBColor(139, 233, 253),
+			String:    tcell.NewRGBColor(241, 250, 140),
+			Number:    tcell.NewRGBColor(189, 147, 249),
+			Keyword:   tcell.NewRGBColor(255, 121, 198),
+			Tag:       tcell.NewRGBColor(255, 121, 198),
+			Attribute: tcell.NewRGBColor(80, 250, 123),
+			Comment:   tcell.NewRGBColor(98, 114, 164),
+			Match:     tcell.NewRGBColor(68, 71, 90),
+		},
 	}
 }
 
@@ -69,6 +91,7 @@ func ApplyTheme(theme *Theme) {
 		InverseTextColor:            theme.Background,
 		ContrastSecondaryTextColor:  theme.SelectedFg,
 	}
+	components.SetSyntaxColors(theme.Syntax)
 }
 
 // GetTheme returns a theme by name
@@ -0,0 +1,275 @@
+package components
+
+import (
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// authTypes lists the auth modes in the order shown in the type selector
+var authTypes = []struct {
+	Type  http.AuthType
+	Label string
+}{
+	{http.AuthNone, "No Auth"},
+	{http.AuthBasic, "Basic"},
+	{http.AuthBearer, "Bearer Token"},
+	{http.AuthAPIKey, "API Key"},
+	{http.AuthDigest, "Digest"},
+	{http.AuthOAuth2, "OAuth 2.0"},
+}
+
+// grantTypes lists the OAuth 2.0 grants in the order shown in the grant selector
+var grantTypes = []struct {
+	Grant string
+	Label string
+}{
+	{http.GrantClientCredentials, "Client Credentials"},
+	{http.GrantPassword, "Password"},
+	{http.GrantAuthorizationCode, "Authorization Code (PKCE)"},
+}
+
+// apiKeyLocations lists where an API key can be sent
+var apiKeyLocations = []string{"Header", "Query Param"}
+
+// AuthForm represents the request authentication editor. Only the fields
+// used by the selected auth type are shown.
+type AuthForm struct {
+	Form          *tview.Form
+	typeSelect    *tview.DropDown
+	usernameInput *tview.InputField
+	passwordInput *tview.InputField
+	tokenInput    *tview.InputField
+	keyInput      *tview.InputField
+	valueInput    *tview.InputField
+	inSelect      *tview.DropDown
+	authType      http.AuthType
+
+	// OAuth 2.0 fields
+	grantSelect       *tview.DropDown
+	tokenURLInput     *tview.InputField
+	authURLInput      *tview.InputField
+	redirectURLInput  *tview.InputField
+	clientIDInput     *tview.InputField
+	clientSecretInput *tview.InputField
+	scopeInput        *tview.InputField
+	grantType         string
+
+	onLayoutChange func()
+}
+
+// NewAuthForm creates a new auth editor
+func NewAuthForm() *AuthForm {
+	af := &AuthForm{}
+	af.build()
+	return af
+}
+
+func (af *AuthForm) build() {
+	labels := make([]string, len(authTypes))
+	for i, t := range authTypes {
+		labels[i] = t.Label
+	}
+
+	af.typeSelect = newFormDropDown("Type: ", labels)
+	af.usernameInput = tview.NewInputField().
+		SetLabel("Username: ").
+		SetFieldWidth(0)
+	af.passwordInput = tview.NewInputField().
+		SetLabel("Password: ").
+		SetFieldWidth(0).
+		SetMaskCharacter('*')
+	af.tokenInput = tview.NewInputField().
+		SetLabel("Token: ").
+		SetPlaceholder("{{token}}").
+		SetFieldWidth(0)
+	af.keyInput = tview.NewInputField().
+		SetLabel("Key: ").
+		SetPlaceholder("X-API-Key").
+		SetFieldWidth(0)
+	af.valueInput = tview.NewInputField().
+		SetLabel("Value: ").
+		SetFieldWidth(0)
+	af.inSelect = newFormDropDown("Add to: ", apiKeyLocations)
+
+	grantLabels := make([]string, len(grantTypes))
+	for i, g := range grantTypes {
+		grantLabels[i] = g.Label
+	}
+	af.grantSelect = newFormDropDown("Grant: ", grantLabels)
+	af.grantType = grantTypes[0].Grant
+	af.tokenURLInput = tview.NewInputField().
+		SetLabel("Token URL: ").
+		SetPlaceholder("https://auth.example.com/oauth/token").
+		SetFieldWidth(0)
+	af.authURLInput = tview.NewInputField().
+		SetLabel("Auth URL: ").
+		SetPlaceholder("https://auth.example.com/authorize").
+		SetFieldWidth(0)
+	af.redirectURLInput = tview.NewInputField().
+		SetLabel("Redirect URL: ").
+		SetPlaceholder("http://127.0.0.1:0/callback (any free port)").
+		SetFieldWidth(0)
+	af.clientIDInput = tview.NewInputField().
+		SetLabel("Client ID: ").
+		SetFieldWidth(0)
+	af.clientSecretInput = tview.NewInputField().
+		SetLabel("Client Secret: ").
+		SetFieldWidth(0).
+		SetMaskCharacter('*')
+	af.scopeInput = tview.NewInputField().
+		SetLabel("Scope: ").
+		SetFieldWidth(0)
+
+	af.Form = tview.NewForm()
+	af.Form.SetBorder(true).
+		SetTitle(" Auth ").
+		SetTitleAlign(tview.AlignLeft)
+
+	af.layout()
+
+	// Set the callbacks last so building the form doesn't trigger them
+	af.typeSelect.SetSelectedFunc(func(text string, index int) {
+		if authTypes[index].Type != af.authType {
+			af.authType = authTypes[index].Type
+			af.layout()
+		}
+	})
+	af.grantSelect.SetSelectedFunc(func(text string, index int) {
+		if grantTypes[index].Grant != af.grantType {
+			af.grantType = grantTypes[index].Grant
+			af.layout()
+		}
+	})
+}
+
+// layout shows the fields used by the selected auth type
+func (af *AuthForm) layout() {
+	af.Form.Clear(false)
+	for _, item := range af.items() {
+		af.Form.AddFormItem(item)
+	}
+	if af.onLayoutChange != nil {
+		af.onLayoutChange()
+	}
+}
+
+// items returns the form items used by the selected auth type
+func (af *AuthForm) items() []tview.FormItem {
+	items := []tview.FormItem{af.typeSelect}
+	switch af.authType {
+	case http.AuthBasic, http.AuthDigest:
+		items = append(items, af.usernameInput, af.passwordInput)
+	case http.AuthBearer:
+		items = append(items, af.tokenInput)
+	case http.AuthAPIKey:
+		items = append(items, af.keyInput, af.valueInput, af.inSelect)
+	case http.AuthOAuth2:
+		items = append(items, af.grantSelect)
+		if af.grantType == http.GrantAuthorizationCode {
+			items = append(items, af.authURLInput, af.redirectURLInput)
+		}
+		items = append(items, af.tokenURLInput, af.clientIDInput, af.clientSecretInput, af.scopeInput)
+		if af.grantType == http.GrantPassword {
+			items = append(items, af.usernameInput, af.passwordInput)
+		}
+	}
+	return items
+}
+
+// GetFocusableItems returns the visible form items in order
+func (af *AuthForm) GetFocusableItems() []tview.Primitive {
+	var primitives []tview.Primitive
+	for _, item := range af.items() {
+		primitives = append(primitives, item)
+	}
+	return primitives
+}
+
+// SetOnLayoutChange sets the callback for when the visible fields change
+func (af *AuthForm) SetOnLayoutChange(fn func()) {
+	af.onLayoutChange = fn
+}
+
+// GetAuth returns the credentials entered in the form
+func (af *AuthForm) GetAuth() http.Auth {
+	auth := http.Auth{Type: af.authType}
+	switch af.authType {
+	case http.AuthBasic, http.AuthDigest:
+		auth.Username = af.usernameInput.GetText()
+		auth.Password = af.passwordInput.GetText()
+	case http.AuthBearer:
+		auth.Token = af.tokenInput.GetText()
+	case http.AuthAPIKey:
+		auth.Key = af.keyInput.GetText()
+		auth.Value = af.valueInput.GetText()
+		auth.In = http.APIKeyInHeader
+		if index, _ := af.inSelect.GetCurrentOption(); index == 1 {
+			auth.In = http.APIKeyInQuery
+		}
+	case http.AuthOAuth2:
+		auth.GrantType = af.grantType
+		auth.TokenURL = af.tokenURLInput.GetText()
+		auth.ClientID = af.clientIDInput.GetText()
+		auth.ClientSecret = af.clientSecretInput.GetText()
+		auth.Scope = af.scopeInput.GetText()
+		switch af.grantType {
+		case http.GrantAuthorizationCode:
+			auth.AuthURL = af.authURLInput.GetText()
+			auth.RedirectURL = af.redirectURLInput.GetText()
+		case http.GrantPassword:
+			auth.Username = af.usernameInput.GetText()
+			auth.Password = af.passwordInput.GetText()
+		}
+	}
+	return auth
+}
+
+// SetAuth populates the form
+func (af *AuthForm) SetAuth(auth http.Auth) {
+	af.usernameInput.SetText(auth.Username)
+	af.passwordInput.SetText(auth.Password)
+	af.tokenInput.SetText(auth.Token)
+	af.keyInput.SetText(auth.Key)
+	af.valueInput.SetText(auth.Value)
+	if auth.In == http.APIKeyInQuery {
+		af.inSelect.SetCurrentOption(1)
+	} else {
+		af.inSelect.SetCurrentOption(0)
+	}
+	af.tokenURLInput.SetText(auth.TokenURL)
+	af.authURLInput.SetText(auth.AuthURL)
+	af.redirectURLInput.SetText(auth.RedirectURL)
+	af.clientIDInput.SetText(auth.ClientID)
+	af.clientSecretInput.SetText(auth.ClientSecret)
+	af.scopeInput.SetText(auth.Scope)
+
+	af.grantType = grantTypes[0].Grant
+	af.grantSelect.SetCurrentOption(0)
+	for i, g := range grantTypes {
+		if g.Grant == auth.GrantType {
+			af.grantSelect.SetCurrentOption(i)
+			af.grantType = g.Grant
+		}
+	}
+
+	for i, t := range authTypes {
+		if t.Type == auth.Type {
+			af.typeSelect.SetCurrentOption(i)
+		}
+	}
+	af.authType = auth.Type
+	af.layout()
+}
+
+// newFormDropDown creates a drop-down styled like the method selector
+func newFormDropDown(label string, options []string) *tview.DropDown {
+	return tview.NewDropDown().
+		SetLabel(label).
+		SetOptions(options, nil).
+		SetCurrentOption(0).
+		SetListStyles(
+			tcell.StyleDefault.Background(tcell.ColorDefault).Foreground(tcell.ColorWhite),
+			tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite),
+		)
+}
@@ -0,0 +1,380 @@
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/graphql"
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/rivo/tview"
+)
+
+// bodyTypes lists the body types in the order shown in the type selector
+var bodyTypes = []struct {
+	Type  http.BodyType
+	Label string
+}{
+	{http.BodyNone, "None"},
+	{http.BodyJSON, "JSON"},
+	{http.BodyText, "Text"},
+	{http.BodyXML, "XML"},
+	{http.BodyGraphQL, "GraphQL"},
+	{http.BodyURLEncoded, "Form URL-Encoded"},
+	{http.BodyMultipart, "Multipart Form"},
+	{http.BodyBinary, "Binary File"},
+}
+
+// bodyPlaceholders hint at the content of the text body types
+var bodyPlaceholders = map[http.BodyType]string{
+	http.BodyJSON: `{"key": "value"}`,
+	http.BodyText: "Plain text",
+	http.BodyXML:  `<?xml version="1.0"?>`,
+}
+
+// BodyEditor represents the request body editor. The editor shown depends
+// on the body type: text for JSON, text and XML, a table of fields for forms,
+// a path for binary files, and the query and variables of GraphQL operations.
+type BodyEditor struct {
+	Container  *tview.Flex
+	TypeSelect *tview.DropDown
+	TextInput  *tview.TextArea
+	FormEditor *KeyValueEditor
+	FileForm   *tview.Form
+	fileInput  *tview.InputField
+	pages      *tview.Pages
+	bodyType   http.BodyType
+
+	// GraphQL editor
+	QueryInput     *tview.TextArea
+	VariablesInput *tview.TextArea
+	SchemaButton   *tview.Button
+	operationInput *tview.InputField
+	schemaView     *tview.TextView
+	schemaStatus   string                 // progress or failure of fetching the schema
+	schemaSource   func() *graphql.Schema // the cached schema of the endpoint
+
+	onLayoutChange func()
+	onTypeChange   func(bodyType http.BodyType)
+	onFetchSchema  func()
+}
+
+// NewBodyEditor creates a new body editor
+func NewBodyEditor() *BodyEditor {
+	be := &BodyEditor{}
+	be.build()
+	return be
+}
+
+func (be *BodyEditor) build() {
+	labels := make([]string, len(bodyTypes))
+	for i, t := range bodyTypes {
+		labels[i] = t.Label
+	}
+	be.TypeSelect = newFormDropDown("Type: ", labels)
+
+	be.TextInput = tview.NewTextArea()
+	be.TextInput.SetBorder(true).
+		SetTitle(" Body ").
+		SetTitleAlign(tview.AlignLeft)
+
+	// Url-encoded and multipart fields
+	be.FormEditor = NewKeyValueEditor("Form Fields")
+	be.FormEditor.SetOnLayoutChange(be.notifyLayoutChange)
+
+	be.fileInput = tview.NewInputField().
+		SetLabel("File: ").
+		SetPlaceholder("~/uploads/image.png").
+		SetFieldWidth(0)
+	be.FileForm = tview.NewForm().
+		AddFormItem(be.fileInput)
+	be.FileForm.SetBorder(true).
+		SetTitle(" Binary File ").
+		SetTitleAlign(tview.AlignLeft)
+
+	be.buildGraphQL()
+
+	none := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[gray]This request has no body[-]")
+	none.SetBorder(true).
+		SetTitle(" Body ").
+		SetTitleAlign(tview.AlignLeft)
+
+	be.pages = tview.NewPages().
+		AddPage("none", none, true, false).
+		AddPage("text", be.TextInput, true, true).
+		AddPage("form", be.FormEditor.Container, true, false).
+		AddPage("file", be.FileForm, true, false).
+		AddPage("graphql", be.graphQLPage(), true, false)
+
+	be.Container = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(be.TypeSelect, 1, 0, false).
+		AddItem(be.pages, 0, 1, false)
+
+	be.TypeSelect.SetCurrentOption(be.typeIndex(http.BodyJSON))
+	be.layout()
+
+	// Set the callback last so building the editor doesn't trigger it
+	be.TypeSelect.SetSelectedFunc(func(text string, index int) {
+		if bodyTypes[index].Type != be.bodyType {
+			be.bodyType = bodyTypes[index].Type
+			be.layout()
+			if be.onTypeChange != nil {
+				be.onTypeChange(be.bodyType)
+			}
+		}
+	})
+}
+
+// buildGraphQL builds the editors of GraphQL operations
+func (be *BodyEditor) buildGraphQL() {
+	be.QueryInput = tview.NewTextArea().
+		SetPlaceholder("query { ... }")
+	be.QueryInput.SetBorder(true).
+		SetTitle(" Query ").
+		SetTitleAlign(tview.AlignLeft)
+
+	be.VariablesInput = tview.NewTextArea().
+		SetPlaceholder(`{"id": "1"}`)
+	be.VariablesInput.SetBorder(true).
+		SetTitle(" Variables ").
+		SetTitleAlign(tview.AlignLeft)
+
+	be.operationInput = tview.NewInputField().
+		SetLabel("Operation: ").
+		SetFieldWidth(0)
+
+	be.SchemaButton = tview.NewButton("Fetch Schema").
+		SetSelectedFunc(func() {
+			if be.onFetchSchema != nil {
+				be.schemaStatus = "[gray]Fetching schema… (Esc to cancel)[-]"
+				be.refreshSchema()
+				be.onFetchSchema()
+			}
+		})
+
+	// Validation errors and the fields that can be selected at the cursor
+	be.schemaView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+	be.schemaView.SetBorder(true).
+		SetTitle(" Schema ").
+		SetTitleAlign(tview.AlignLeft)
+
+	be.QueryInput.SetChangedFunc(be.refreshSchema)
+	be.QueryInput.SetMovedFunc(be.refreshSchema)
+	be.VariablesInput.SetChangedFunc(be.refreshSchema)
+	be.operationInput.SetChangedFunc(func(string) { be.refreshSchema() })
+}
+
+// graphQLPage lays out the GraphQL editors
+func (be *BodyEditor) graphQLPage() *tview.Flex {
+	operationRow := tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(be.operationInput, 0, 1, false).
+		AddItem(tview.NewBox(), 1, 0, false).
+		AddItem(be.SchemaButton, 14, 0, false)
+
+	return tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(be.QueryInput, 0, 3, false).
+		AddItem(be.VariablesInput, 0, 2, false).
+		AddItem(operationRow, 1, 0, false).
+		AddItem(be.schemaView, 0, 2, false)
+}
+
+// typeIndex returns the position of a body type in the type selector
+func (be *BodyEditor) typeIndex(bodyType http.BodyType) int {
+	for i, t := range bodyTypes {
+		if t.Type == bodyType {
+			return i
+		}
+	}
+	return be.typeIndex(http.BodyJSON)
+}
+
+// layout shows the editor of the selected body type
+func (be *BodyEditor) layout() {
+	switch be.bodyType {
+	case http.BodyNone:
+		be.pages.SwitchToPage("none")
+	case http.BodyURLEncoded, http.BodyMultipart:
+		be.FormEditor.SetFiles(be.bodyType == http.BodyMultipart)
+		be.pages.SwitchToPage("form")
+	case http.BodyBinary:
+		be.pages.SwitchToPage("file")
+	case http.BodyGraphQL:
+		be.refreshSchema()
+		be.pages.SwitchToPage("graphql")
+	default:
+		be.TextInput.SetPlaceholder(bodyPlaceholders[be.bodyType])
+		be.pages.SwitchToPage("text")
+	}
+	be.notifyLayoutChange()
+}
+
+func (be *BodyEditor) notifyLayoutChange() {
+	if be.onLayoutChange != nil {
+		be.onLayoutChange()
+	}
+}
+
+// SetOnLayoutChange sets the callback for when the editor shown, or its
+// focusable items, change
+func (be *BodyEditor) SetOnLayoutChange(fn func()) {
+	be.onLayoutChange = fn
+}
+
+// SetOnTypeChange sets the callback for when another body type is selected
+func (be *BodyEditor) SetOnTypeChange(fn func(bodyType http.BodyType)) {
+	be.onTypeChange = fn
+}
+
+// SetOnFetchSchema sets the callback for when the GraphQL schema of the
+// endpoint is asked for. SetSchemaResult reports how fetching it went.
+func (be *BodyEditor) SetOnFetchSchema(fn func()) {
+	be.onFetchSchema = fn
+}
+
+// SetSchemaSource sets the function returning the cached GraphQL schema of
+// the endpoint, used to check the query and list fields
+func (be *BodyEditor) SetSchemaSource(fn func() *graphql.Schema) {
+	be.schemaSource = fn
+}
+
+// SetSchemaResult shows the outcome of fetching the schema: the schema, now
+// cached, is used when err is nil
+func (be *BodyEditor) SetSchemaResult(err error) {
+	be.schemaStatus = ""
+	if errors.Is(err, context.Canceled) {
+		be.schemaStatus = "[yellow]Fetching the schema was cancelled[-]"
+	} else if err != nil {
+		be.schemaStatus = fmt.Sprintf("[red]Couldn't fetch the schema: %s[-]", tview.Escape(err.Error()))
+	}
+	be.refreshSchema()
+}
+
+// SetOnFocus sets the callback used to move focus within the form fields table
+func (be *BodyEditor) SetOnFocus(fn func(p tview.Primitive)) {
+	be.FormEditor.SetOnFocus(fn)
+}
+
+// GetFocusableItems returns the type selector and the items of the editor shown
+func (be *BodyEditor) GetFocusableItems() []tview.Primitive {
+	items := []tview.Primitive{be.TypeSelect}
+	switch be.bodyType {
+	case http.BodyNone:
+	case http.BodyURLEncoded, http.BodyMultipart:
+		items = append(items, be.FormEditor.GetFocusableItems()...)
+	case http.BodyBinary:
+		items = append(items, be.fileInput)
+	case http.BodyGraphQL:
+		items = append(items, be.QueryInput, be.VariablesInput, be.operationInput, be.SchemaButton, be.schemaView)
+	default:
+		items = append(items, be.TextInput)
+	}
+	return items
+}
+
+// schema returns the cached schema of the endpoint, if it has been fetched
+func (be *BodyEditor) schema() *graphql.Schema {
+	if be.schemaSource == nil {
+		return nil
+	}
+	return be.schemaSource()
+}
+
+// graphQL returns the GraphQL operation being edited
+func (be *BodyEditor) graphQL() http.GraphQL {
+	return http.GraphQL{
+		Query:         be.QueryInput.GetText(),
+		Variables:     be.VariablesInput.GetText(),
+		OperationName: strings.TrimSpace(be.operationInput.GetText()),
+	}
+}
+
+// refreshSchema checks the GraphQL operation and lists the fields that can
+// be selected where the cursor is in the query, or those of the query type
+// outside any selection
+func (be *BodyEditor) refreshSchema() {
+	if be.bodyType != http.BodyGraphQL {
+		return
+	}
+
+	var b strings.Builder
+	op := be.graphQL()
+	schema := be.schema()
+	if strings.TrimSpace(op.Query) != "" {
+		for _, err := range graphql.Validate(schema, op) {
+			fmt.Fprintf(&b, "[red]%s[-]\n", tview.Escape(err.Error()))
+		}
+	}
+	if be.schemaStatus != "" {
+		b.WriteString(be.schemaStatus + "\n")
+	}
+
+	if schema == nil {
+		if be.schemaStatus == "" {
+			b.WriteString("[gray]No schema yet: select Fetch Schema to introspect the endpoint[-]\n")
+		}
+		be.schemaView.SetText(b.String())
+		return
+	}
+
+	_, cursor, _ := be.QueryInput.GetSelection()
+	t := schema.TypeAt(op.Query, cursor)
+	if t == nil {
+		t = schema.Types[schema.QueryType]
+	}
+	if t != nil {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[yellow]%s[-]\n", tview.Escape(t.Name))
+		for _, f := range t.Fields {
+			if f.IsDeprecated {
+				fmt.Fprintf(&b, "  [gray]%s (deprecated)[-]\n", tview.Escape(f.String()))
+				continue
+			}
+			fmt.Fprintf(&b, "  %s\n", tview.Escape(f.String()))
+		}
+		if len(t.Fields) == 0 && len(t.PossibleTypes) > 0 {
+			names := make([]string, len(t.PossibleTypes))
+			for i, p := range t.PossibleTypes {
+				names[i] = p.Name
+			}
+			fmt.Fprintf(&b, "  [gray]one of[-] %s\n", tview.Escape(strings.Join(names, ", ")))
+		}
+	}
+	be.schemaView.SetText(b.String())
+}
+
+// GetBody sets the body of a request from the editor. The text, fields and
+// file are all kept so switching the type back doesn't lose them.
+func (be *BodyEditor) GetBody(req *http.Request) {
+	req.BodyType = be.bodyType
+	req.Body = be.TextInput.GetText()
+	req.Form = be.FormEditor.Rows()
+	req.BodyFile = strings.TrimSpace(be.fileInput.GetText())
+	req.GraphQL = be.graphQL()
+}
+
+// SetBody populates the editor from the body of a request
+func (be *BodyEditor) SetBody(req *http.Request) {
+	be.TextInput.SetText(req.Body, true)
+	be.FormEditor.SetRows(req.Form)
+	be.fileInput.SetText(req.BodyFile)
+	be.QueryInput.SetText(req.GraphQL.Query, false)
+	be.VariablesInput.SetText(req.GraphQL.Variables, false)
+	be.operationInput.SetText(req.GraphQL.OperationName)
+
+	// The type is set before the selector so loading a request isn't taken
+	// for a change of type
+	index := be.typeIndex(req.BodyType)
+	be.bodyType = bodyTypes[index].Type
+	be.TypeSelect.SetCurrentOption(index)
+	be.layout()
+}
@@ -9,147 +9,422 @@ import (
 	"github.com/rivo/tview"
 )
 
-// CollectionsList represents the sidebar with saved requests
+// CollectionsList represents the sidebar tree of collections, their folders
+// and saved requests
 type CollectionsList struct {
-	Container *tview.Flex
-	List      *tview.List
-	requests  []*storage.SavedRequest
+	Container       *tview.Flex
+	Tree            *tview.TreeView
+	root            *tview.TreeNode
+	collections     []*storage.Collection
+	folders         []*storage.Folder
+	requests        []*storage.SavedRequest
+	expanded        map[int64]bool // collections shown open, by ID
+	expandedFolders map[int64]bool // folders shown open, by ID
 
-	onSelect func(req *http.Request)
-	onNew    func()
-	onDelete func(id int64)
+	onSelect           func(req *http.Request, collectionID, folderID int64)
+	onNew              func(collectionID, folderID int64)
+	onDelete           func(id int64)
+	onToggleHistory    func(collectionID int64)
+	onNewCollection    func()
+	onRenameCollection func(c *storage.Collection)
+	onDeleteCollection func(c *storage.Collection)
+	onNewFolder        func(collectionID, parentID int64)
+	onRenameFolder     func(f *storage.Folder)
+	onDeleteFolder     func(f *storage.Folder, folders, requests int)
+	onRenameRequest    func(req *storage.SavedRequest)
+	onMoveRequest      func(req *storage.SavedRequest)
+	onCopyRequest      func(req *storage.SavedRequest)
 }
 
+// newRequestNode is the reference of the "+ New Request" item
+type newRequestNode struct{}
+
 // NewCollectionsList creates a new collections list
 func NewCollectionsList() *CollectionsList {
 	cl := &CollectionsList{
-		requests: make([]*storage.SavedRequest, 0),
+		requests:        make([]*storage.SavedRequest, 0),
+		expanded:        make(map[int64]bool),
+		expandedFolders: make(map[int64]bool),
 	}
 	cl.build()
 	return cl
 }
 
 func (cl *CollectionsList) build() {
-	cl.List = tview.NewList().
-		ShowSecondaryText(true).
-		SetHighlightFullLine(true).
-		SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+	cl.root = tview.NewTreeNode("")
+	cl.Tree = tview.NewTreeView().
+		SetRoot(cl.root).
+		SetTopLevel(1).
+		SetGraphics(false)
 
-	cl.List.SetBorder(true).
+	cl.Tree.SetBorder(true).
 		SetTitle(" Collections ").
 		SetTitleAlign(tview.AlignLeft)
 
-	// Add "New Request" item at the top
-	cl.List.AddItem("+ New Request", "Create a new request", 'n', func() {
-		if cl.onNew != nil {
-			cl.onNew()
+	// Enter opens a request, or expands or collapses a collection or folder
+	cl.Tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		switch ref := node.GetReference().(type) {
+		case newRequestNode:
+			if cl.onNew != nil {
+				cl.onNew(storage.DefaultCollectionID, 0)
+			}
+		case *storage.Collection:
+			cl.expanded[ref.ID] = !cl.expanded[ref.ID]
+			cl.refresh()
+		case *storage.Folder:
+			cl.expandedFolders[ref.ID] = !cl.expandedFolders[ref.ID]
+			cl.refresh()
+		case *storage.SavedRequest:
+			if cl.onSelect != nil {
+				cl.onSelect(ref.ToHTTPRequest(), ref.CollectionID, ref.FolderID)
+			}
 		}
 	})
 
-	// Handle selection
-	cl.List.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
-		if index == 0 {
-			// "New Request" item
-			if cl.onNew != nil {
-				cl.onNew()
+	cl.Tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		collection, folder, request := cl.selected()
+		switch event.Key() {
+		case tcell.KeyRight:
+			// Expand the selected collection or folder
+			switch {
+			case request == nil && folder != nil && !cl.expandedFolders[folder.ID]:
+				cl.expandedFolders[folder.ID] = true
+			case request == nil && folder == nil && collection != nil && !cl.expanded[collection.ID]:
+				cl.expanded[collection.ID] = true
+			default:
+				return event
+			}
+			cl.refresh()
+			return nil
+		case tcell.KeyLeft:
+			// Collapse the selected folder or collection, or the closest one
+			// holding the selection
+			var openFolder int64
+			switch {
+			case request != nil && folder != nil:
+				openFolder = folder.ID
+			case request == nil && folder != nil && cl.expandedFolders[folder.ID]:
+				openFolder = folder.ID
+			case request == nil && folder != nil:
+				openFolder = folder.ParentID
+			}
+			switch {
+			case openFolder != 0:
+				cl.expandedFolders[openFolder] = false
+			case collection != nil && cl.expanded[collection.ID]:
+				cl.expanded[collection.ID] = false
+			default:
+				return event
 			}
-			return
+			cl.refresh()
+			return nil
+		case tcell.KeyRune:
+		default:
+			return event
 		}
 
-		// Adjust index for saved requests (subtract 1 for "New Request" item)
-		reqIndex := index - 1
-		if reqIndex >= 0 && reqIndex < len(cl.requests) {
-			if cl.onSelect != nil {
-				cl.onSelect(cl.requests[reqIndex].ToHTTPRequest())
-			}
+		// New requests and folders go into the selected collection or
+		// folder, or next to the selected request
+		collectionID, folderID := int64(storage.DefaultCollectionID), int64(0)
+		if collection != nil {
+			collectionID = collection.ID
+		}
+		if folder != nil {
+			folderID = folder.ID
 		}
-	})
 
-	// Handle delete with 'd' key
-	cl.List.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		if event.Rune() == 'd' || event.Rune() == 'D' {
-			index := cl.List.GetCurrentItem()
-			if index > 0 { // Don't delete "New Request" item
-				reqIndex := index - 1
-				if reqIndex >= 0 && reqIndex < len(cl.requests) {
-					if cl.onDelete != nil {
-						cl.onDelete(cl.requests[reqIndex].ID)
-					}
+		switch event.Rune() {
+		case 'n', 'N':
+			if cl.onNew != nil {
+				cl.onNew(collectionID, folderID)
+			}
+		case 'f', 'F':
+			if collection != nil && cl.onNewFolder != nil {
+				cl.onNewFolder(collectionID, folderID)
+			}
+		case 'c', 'C':
+			if cl.onNewCollection != nil {
+				cl.onNewCollection()
+			}
+		case 'r', 'R':
+			switch {
+			case request != nil:
+				if cl.onRenameRequest != nil {
+					cl.onRenameRequest(request)
+				}
+			case folder != nil:
+				if cl.onRenameFolder != nil {
+					cl.onRenameFolder(folder)
+				}
+			case collection != nil:
+				if cl.onRenameCollection != nil {
+					cl.onRenameCollection(collection)
 				}
 			}
-			return nil
+		case 'd', 'D':
+			switch {
+			case request != nil:
+				if cl.onDelete != nil {
+					cl.onDelete(request.ID)
+				}
+			case folder != nil:
+				if cl.onDeleteFolder != nil {
+					folders, requests := cl.folderContents(folder.ID)
+					cl.onDeleteFolder(folder, folders, requests)
+				}
+			case collection != nil:
+				if cl.onDeleteCollection != nil {
+					cl.onDeleteCollection(collection)
+				}
+			}
+		case 'm', 'M':
+			if request != nil && cl.onMoveRequest != nil {
+				cl.onMoveRequest(request)
+			}
+		case 'y', 'Y':
+			if request != nil && cl.onCopyRequest != nil {
+				cl.onCopyRequest(request)
+			}
+		case 'h', 'H':
+			if collection != nil && cl.onToggleHistory != nil {
+				cl.onToggleHistory(collection.ID)
+			}
+		default:
+			return event
 		}
-		return event
+		return nil
 	})
 
 	// Help text at bottom
 	helpText := tview.NewTextView().
 		SetDynamicColors(true).
-		SetText("[gray]n: new | d: delete[-]").
+		SetText("[gray]n: new | f: folder | c: collection\nr: rename | m: move | y: copy\nd: delete | h: history[-]").
 		SetTextAlign(tview.AlignCenter)
 	helpText.SetBorder(false)
 
 	cl.Container = tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(cl.List, 0, 1, true).
-		AddItem(helpText, 1, 0, false)
+		AddItem(cl.Tree, 0, 1, true).
+		AddItem(helpText, 3, 0, false)
+
+	cl.refresh()
+}
+
+// selected returns the collection of the selected node, the selected folder
+// or the folder holding the selected request, and the selected request
+func (cl *CollectionsList) selected() (*storage.Collection, *storage.Folder, *storage.SavedRequest) {
+	node := cl.Tree.GetCurrentNode()
+	if node == nil {
+		return nil, nil, nil
+	}
+	switch ref := node.GetReference().(type) {
+	case *storage.Collection:
+		return ref, nil, nil
+	case *storage.Folder:
+		return cl.collection(ref.CollectionID), ref, nil
+	case *storage.SavedRequest:
+		return cl.collection(ref.CollectionID), cl.folder(ref.FolderID), ref
+	}
+	return nil, nil, nil
+}
+
+// collection returns the collection with the given ID
+func (cl *CollectionsList) collection(id int64) *storage.Collection {
+	for _, c := range cl.collections {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+// folder returns the folder with the given ID
+func (cl *CollectionsList) folder(id int64) *storage.Folder {
+	for _, f := range cl.folders {
+		if f.ID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+// folderContents counts the subfolders and requests inside a folder, at any
+// depth
+func (cl *CollectionsList) folderContents(id int64) (folders, requests int) {
+	for _, req := range cl.requests {
+		if req.FolderID == id {
+			requests++
+		}
+	}
+	for _, f := range cl.folders {
+		if f.ParentID == id {
+			subfolders, subrequests := cl.folderContents(f.ID)
+			folders += 1 + subfolders
+			requests += subrequests
+		}
+	}
+	return folders, requests
+}
+
+// SetCollections sets the collections shown as top-level nodes
+func (cl *CollectionsList) SetCollections(collections []*storage.Collection) {
+	cl.collections = collections
+	cl.refresh()
+}
+
+// SetFolders sets the folders shown inside the collections
+func (cl *CollectionsList) SetFolders(folders []*storage.Folder) {
+	cl.folders = folders
+	cl.refresh()
 }
 
-// SetRequests populates the list with saved requests
+// SetRequests populates the tree with saved requests
 func (cl *CollectionsList) SetRequests(requests []*storage.SavedRequest) {
 	cl.requests = requests
 	cl.refresh()
 }
 
-// refresh updates the list display
+// Expand shows the requests of a collection
+func (cl *CollectionsList) Expand(collectionID int64) {
+	cl.expanded[collectionID] = true
+	cl.refresh()
+}
+
+// ExpandFolder shows the contents of a folder, opening the folders and the
+// collection holding it
+func (cl *CollectionsList) ExpandFolder(folderID int64) {
+	for f := cl.folder(folderID); f != nil; f = cl.folder(f.ParentID) {
+		cl.expandedFolders[f.ID] = true
+		cl.expanded[f.CollectionID] = true
+	}
+	cl.refresh()
+}
+
+// refresh rebuilds the tree, keeping the selection on the same collection,
+// folder or request
 func (cl *CollectionsList) refresh() {
-	// Remember current selection
-	currentIndex := cl.List.GetCurrentItem()
+	var selected any
+	if node := cl.Tree.GetCurrentNode(); node != nil {
+		selected = node.GetReference()
+	}
 
-	// Clear and rebuild
-	cl.List.Clear()
+	cl.root.ClearChildren()
+	current := tview.NewTreeNode("+ New Request").
+		SetReference(newRequestNode{}).
+		SetSelectedTextStyle(selectedStyle())
+	cl.root.AddChild(current)
 
-	// Add "New Request" item
-	cl.List.AddItem("+ New Request", "Create a new request", 'n', nil)
+	// addNode adds a node under parent. A node hidden in a collapsed
+	// collection or folder passes its selection on to hiddenBy, the closest
+	// node above it that is shown.
+	addNode := func(parent *tview.TreeNode, text string, ref any, hiddenBy *tview.TreeNode) *tview.TreeNode {
+		node := tview.NewTreeNode(text).
+			SetReference(ref).
+			SetSelectedTextStyle(selectedStyle())
+		parent.AddChild(node)
+		if sameReference(ref, selected) {
+			current = node
+			if hiddenBy != nil {
+				current = hiddenBy
+			}
+		}
+		return node
+	}
+
+	// addContents adds the folders and requests inside a folder, or at the
+	// top of the collection for folder 0
+	var addContents func(parent *tview.TreeNode, collectionID, folderID int64, hiddenBy *tview.TreeNode)
+	addContents = func(parent *tview.TreeNode, collectionID, folderID int64, hiddenBy *tview.TreeNode) {
+		if hiddenBy == nil && !parent.IsExpanded() {
+			hiddenBy = parent
+		}
+		for _, f := range cl.folders {
+			if f.CollectionID == collectionID && f.ParentID == folderID {
+				_, count := cl.folderContents(f.ID)
+				node := addNode(parent, folderText(f, count, cl.expandedFolders[f.ID]), f, hiddenBy)
+				node.SetExpanded(cl.expandedFolders[f.ID])
+				addContents(node, collectionID, f.ID, hiddenBy)
+			}
+		}
+		for _, req := range cl.requests {
+			if req.CollectionID == collectionID && req.FolderID == folderID {
+				addNode(parent, requestText(req), req, hiddenBy)
+			}
+		}
+	}
 
-	// Add saved requests
+	counts := make(map[int64]int)
 	for _, req := range cl.requests {
-		methodColor := getMethodColor(req.Method)
-		mainText := fmt.Sprintf("[%s]%s[-] %s", methodColor, req.Method, req.Name)
-		secondaryText := truncateURL(req.URL, 30)
-		cl.List.AddItem(mainText, secondaryText, 0, nil)
+		counts[req.CollectionID]++
 	}
 
-	// Restore selection if valid
-	if currentIndex < cl.List.GetItemCount() {
-		cl.List.SetCurrentItem(currentIndex)
+	for _, c := range cl.collections {
+		node := addNode(cl.root, collectionText(c, counts[c.ID], cl.expanded[c.ID]), c, nil)
+		node.SetExpanded(cl.expanded[c.ID])
+		addContents(node, c.ID, 0, nil)
 	}
+
+	cl.Tree.SetCurrentNode(current)
 }
 
-// AddRequest adds a request to the list
-func (cl *CollectionsList) AddRequest(req *storage.SavedRequest) {
-	cl.requests = append(cl.requests, req)
-	cl.refresh()
+// sameReference reports whether two node references are the same collection,
+// folder or request, which are reloaded as new values
+func sameReference(a, b any) bool {
+	switch a := a.(type) {
+	case *storage.Collection:
+		b, ok := b.(*storage.Collection)
+		return ok && a.ID == b.ID
+	case *storage.Folder:
+		b, ok := b.(*storage.Folder)
+		return ok && a.ID == b.ID
+	case *storage.SavedRequest:
+		b, ok := b.(*storage.SavedRequest)
+		return ok && a.ID == b.ID
+	}
+	return false
 }
 
-// RemoveRequest removes a request from the list by ID
-func (cl *CollectionsList) RemoveRequest(id int64) {
-	for i, req := range cl.requests {
-		if req.ID == id {
-			cl.requests = append(cl.requests[:i], cl.requests[i+1:]...)
-			break
-		}
+// selectedStyle is the style of the selected tree node
+func selectedStyle() tcell.Style {
+	return tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tview.Styles.PrimaryTextColor)
+}
+
+// expandMarker shows whether a collection or folder is open
+func expandMarker(expanded bool) string {
+	if expanded {
+		return "▾"
 	}
-	cl.refresh()
+	return "▸"
+}
+
+// collectionText shows a collection's name, request count and whether its
+// requests are recorded in history
+func collectionText(c *storage.Collection, count int, expanded bool) string {
+	text := fmt.Sprintf("%s %s [gray](%d)[-]", expandMarker(expanded), tview.Escape(c.Name), count)
+	if c.SkipHistory {
+		text += " [gray]no history[-]"
+	}
+	return text
+}
+
+// folderText shows a folder's name and the number of requests inside it
+func folderText(f *storage.Folder, count int, expanded bool) string {
+	return fmt.Sprintf("%s %s/ [gray](%d)[-]", expandMarker(expanded), tview.Escape(f.Name), count)
+}
+
+// requestText shows a request's method and name
+func requestText(req *storage.SavedRequest) string {
+	return fmt.Sprintf("[%s]%s[-] %s", getMethodColor(req.Method), req.Method, tview.Escape(req.Name))
 }
 
 // SetOnSelect sets the callback for when a request is selected
-func (cl *CollectionsList) SetOnSelect(fn func(req *http.Request)) {
+func (cl *CollectionsList) SetOnSelect(fn func(req *http.Request, collectionID, folderID int64)) {
 	cl.onSelect = fn
 }
 
-// SetOnNew sets the callback for when new request is triggered
-func (cl *CollectionsList) SetOnNew(fn func()) {
+// SetOnNew sets the callback for creating a new request in a collection or
+// folder
+func (cl *CollectionsList) SetOnNew(fn func(collectionID, folderID int64)) {
 	cl.onNew = fn
 }
 
@@ -158,6 +433,61 @@ func (cl *CollectionsList) SetOnDelete(fn func(id int64)) {
 	cl.onDelete = fn
 }
 
+// SetOnToggleHistory sets the callback for turning history recording on or
+// off for a collection
+func (cl *CollectionsList) SetOnToggleHistory(fn func(collectionID int64)) {
+	cl.onToggleHistory = fn
+}
+
+// SetOnNewCollection sets the callback for creating a collection
+func (cl *CollectionsList) SetOnNewCollection(fn func()) {
+	cl.onNewCollection = fn
+}
+
+// SetOnRenameCollection sets the callback for renaming a collection
+func (cl *CollectionsList) SetOnRenameCollection(fn func(c *storage.Collection)) {
+	cl.onRenameCollection = fn
+}
+
+// SetOnDeleteCollection sets the callback for deleting a collection
+func (cl *CollectionsList) SetOnDeleteCollection(fn func(c *storage.Collection)) {
+	cl.onDeleteCollection = fn
+}
+
+// SetOnNewFolder sets the callback for creating a folder in a collection or
+// inside another folder
+func (cl *CollectionsList) SetOnNewFolder(fn func(collectionID, parentID int64)) {
+	cl.onNewFolder = fn
+}
+
+// SetOnRenameFolder sets the callback for renaming a folder
+func (cl *CollectionsList) SetOnRenameFolder(fn func(f *storage.Folder)) {
+	cl.onRenameFolder = fn
+}
+
+// SetOnDeleteFolder sets the callback for deleting a folder, given the number
+// of subfolders and requests that go with it
+func (cl *CollectionsList) SetOnDeleteFolder(fn func(f *storage.Folder, folders, requests int)) {
+	cl.onDeleteFolder = fn
+}
+
+// SetOnRenameRequest sets the callback for renaming a request
+func (cl *CollectionsList) SetOnRenameRequest(fn func(req *storage.SavedRequest)) {
+	cl.onRenameRequest = fn
+}
+
+// SetOnMoveRequest sets the callback for moving a request to another
+// collection or folder
+func (cl *CollectionsList) SetOnMoveRequest(fn func(req *storage.SavedRequest)) {
+	cl.onMoveRequest = fn
+}
+
+// SetOnCopyRequest sets the callback for copying a request to a collection or
+// folder
+func (cl *CollectionsList) SetOnCopyRequest(fn func(req *storage.SavedRequest)) {
+	cl.onCopyRequest = fn
+}
+
 // getMethodColor returns a color for HTTP method
 func getMethodColor(method string) string {
 	switch method {
@@ -179,11 +509,3 @@ func getMethodColor(method string) string {
 		return "white"
 	}
 }
-
-// truncateURL shortens a URL for display
-func truncateURL(url string, maxLen int) string {
-	if len(url) <= maxLen {
-		return url
-	}
-	return url[:maxLen-3] + "..."
-}
@@ -1,17 +1,20 @@
 package components
 
 import (
+	"github.com/YashIIT0909/TRexT/internal/storage"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
 // SaveDialog represents a modal dialog for saving requests
 type SaveDialog struct {
-	Modal     *tview.Form
-	Container *tview.Flex
-	nameInput *tview.InputField
+	Modal            *tview.Form
+	Container        *tview.Flex
+	nameInput        *tview.InputField
+	collectionSelect *tview.DropDown
+	locations        []location
 
-	onSave   func(name string)
+	onSave   func(name string, collectionID, folderID int64)
 	onCancel func()
 }
 
@@ -28,11 +31,17 @@ func (sd *SaveDialog) build() {
 		SetTitle(" Save Request ").
 		SetTitleAlign(tview.AlignCenter)
 
-	sd.Modal.AddInputField("Name:", "", 40, nil, nil)
+	sd.nameInput = tview.NewInputField().
+		SetLabel("Name:").
+		SetFieldWidth(40)
+	sd.collectionSelect = tview.NewDropDown().
+		SetLabel("Collection:")
+	sd.Modal.AddFormItem(sd.nameInput)
+	sd.Modal.AddFormItem(sd.collectionSelect)
 	sd.Modal.AddButton("Save", func() {
 		if sd.onSave != nil {
-			name := sd.Modal.GetFormItem(0).(*tview.InputField).GetText()
-			sd.onSave(name)
+			collectionID, folderID := selectedLocation(sd.collectionSelect, sd.locations)
+			sd.onSave(sd.nameInput.GetText(), collectionID, folderID)
 		}
 	})
 	sd.Modal.AddButton("Cancel", func() {
@@ -57,13 +66,13 @@ func (sd *SaveDialog) build() {
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(nil, 0, 1, false).
-			AddItem(sd.Modal, 10, 0, true).
-			AddItem(nil, 0, 1, false), 50, 0, true).
+			AddItem(sd.Modal, 11, 0, true).
+			AddItem(nil, 0, 1, false), 56, 0, true).
 		AddItem(nil, 0, 1, false)
 }
 
 // SetOnSave sets the save callback
-func (sd *SaveDialog) SetOnSave(fn func(name string)) {
+func (sd *SaveDialog) SetOnSave(fn func(name string, collectionID, folderID int64)) {
 	sd.onSave = fn
 }
 
@@ -74,12 +83,225 @@ func (sd *SaveDialog) SetOnCancel(fn func()) {
 
 // Reset clears the dialog
 func (sd *SaveDialog) Reset() {
-	sd.Modal.GetFormItem(0).(*tview.InputField).SetText("")
+	sd.nameInput.SetText("")
+	sd.Modal.SetFocus(0)
 }
 
 // SetName sets the name input value
 func (sd *SaveDialog) SetName(name string) {
-	sd.Modal.GetFormItem(0).(*tview.InputField).SetText(name)
+	sd.nameInput.SetText(name)
+	sd.Modal.SetFocus(0)
+}
+
+// SetLocations sets the collections and folders to save into and selects one
+func (sd *SaveDialog) SetLocations(collections []*storage.Collection, folders []*storage.Folder, collectionID, folderID int64) {
+	sd.locations = setLocationOptions(sd.collectionSelect, collections, folders, collectionID, folderID)
+}
+
+// location is a collection, or a folder inside one, offered in a dropdown
+type location struct {
+	collectionID int64
+	folderID     int64
+}
+
+// setLocationOptions fills a dropdown with the collections, each followed by
+// its folders shown as paths like "API / users / admin", and selects one
+func setLocationOptions(dropDown *tview.DropDown, collections []*storage.Collection, folders []*storage.Folder, collectionID, folderID int64) []location {
+	var (
+		options   []string
+		locations []location
+	)
+	var addFolders func(c *storage.Collection, parentID int64, path string)
+	addFolders = func(c *storage.Collection, parentID int64, path string) {
+		for _, f := range folders {
+			if f.CollectionID == c.ID && f.ParentID == parentID {
+				options = append(options, path+" / "+f.Name)
+				locations = append(locations, location{c.ID, f.ID})
+				addFolders(c, f.ID, path+" / "+f.Name)
+			}
+		}
+	}
+	for _, c := range collections {
+		options = append(options, c.Name)
+		locations = append(locations, location{c.ID, 0})
+		addFolders(c, 0, c.Name)
+	}
+
+	dropDown.SetOptions(options, nil)
+	for i, loc := range locations {
+		if loc == (location{collectionID, folderID}) {
+			dropDown.SetCurrentOption(i)
+			return locations
+		}
+	}
+	if len(options) > 0 {
+		dropDown.SetCurrentOption(0)
+	}
+	return locations
+}
+
+// selectedLocation returns the collection and folder chosen in a dropdown
+func selectedLocation(dropDown *tview.DropDown, locations []location) (collectionID, folderID int64) {
+	index, _ := dropDown.GetCurrentOption()
+	if index < 0 || index >= len(locations) {
+		return storage.DefaultCollectionID, 0
+	}
+	return locations[index].collectionID, locations[index].folderID
+}
+
+// PromptDialog represents a modal dialog asking for a single line of text
+type PromptDialog struct {
+	Modal     *tview.Form
+	Container *tview.Flex
+	input     *tview.InputField
+
+	onSubmit func(text string)
+	onCancel func()
+}
+
+// NewPromptDialog creates a new prompt dialog
+func NewPromptDialog() *PromptDialog {
+	pd := &PromptDialog{}
+	pd.build()
+	return pd
+}
+
+func (pd *PromptDialog) build() {
+	pd.input = tview.NewInputField().
+		SetLabel("Name:").
+		SetFieldWidth(40)
+
+	pd.Modal = tview.NewForm().
+		AddFormItem(pd.input).
+		AddButton("OK", func() {
+			if pd.onSubmit != nil {
+				pd.onSubmit(pd.input.GetText())
+			}
+		}).
+		AddButton("Cancel", func() {
+			if pd.onCancel != nil {
+				pd.onCancel()
+			}
+		})
+	pd.Modal.SetBorder(true).
+		SetTitleAlign(tview.AlignCenter)
+
+	// Enter in the field submits without moving to the buttons
+	pd.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEnter {
+			if pd.onSubmit != nil {
+				pd.onSubmit(pd.input.GetText())
+			}
+			return nil
+		}
+		return event
+	})
+
+	// Esc cancels
+	pd.Modal.SetCancelFunc(func() {
+		if pd.onCancel != nil {
+			pd.onCancel()
+		}
+	})
+
+	// Center the modal
+	pd.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(pd.Modal, 7, 0, true).
+			AddItem(nil, 0, 1, false), 50, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// SetPrompt sets the dialog title and the initial text
+func (pd *PromptDialog) SetPrompt(title, text string) {
+	pd.Modal.SetTitle(" " + title + " ")
+	pd.input.SetText(text)
+	pd.Modal.SetFocus(0)
+}
+
+// SetOnSubmit sets the callback for when the text is submitted
+func (pd *PromptDialog) SetOnSubmit(fn func(text string)) {
+	pd.onSubmit = fn
+}
+
+// SetOnCancel sets the cancel callback
+func (pd *PromptDialog) SetOnCancel(fn func()) {
+	pd.onCancel = fn
+}
+
+// CollectionDialog represents a modal dialog for choosing a collection or
+// folder
+type CollectionDialog struct {
+	Modal     *tview.Form
+	Container *tview.Flex
+	selection *tview.DropDown
+	locations []location
+
+	onSubmit func(collectionID, folderID int64)
+	onCancel func()
+}
+
+// NewCollectionDialog creates a new collection dialog
+func NewCollectionDialog() *CollectionDialog {
+	cd := &CollectionDialog{}
+	cd.build()
+	return cd
+}
+
+func (cd *CollectionDialog) build() {
+	cd.selection = tview.NewDropDown().
+		SetLabel("Collection:")
+
+	cd.Modal = tview.NewForm().
+		AddFormItem(cd.selection).
+		AddButton("OK", func() {
+			if cd.onSubmit != nil {
+				cd.onSubmit(selectedLocation(cd.selection, cd.locations))
+			}
+		}).
+		AddButton("Cancel", func() {
+			if cd.onCancel != nil {
+				cd.onCancel()
+			}
+		})
+	cd.Modal.SetBorder(true).
+		SetTitleAlign(tview.AlignCenter)
+
+	// Esc cancels
+	cd.Modal.SetCancelFunc(func() {
+		if cd.onCancel != nil {
+			cd.onCancel()
+		}
+	})
+
+	// Center the modal
+	cd.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(cd.Modal, 7, 0, true).
+			AddItem(nil, 0, 1, false), 50, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// SetLocations sets the dialog title and the collections and folders to
+// choose from, selecting one
+func (cd *CollectionDialog) SetLocations(title string, collections []*storage.Collection, folders []*storage.Folder, collectionID, folderID int64) {
+	cd.Modal.SetTitle(" " + title + " ")
+	cd.locations = setLocationOptions(cd.selection, collections, folders, collectionID, folderID)
+	cd.Modal.SetFocus(0)
+}
+
+// SetOnSubmit sets the callback for when a collection or folder is chosen
+func (cd *CollectionDialog) SetOnSubmit(fn func(collectionID, folderID int64)) {
+	cd.onSubmit = fn
+}
+
+// SetOnCancel sets the cancel callback
+func (cd *CollectionDialog) SetOnCancel(fn func()) {
+	cd.onCancel = fn
 }
 
 // HelpBar represents the bottom help bar
@@ -100,7 +322,7 @@ func NewHelpBar() *HelpBar {
 
 // SetDefaultHelp sets the default help text
 func (hb *HelpBar) SetDefaultHelp() {
-	hb.View.SetText("[yellow]Ctrl+Enter[-]: Send | [yellow]Ctrl+S[-]: Save | [yellow]Ctrl+N[-]: New | [yellow]Tab[-]: Navigate | [yellow]Ctrl+Q[-]: Quit")
+	hb.View.SetText("[yellow]Ctrl+Enter[-]: Send | [yellow]Ctrl+S[-]: Save | [yellow]Ctrl+N[-]: New | [yellow]Ctrl+T[-]: Tab | [yellow]Ctrl+E[-]: Env | [yellow]Ctrl+O[-]: Import | [yellow]Ctrl+G[-]: Export | [yellow]Ctrl+R[-]: History | [yellow]Tab[-]: Navigate | [yellow]Ctrl+Q[-]: Quit")
 }
 
 // SetText sets custom help text
@@ -145,6 +367,11 @@ func (cd *ConfirmDialog) build(message string) {
 		AddItem(nil, 0, 1, false)
 }
 
+// SetMessage sets the question asked
+func (cd *ConfirmDialog) SetMessage(message string) {
+	cd.Modal.SetText(message)
+}
+
 // SetOnConfirm sets the confirm callback
 func (cd *ConfirmDialog) SetOnConfirm(fn func()) {
 	cd.onConfirm = fn
@@ -0,0 +1,255 @@
+package components
+
+import (
+	"github.com/YashIIT0909/TRexT/internal/storage"
+	"github.com/YashIIT0909/TRexT/internal/utils"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// noEnvironment is the label shown when no environment is active
+const noEnvironment = "No Environment"
+
+// EnvironmentSelect represents the active environment switcher
+type EnvironmentSelect struct {
+	Container *tview.Flex
+	DropDown  *tview.DropDown
+	envs      []*storage.Environment
+
+	onChange func(env *storage.Environment)
+}
+
+// NewEnvironmentSelect creates a new environment switcher
+func NewEnvironmentSelect() *EnvironmentSelect {
+	es := &EnvironmentSelect{}
+	es.build()
+	return es
+}
+
+func (es *EnvironmentSelect) build() {
+	es.DropDown = tview.NewDropDown().
+		SetFieldWidth(20).
+		SetListStyles(
+			tcell.StyleDefault.Background(tcell.ColorDefault).Foreground(tcell.ColorWhite),
+			tcell.StyleDefault.Background(tcell.ColorDarkCyan).Foreground(tcell.ColorWhite),
+		)
+	es.DropDown.SetBorder(false)
+
+	es.Container = tview.NewFlex().
+		AddItem(es.DropDown, 0, 1, false)
+	es.Container.SetBorder(true).
+		SetTitle(" Environment ").
+		SetTitleAlign(tview.AlignLeft)
+
+	es.SetEnvironments(nil, "")
+}
+
+// SetEnvironments populates the switcher and selects the environment with the given name
+func (es *EnvironmentSelect) SetEnvironments(envs []*storage.Environment, active string) {
+	es.envs = envs
+
+	options := []string{noEnvironment}
+	current := 0
+	for i, env := range envs {
+		options = append(options, env.Name)
+		if env.Name == active {
+			current = i + 1
+		}
+	}
+
+	// Set the callback after selecting so it isn't fired for the initial selection
+	es.DropDown.SetOptions(options, nil)
+	es.DropDown.SetCurrentOption(current)
+	es.DropDown.SetSelectedFunc(func(text string, index int) {
+		if es.onChange != nil {
+			es.onChange(es.environmentAt(index))
+		}
+	})
+}
+
+// Active returns the selected environment, or nil if none is selected
+func (es *EnvironmentSelect) Active() *storage.Environment {
+	index, _ := es.DropDown.GetCurrentOption()
+	return es.environmentAt(index)
+}
+
+// SetOnChange sets the callback for when the active environment changes
+func (es *EnvironmentSelect) SetOnChange(fn func(env *storage.Environment)) {
+	es.onChange = fn
+}
+
+func (es *EnvironmentSelect) environmentAt(index int) *storage.Environment {
+	if index <= 0 || index > len(es.envs) {
+		return nil
+	}
+	return es.envs[index-1]
+}
+
+// EnvironmentDialog represents a modal dialog for managing environments
+type EnvironmentDialog struct {
+	Container      *tview.Flex
+	List           *tview.List
+	Form           *tview.Form
+	nameInput      *tview.InputField
+	variablesInput *tview.TextArea
+	envs           []*storage.Environment
+	editing        *storage.Environment
+
+	onSave   func(env *storage.Environment)
+	onDelete func(id int64)
+	onClose  func()
+	onFocus  func(p tview.Primitive)
+}
+
+// NewEnvironmentDialog creates a new environment manager dialog
+func NewEnvironmentDialog() *EnvironmentDialog {
+	ed := &EnvironmentDialog{}
+	ed.build()
+	return ed
+}
+
+func (ed *EnvironmentDialog) build() {
+	ed.List = tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+	ed.List.SetBorder(true).
+		SetTitle(" Environments ").
+		SetTitleAlign(tview.AlignLeft)
+
+	ed.List.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		ed.edit(index)
+	})
+	ed.List.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		ed.edit(index)
+		ed.focus(ed.Form)
+	})
+
+	// Tab moves into the form, Esc closes the dialog
+	ed.List.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab:
+			ed.focus(ed.Form)
+			return nil
+		case tcell.KeyEscape:
+			if ed.onClose != nil {
+				ed.onClose()
+			}
+			return nil
+		}
+		return event
+	})
+
+	ed.nameInput = tview.NewInputField().
+		SetLabel("Name: ").
+		SetFieldWidth(30)
+
+	ed.variablesInput = tview.NewTextArea().
+		SetLabel("Variables: ").
+		SetPlaceholder("baseUrl=https://api.example.com\ntoken=secret")
+	ed.variablesInput.SetSize(10, 0)
+
+	ed.Form = tview.NewForm().
+		AddFormItem(ed.nameInput).
+		AddFormItem(ed.variablesInput).
+		AddButton("Save", func() {
+			if ed.onSave == nil || ed.nameInput.GetText() == "" {
+				return
+			}
+			env := &storage.Environment{
+				Name:      ed.nameInput.GetText(),
+				Variables: utils.ParseVariables(ed.variablesInput.GetText()),
+			}
+			if ed.editing != nil {
+				env.ID = ed.editing.ID
+			}
+			ed.onSave(env)
+		}).
+		AddButton("Delete", func() {
+			if ed.onDelete != nil && ed.editing != nil {
+				ed.onDelete(ed.editing.ID)
+			}
+		}).
+		AddButton("Close", func() {
+			if ed.onClose != nil {
+				ed.onClose()
+			}
+		})
+	ed.Form.SetBorder(true).
+		SetTitle(" Edit Environment ").
+		SetTitleAlign(tview.AlignLeft)
+
+	// Esc in the form returns to the list
+	ed.Form.SetCancelFunc(func() {
+		ed.focus(ed.List)
+	})
+
+	body := tview.NewFlex().
+		AddItem(ed.List, 24, 0, true).
+		AddItem(ed.Form, 0, 1, false)
+
+	// Center the modal
+	ed.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(body, 18, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// SetEnvironments populates the dialog with the stored environments
+func (ed *EnvironmentDialog) SetEnvironments(envs []*storage.Environment) {
+	ed.envs = envs
+
+	current := ed.List.GetCurrentItem()
+	ed.List.Clear()
+	ed.List.AddItem("+ New Environment", "", 0, nil)
+	for _, env := range envs {
+		ed.List.AddItem(env.Name, "", 0, nil)
+	}
+	if current >= ed.List.GetItemCount() {
+		current = ed.List.GetItemCount() - 1
+	}
+	ed.List.SetCurrentItem(current)
+	ed.edit(current)
+}
+
+// edit loads the environment at the given list index into the form
+func (ed *EnvironmentDialog) edit(index int) {
+	if index <= 0 || index > len(ed.envs) {
+		ed.editing = nil
+		ed.nameInput.SetText("")
+		ed.variablesInput.SetText("", true)
+		return
+	}
+	ed.editing = ed.envs[index-1]
+	ed.nameInput.SetText(ed.editing.Name)
+	ed.variablesInput.SetText(utils.FormatVariables(ed.editing.Variables), true)
+}
+
+// SetOnSave sets the save callback
+func (ed *EnvironmentDialog) SetOnSave(fn func(env *storage.Environment)) {
+	ed.onSave = fn
+}
+
+// SetOnDelete sets the delete callback
+func (ed *EnvironmentDialog) SetOnDelete(fn func(id int64)) {
+	ed.onDelete = fn
+}
+
+// SetOnClose sets the close callback
+func (ed *EnvironmentDialog) SetOnClose(fn func()) {
+	ed.onClose = fn
+}
+
+// SetOnFocus sets the callback used to move focus between the list and the form
+func (ed *EnvironmentDialog) SetOnFocus(fn func(p tview.Primitive)) {
+	ed.onFocus = fn
+}
+
+func (ed *EnvironmentDialog) focus(p tview.Primitive) {
+	if ed.onFocus != nil {
+		ed.onFocus(p)
+	}
+}
@@ -0,0 +1,574 @@
+package components
+
+import (
+	"fmt"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/utils"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// SyntaxColors defines the colors used to highlight response bodies
+type SyntaxColors struct {
+	Key       tcell.Color // JSON and YAML keys
+	String    tcell.Color
+	Number    tcell.Color
+	Keyword   tcell.Color // true, false, null and JavaScript keywords
+	Tag       tcell.Color // XML and HTML tags
+	Attribute tcell.Color // XML and HTML attribute names
+	Comment   tcell.Color
+	Match     tcell.Color // background of search matches
+}
+
+// syntaxColors holds the colors of the active theme; ColorDefault leaves
+// text unhighlighted
+var syntaxColors SyntaxColors
+
+// SetSyntaxColors sets the colors used to highlight response bodies
+func SetSyntaxColors(colors SyntaxColors) {
+	syntaxColors = colors
+}
+
+// Bodies larger than this are shown without highlighting
+const maxHighlightSize = 1 << 20
+
+// Languages that bodies can be highlighted as
+const (
+	syntaxJSON       = "json"
+	syntaxXML        = "xml"
+	syntaxHTML       = "html"
+	syntaxYAML       = "yaml"
+	syntaxJavaScript = "javascript"
+)
+
+// syntaxFor picks the language of a body from its Content-Type. Bodies
+// without a recognised type are treated as JSON when they parse as JSON.
+func syntaxFor(contentType, body string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json"):
+		return syntaxJSON
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return syntaxHTML
+	case strings.HasSuffix(mediaType, "/xml") || strings.HasSuffix(mediaType, "+xml"):
+		return syntaxXML
+	case strings.Contains(mediaType, "yaml"):
+		return syntaxYAML
+	case strings.Contains(mediaType, "javascript") || strings.Contains(mediaType, "ecmascript"):
+		return syntaxJavaScript
+	}
+	if utils.IsValidJSON(body) {
+		return syntaxJSON
+	}
+	return ""
+}
+
+// highlightBody pretty-prints JSON and highlights a body according to its
+// Content-Type. The result is escaped, so the body can't inject tview tags.
+func highlightBody(body, contentType string) string {
+	h := &highlighter{}
+	h.body(body, contentType)
+	return h.String()
+}
+
+// body adds a body highlighted according to its Content-Type
+func (h *highlighter) body(body, contentType string) {
+	syntax := syntaxFor(contentType, body)
+	if syntax == syntaxJSON {
+		body = formatBody(body)
+	}
+	if syntax == "" || len(body) > maxHighlightSize {
+		h.text(body)
+		return
+	}
+
+	switch syntax {
+	case syntaxJSON:
+		h.json(body)
+	case syntaxXML:
+		h.markup(body, false)
+	case syntaxHTML:
+		h.markup(body, true)
+	case syntaxYAML:
+		h.yaml(body)
+	case syntaxJavaScript:
+		h.javascript(body)
+	}
+}
+
+// highlighter builds tview text from tokens. Uncolored text is collected
+// and escaped in one piece so tags split across tokens are escaped too.
+type highlighter struct {
+	out    strings.Builder
+	plain  strings.Builder
+	source strings.Builder // the text without tags
+
+	// Search matches, as sorted byte ranges of the text, are marked as
+	// regions "m0", "m1", ...
+	matches [][]int
+	match   int
+	inMatch bool
+	offset  int
+}
+
+// text adds uncolored text
+func (h *highlighter) text(s string) {
+	h.write(s, tcell.ColorDefault)
+}
+
+// token adds text in the given color
+func (h *highlighter) token(s string, color tcell.Color) {
+	h.write(s, color)
+}
+
+// write adds text, splitting it where search matches start and end
+func (h *highlighter) write(s string, color tcell.Color) {
+	h.source.WriteString(s)
+	for {
+		h.markMatches()
+		if s == "" {
+			return
+		}
+		n := len(s)
+		if h.match < len(h.matches) {
+			boundary := h.matches[h.match][0]
+			if h.inMatch {
+				boundary = h.matches[h.match][1]
+			}
+			n = min(n, boundary-h.offset)
+		}
+		h.emit(s[:n], color)
+		h.offset += n
+		s = s[n:]
+	}
+}
+
+func (h *highlighter) emit(s string, color tcell.Color) {
+	if color == tcell.ColorDefault {
+		h.plain.WriteString(s)
+		return
+	}
+	h.flush()
+	fmt.Fprintf(&h.out, "[#%06x]%s[-]", color.Hex(), tview.Escape(s))
+}
+
+// markMatches opens or closes the region of a match at the current offset
+func (h *highlighter) markMatches() {
+	for h.match < len(h.matches) {
+		m := h.matches[h.match]
+		switch {
+		case !h.inMatch && m[0] == h.offset:
+			h.flush()
+			fmt.Fprintf(&h.out, `["m%d"]`, h.match)
+			if syntaxColors.Match != tcell.ColorDefault {
+				fmt.Fprintf(&h.out, "[:#%06x]", syntaxColors.Match.Hex())
+			}
+			h.inMatch = true
+		case h.inMatch && m[1] == h.offset:
+			h.closeMatch()
+		default:
+			return
+		}
+	}
+}
+
+func (h *highlighter) closeMatch() {
+	h.flush()
+	if syntaxColors.Match != tcell.ColorDefault {
+		h.out.WriteString("[:-]")
+	}
+	h.out.WriteString(`[""]`)
+	h.inMatch = false
+	h.match++
+}
+
+func (h *highlighter) flush() {
+	if h.plain.Len() > 0 {
+		h.out.WriteString(tview.Escape(h.plain.String()))
+		h.plain.Reset()
+	}
+}
+
+// String returns the highlighted text
+func (h *highlighter) String() string {
+	h.markMatches()
+	if h.inMatch {
+		h.closeMatch()
+	}
+	h.flush()
+	return h.out.String()
+}
+
+// json highlights JSON, telling keys from string values by the colon after them
+func (h *highlighter) json(s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			j := scanQuoted(s, i)
+			color := syntaxColors.String
+			if strings.HasPrefix(strings.TrimLeft(s[j:], " \t\r\n"), ":") {
+				color = syntaxColors.Key
+			}
+			h.token(s[i:j], color)
+			i = j
+		case c == '-' || isDigit(c):
+			j := i + 1
+			for j < len(s) && strings.IndexByte("0123456789.eE+-", s[j]) >= 0 {
+				j++
+			}
+			h.token(s[i:j], syntaxColors.Number)
+			i = j
+		case isIdentStart(c):
+			j := scanIdent(s, i)
+			switch s[i:j] {
+			case "true", "false", "null":
+				h.token(s[i:j], syntaxColors.Keyword)
+			default:
+				h.text(s[i:j])
+			}
+			i = j
+		default:
+			h.text(s[i : i+1])
+			i++
+		}
+	}
+}
+
+// jsKeywords are highlighted in JavaScript
+var jsKeywords = map[string]bool{
+	"async": true, "await": true, "break": true, "case": true, "catch": true, "class": true,
+	"const": true, "continue": true, "default": true, "delete": true, "do": true, "else": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true, "from": true,
+	"function": true, "if": true, "import": true, "in": true, "instanceof": true, "let": true,
+	"new": true, "null": true, "of": true, "return": true, "switch": true, "this": true,
+	"throw": true, "true": true, "try": true, "typeof": true, "undefined": true, "var": true,
+	"void": true, "while": true, "yield": true,
+}
+
+// javascript highlights comments, strings, numbers and keywords
+func (h *highlighter) javascript(s string) {
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case strings.HasPrefix(s[i:], "//"):
+			j := strings.IndexByte(s[i:], '\n')
+			if j < 0 {
+				j = len(s) - i
+			}
+			h.token(s[i:i+j], syntaxColors.Comment)
+			i += j
+		case strings.HasPrefix(s[i:], "/*"):
+			j := strings.Index(s[i+2:], "*/")
+			end := len(s)
+			if j >= 0 {
+				end = i + 2 + j + 2
+			}
+			h.token(s[i:end], syntaxColors.Comment)
+			i = end
+		case c == '"' || c == '\'' || c == '`':
+			j := scanQuoted(s, i)
+			h.token(s[i:j], syntaxColors.String)
+			i = j
+		case isDigit(c):
+			j := scanIdent(s, i)
+			for j < len(s) && s[j] == '.' {
+				j = scanIdent(s, j+1)
+			}
+			h.token(s[i:j], syntaxColors.Number)
+			i = j
+		case isIdentStart(c):
+			j := scanIdent(s, i)
+			if jsKeywords[s[i:j]] {
+				h.token(s[i:j], syntaxColors.Keyword)
+			} else {
+				h.text(s[i:j])
+			}
+			i = j
+		default:
+			h.text(s[i : i+1])
+			i++
+		}
+	}
+}
+
+// markup highlights XML, or HTML whose script and style elements hold raw text
+func (h *highlighter) markup(s string, html bool) {
+	for i := 0; i < len(s); {
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			i += h.delimited(rest, "-->", syntaxColors.Comment)
+		case strings.HasPrefix(rest, "<![CDATA["):
+			i += h.delimited(rest, "]]>", syntaxColors.String)
+		case strings.HasPrefix(rest, "<!") || strings.HasPrefix(rest, "<?"):
+			i += h.delimited(rest, ">", syntaxColors.Keyword)
+		case rest[0] == '<' && len(rest) > 1 && (rest[1] == '/' || isIdentStart(rest[1])):
+			n, name, opened := h.markupTag(rest)
+			i += n
+			if !html || !opened || (name != "script" && name != "style") {
+				continue
+			}
+			// Script and style contents run to the closing tag
+			end := strings.Index(strings.ToLower(s[i:]), "</"+name)
+			if end < 0 {
+				end = len(s) - i
+			}
+			if name == "script" {
+				h.javascript(s[i : i+end])
+			} else {
+				h.text(s[i : i+end])
+			}
+			i += end
+		default:
+			j := strings.IndexByte(rest[1:], '<')
+			if j < 0 {
+				j = len(rest) - 1
+			}
+			h.text(rest[:j+1])
+			i += j + 1
+		}
+	}
+}
+
+// delimited colors s up to and including end, returning the length used
+func (h *highlighter) delimited(s, end string, color tcell.Color) int {
+	n := len(s)
+	if j := strings.Index(s, end); j >= 0 {
+		n = j + len(end)
+	}
+	h.token(s[:n], color)
+	return n
+}
+
+// markupTag highlights the tag at the start of s and its attributes. It
+// returns the length of the tag, the lowercased tag name and whether it
+// opens an element.
+func (h *highlighter) markupTag(s string) (int, string, bool) {
+	j := 1
+	closing := s[j] == '/'
+	if closing {
+		j++
+	}
+	k := j
+	for k < len(s) && isNameChar(s[k]) {
+		k++
+	}
+	name := strings.ToLower(s[j:k])
+	h.token(s[:k], syntaxColors.Tag)
+
+	for k < len(s) {
+		c := s[k]
+		switch {
+		case c == '>':
+			h.token(">", syntaxColors.Tag)
+			return k + 1, name, !closing
+		case strings.HasPrefix(s[k:], "/>"):
+			h.token("/>", syntaxColors.Tag)
+			return k + 2, name, false
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(s[k+1:], c)
+			if end < 0 {
+				end = len(s) - k - 2
+			}
+			h.token(s[k:k+end+2], syntaxColors.String)
+			k += end + 2
+		case isNameChar(c):
+			start := k
+			for k < len(s) && isNameChar(s[k]) {
+				k++
+			}
+			h.token(s[start:k], syntaxColors.Attribute)
+		default:
+			h.text(s[k : k+1])
+			k++
+		}
+	}
+	return len(s), name, false
+}
+
+// yaml highlights YAML line by line
+func (h *highlighter) yaml(s string) {
+	blockIndent := -1 // indentation of the key owning a block scalar
+	for _, line := range strings.SplitAfter(s, "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		eol := line[len(content):]
+		indent := len(content) - len(strings.TrimLeft(content, " "))
+
+		// Lines of a block scalar are more indented than its key
+		if blockIndent >= 0 {
+			if strings.TrimSpace(content) == "" || indent > blockIndent {
+				h.token(content, syntaxColors.String)
+				h.text(eol)
+				continue
+			}
+			blockIndent = -1
+		}
+
+		h.text(content[:indent])
+		rest := content[indent:]
+		for rest == "-" || strings.HasPrefix(rest, "- ") {
+			n := min(2, len(rest))
+			h.text(rest[:n])
+			rest = rest[n:]
+			indent += n
+		}
+
+		switch {
+		case strings.HasPrefix(rest, "#"):
+			h.token(rest, syntaxColors.Comment)
+		case rest == "---" || rest == "..." || strings.HasPrefix(rest, "--- "):
+			h.token(rest, syntaxColors.Keyword)
+		default:
+			if k := yamlKeyEnd(rest); k > 0 {
+				h.token(rest[:k], syntaxColors.Key)
+				h.text(":")
+				rest = rest[k+1:]
+			}
+			if h.yamlValue(rest) {
+				blockIndent = indent
+			}
+		}
+		h.text(eol)
+	}
+}
+
+// yamlKeyEnd returns the index of the colon ending the mapping key at the
+// start of s, or -1 if s isn't a key
+func yamlKeyEnd(s string) int {
+	if s == "" {
+		return -1
+	}
+	end := -1
+	if s[0] == '"' || s[0] == '\'' {
+		end = scanQuoted(s, 0)
+		if !strings.HasPrefix(s[end:], ":") {
+			return -1
+		}
+	} else {
+		if strings.IndexByte("{[&*!|>%@`", s[0]) >= 0 {
+			return -1
+		}
+		end = strings.Index(s, ": ")
+		if end < 0 && strings.HasSuffix(s, ":") {
+			end = len(s) - 1
+		}
+		if end < 0 || strings.Contains(s[:end], " #") {
+			return -1
+		}
+	}
+	if end+1 < len(s) && s[end+1] != ' ' {
+		return -1
+	}
+	return end
+}
+
+// yamlValue highlights a scalar value and a trailing comment. It returns
+// true when the value starts a block scalar.
+func (h *highlighter) yamlValue(s string) bool {
+	trimmed := strings.TrimLeft(s, " ")
+	h.text(s[:len(s)-len(trimmed)])
+	if trimmed == "" {
+		return false
+	}
+
+	var value, comment string
+	if trimmed[0] == '"' || trimmed[0] == '\'' {
+		j := scanQuoted(trimmed, 0)
+		h.token(trimmed[:j], syntaxColors.String)
+		value, comment = "", trimmed[j:]
+	} else if strings.HasPrefix(trimmed, "#") {
+		value, comment = "", trimmed
+	} else if j := strings.Index(trimmed, " #"); j >= 0 {
+		value, comment = trimmed[:j], trimmed[j:]
+	} else {
+		value = trimmed
+	}
+
+	block := false
+	word := strings.TrimSpace(value)
+	switch {
+	case word == "":
+		h.text(value)
+	case word[0] == '|' || word[0] == '>':
+		h.token(value, syntaxColors.Keyword)
+		block = true
+	case word[0] == '{' || word[0] == '[':
+		h.json(value)
+	case word[0] == '&' || word[0] == '*' || word[0] == '!':
+		h.token(value, syntaxColors.Keyword)
+	case isYAMLKeyword(word):
+		h.token(value, syntaxColors.Keyword)
+	case isNumber(word):
+		h.token(value, syntaxColors.Number)
+	default:
+		h.token(value, syntaxColors.String)
+	}
+
+	if c := strings.TrimLeft(comment, " "); c != "" {
+		h.text(comment[:len(comment)-len(c)])
+		if strings.HasPrefix(c, "#") {
+			h.token(c, syntaxColors.Comment)
+		} else {
+			h.text(c)
+		}
+	}
+	return block
+}
+
+// isYAMLKeyword reports whether a plain scalar is a boolean or null
+func isYAMLKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null", "~":
+		return true
+	}
+	return false
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+// scanQuoted returns the index after the string starting with the quote at
+// s[i]. Backslash escapes are skipped and only backquoted strings span lines.
+func scanQuoted(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		case '\n':
+			if quote != '`' {
+				return j
+			}
+		}
+	}
+	return len(s)
+}
+
+// scanIdent returns the index after the identifier characters starting at s[i]
+func scanIdent(s string, i int) int {
+	for i < len(s) && (isIdentStart(s[i]) || isDigit(s[i])) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+// isNameChar reports whether c can appear in an XML name
+func isNameChar(c byte) bool {
+	return isIdentStart(c) && c != '$' || isDigit(c) || c == '-' || c == '.' || c == ':'
+}
@@ -0,0 +1,45 @@
+package components
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// colorTags matches the tags the highlighter adds around colored tokens
+var colorTags = regexp.MustCompile(`\[#[0-9a-f]{6}\]|\[-\]`)
+
+func TestHighlightBodyEscapesTags(t *testing.T) {
+	saved := syntaxColors
+	defer SetSyntaxColors(saved)
+	SetSyntaxColors(SyntaxColors{
+		Key: tcell.ColorBlue, String: tcell.ColorGreen, Number: tcell.ColorYellow, Keyword: tcell.ColorRed,
+		Tag: tcell.ColorPurple, Attribute: tcell.ColorTeal, Comment: tcell.ColorGray,
+	})
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		colored     bool
+	}{
+		{"JSON", "application/json", "{\n  \"[red]\": \"[\\\"region\\\"]\",\n  \"bold\": \"[::b]\",\n  \"n\": [\n    1,\n    true\n  ]\n}", true},
+		{"HTML", "text/html", `<p class="[red]" title='["region"]'>[::b]text[red]</p><!-- [::b] -->`, true},
+		{"YAML", "application/yaml", "\"[red]\": [::b]\nlist:\n  - '[\"region\"]' # [red]\n  - 42\n", true},
+		{"plain text", "text/plain", "[red]red[-] [\"region\"]text[\"\"] [::b]bold", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := highlightBody(tt.body, tt.contentType)
+			if colorTags.MatchString(got) != tt.colored {
+				t.Errorf("highlightBody() = %q, want colored %v", got, tt.colored)
+			}
+			if stripped, want := colorTags.ReplaceAllString(got, ""), tview.Escape(tt.body); stripped != want {
+				t.Errorf("highlightBody() without its color tags = %q, want %q", stripped, want)
+			}
+		})
+	}
+}
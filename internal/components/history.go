@@ -0,0 +1,367 @@
+package components
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// HistoryPanel represents the sidebar list of sent requests
+type HistoryPanel struct {
+	Container   *tview.Flex
+	SearchInput *tview.InputField
+	List        *tview.List
+	entries     []*storage.HistoryEntry
+
+	onSearch func(query string)
+	onOpen   func(id int64)
+	onReplay func(id int64)
+	onSave   func(id int64)
+	onClear  func()
+}
+
+// NewHistoryPanel creates a new history panel
+func NewHistoryPanel() *HistoryPanel {
+	hp := &HistoryPanel{}
+	hp.build()
+	return hp
+}
+
+func (hp *HistoryPanel) build() {
+	hp.SearchInput = tview.NewInputField().
+		SetLabel("Search: ").
+		SetPlaceholder("url, name, method or status").
+		SetFieldWidth(0)
+	hp.SearchInput.SetChangedFunc(func(text string) {
+		if hp.onSearch != nil {
+			hp.onSearch(hp.Query())
+		}
+	})
+
+	hp.List = tview.NewList().
+		ShowSecondaryText(true).
+		SetHighlightFullLine(true).
+		SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+
+	// Enter shows the full request and response
+	hp.List.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		if entry := hp.entryAt(index); entry != nil && hp.onOpen != nil {
+			hp.onOpen(entry.ID)
+		}
+	})
+
+	hp.List.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		entry := hp.entryAt(hp.List.GetCurrentItem())
+		switch event.Rune() {
+		case 'r', 'R':
+			if entry != nil && hp.onReplay != nil {
+				hp.onReplay(entry.ID)
+			}
+			return nil
+		case 's', 'S':
+			if entry != nil && hp.onSave != nil {
+				hp.onSave(entry.ID)
+			}
+			return nil
+		case 'c', 'C':
+			if hp.onClear != nil {
+				hp.onClear()
+			}
+			return nil
+		}
+		return event
+	})
+
+	// Help text at bottom
+	helpText := tview.NewTextView().
+		SetDynamicColors(true).
+		SetText("[gray]enter: details | r: replay | s: save | c: clear[-]").
+		SetTextAlign(tview.AlignCenter)
+	helpText.SetBorder(false)
+
+	hp.Container = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(hp.SearchInput, 1, 0, false).
+		AddItem(hp.List, 0, 1, true).
+		AddItem(helpText, 1, 0, false)
+	hp.Container.SetBorder(true).
+		SetTitle(" History ").
+		SetTitleAlign(tview.AlignLeft)
+}
+
+// SetEntries populates the list, newest first
+func (hp *HistoryPanel) SetEntries(entries []*storage.HistoryEntry) {
+	hp.entries = entries
+
+	currentIndex := hp.List.GetCurrentItem()
+	hp.List.Clear()
+	for _, entry := range entries {
+		hp.List.AddItem(historyMainText(entry), historySecondaryText(entry), 0, nil)
+	}
+	if currentIndex < hp.List.GetItemCount() {
+		hp.List.SetCurrentItem(currentIndex)
+	}
+}
+
+// entryAt returns the entry shown at a list index
+func (hp *HistoryPanel) entryAt(index int) *storage.HistoryEntry {
+	if index < 0 || index >= len(hp.entries) {
+		return nil
+	}
+	return hp.entries[index]
+}
+
+// Query returns the search text
+func (hp *HistoryPanel) Query() string {
+	return strings.TrimSpace(hp.SearchInput.GetText())
+}
+
+// GetFocusableItems returns the focusable items of the panel
+func (hp *HistoryPanel) GetFocusableItems() []tview.Primitive {
+	return []tview.Primitive{hp.SearchInput, hp.List}
+}
+
+// SetOnSearch sets the callback for when the search text changes
+func (hp *HistoryPanel) SetOnSearch(fn func(query string)) {
+	hp.onSearch = fn
+}
+
+// SetOnOpen sets the callback for showing an entry's details
+func (hp *HistoryPanel) SetOnOpen(fn func(id int64)) {
+	hp.onOpen = fn
+}
+
+// SetOnReplay sets the callback for loading an entry into the request panel
+func (hp *HistoryPanel) SetOnReplay(fn func(id int64)) {
+	hp.onReplay = fn
+}
+
+// SetOnSave sets the callback for saving an entry as a request
+func (hp *HistoryPanel) SetOnSave(fn func(id int64)) {
+	hp.onSave = fn
+}
+
+// SetOnClear sets the callback for clearing the history
+func (hp *HistoryPanel) SetOnClear(fn func()) {
+	hp.onClear = fn
+}
+
+// historyMainText shows the method, status and name or URL of an entry
+func historyMainText(entry *storage.HistoryEntry) string {
+	title := entry.Name
+	if title == "" {
+		title = entry.URL
+	}
+	status := "[red]ERR[-]"
+	if entry.Error == "" {
+		status = fmt.Sprintf("[%s]%d[-]", statusColor(entry.StatusCode), entry.StatusCode)
+	}
+	return fmt.Sprintf("[%s]%s[-] %s %s", getMethodColor(entry.Method), entry.Method, status, tview.Escape(title))
+}
+
+// historySecondaryText shows when an entry was sent and how long it took
+func historySecondaryText(entry *storage.HistoryEntry) string {
+	return fmt.Sprintf("%s | %dms", time.Unix(entry.Timestamp, 0).Format("Jan 2 15:04:05"), entry.Duration)
+}
+
+// HistoryDialog represents a modal dialog showing a recorded request and
+// response in full
+type HistoryDialog struct {
+	Container *tview.Flex
+	Form      *tview.Form
+	text      *tview.TextView
+	entry     *storage.HistoryEntry
+
+	onReplay func(entry *storage.HistoryEntry)
+	onSave   func(entry *storage.HistoryEntry)
+	onClose  func()
+}
+
+// NewHistoryDialog creates a new history dialog
+func NewHistoryDialog() *HistoryDialog {
+	hd := &HistoryDialog{}
+	hd.build()
+	return hd
+}
+
+func (hd *HistoryDialog) build() {
+	hd.text = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(true)
+
+	hd.Form = tview.NewForm().
+		AddButton("Replay", func() {
+			if hd.onReplay != nil && hd.entry != nil {
+				hd.onReplay(hd.entry)
+			}
+		}).
+		AddButton("Save as Request", func() {
+			if hd.onSave != nil && hd.entry != nil {
+				hd.onSave(hd.entry)
+			}
+		}).
+		AddButton("Close", func() {
+			if hd.onClose != nil {
+				hd.onClose()
+			}
+		})
+	hd.Form.SetButtonsAlign(tview.AlignCenter)
+
+	// Esc closes the dialog
+	hd.Form.SetCancelFunc(func() {
+		if hd.onClose != nil {
+			hd.onClose()
+		}
+	})
+
+	// Scroll the details while the buttons keep focus
+	hd.Form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown, tcell.KeyPgUp, tcell.KeyPgDn, tcell.KeyHome, tcell.KeyEnd:
+			if handler := hd.text.InputHandler(); handler != nil {
+				handler(event, nil)
+			}
+			return nil
+		}
+		return event
+	})
+
+	body := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(hd.text, 0, 1, false).
+		AddItem(hd.Form, 3, 0, true)
+	body.SetBorder(true).
+		SetTitle(" History ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the modal, sized relative to the screen
+	hd.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(body, 0, 8, true).
+			AddItem(nil, 0, 1, false), 0, 8, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// SetEntry shows a history entry
+func (hd *HistoryDialog) SetEntry(entry *storage.HistoryEntry) {
+	hd.entry = entry
+	hd.text.SetText(formatHistoryEntry(entry))
+	hd.text.ScrollToBeginning()
+	hd.Form.SetFocus(0)
+}
+
+// SetOnReplay sets the replay callback
+func (hd *HistoryDialog) SetOnReplay(fn func(entry *storage.HistoryEntry)) {
+	hd.onReplay = fn
+}
+
+// SetOnSave sets the callback for saving the entry as a request
+func (hd *HistoryDialog) SetOnSave(fn func(entry *storage.HistoryEntry)) {
+	hd.onSave = fn
+}
+
+// SetOnClose sets the close callback
+func (hd *HistoryDialog) SetOnClose(fn func()) {
+	hd.onClose = fn
+}
+
+// formatHistoryEntry renders the request and the response received
+func formatHistoryEntry(entry *storage.HistoryEntry) string {
+	req := entry.ToHTTPRequest()
+	resp := entry.ToHTTPResponse()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[yellow]Request[-] [gray]%s[-]\n", time.Unix(entry.Timestamp, 0).Format(time.DateTime))
+	if req.Name != "" {
+		fmt.Fprintf(&b, "%s\n", tview.Escape(req.Name))
+	}
+	fmt.Fprintf(&b, "[%s]%s[-] %s\n", getMethodColor(req.Method), req.Method, tview.Escape(req.URL))
+	if req.Environment != "" {
+		fmt.Fprintf(&b, "[darkcyan]Environment:[-] %s\n", tview.Escape(req.Environment))
+	}
+	for _, h := range req.Headers {
+		if !h.Disabled {
+			fmt.Fprintf(&b, "[darkcyan]%s:[-] %s\n", tview.Escape(h.Key), tview.Escape(h.Value))
+		}
+	}
+	if req.Auth.Type != http.AuthNone {
+		fmt.Fprintf(&b, "[darkcyan]Auth:[-] %s\n", req.Auth.Type)
+	}
+	switch req.BodyType {
+	case http.BodyNone:
+	case http.BodyURLEncoded, http.BodyMultipart:
+		if req.HasBody() {
+			b.WriteString("\n")
+		}
+		for _, f := range req.Form {
+			switch {
+			case f.Disabled || f.Key == "":
+			case f.File:
+				fmt.Fprintf(&b, "[darkcyan]%s:[-] [gray]file[-] %s\n", tview.Escape(f.Key), tview.Escape(f.Value))
+			default:
+				fmt.Fprintf(&b, "[darkcyan]%s:[-] %s\n", tview.Escape(f.Key), tview.Escape(f.Value))
+			}
+		}
+	case http.BodyBinary:
+		if req.BodyFile != "" {
+			fmt.Fprintf(&b, "\n[darkcyan]File:[-] %s\n", tview.Escape(req.BodyFile))
+		}
+	case http.BodyGraphQL:
+		if req.GraphQL.OperationName != "" {
+			fmt.Fprintf(&b, "\n[darkcyan]Operation:[-] %s\n", tview.Escape(req.GraphQL.OperationName))
+		}
+		if req.GraphQL.Query != "" {
+			fmt.Fprintf(&b, "\n%s\n", tview.Escape(req.GraphQL.Query))
+		}
+		if strings.TrimSpace(req.GraphQL.Variables) != "" {
+			fmt.Fprintf(&b, "\n[darkcyan]Variables:[-]\n%s\n", tview.Escape(formatBody(req.GraphQL.Variables)))
+		}
+	default:
+		if req.Body != "" {
+			fmt.Fprintf(&b, "\n%s\n", tview.Escape(formatBody(req.Body)))
+		}
+	}
+
+	b.WriteString("\n[yellow]Response[-]\n")
+	if resp.Error != nil {
+		fmt.Fprintf(&b, "[red]Error:[-] %s\n", tview.Escape(resp.Error.Error()))
+	}
+	if resp.StatusCode == 0 {
+		return b.String()
+	}
+	fmt.Fprintf(&b, "[%s]%s[-] | %dms | %s\n", statusColor(resp.StatusCode), resp.Status, entry.Duration, formatSize(resp.Size))
+	if !resp.Timing.IsZero() {
+		fmt.Fprintf(&b, "\n%s\n", formatTiming(resp))
+	}
+	keys := make([]string, 0, len(resp.Headers))
+	for key := range resp.Headers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range resp.Headers[key] {
+			fmt.Fprintf(&b, "[darkcyan]%s:[-] %s\n", tview.Escape(key), tview.Escape(value))
+		}
+	}
+
+	switch {
+	case entry.ResponseBody == "" && entry.ResponseSize > 0:
+		b.WriteString("\n[gray]Binary body not recorded[-]\n")
+	case entry.ResponseBody != "":
+		fmt.Fprintf(&b, "\n%s\n", highlightBody(entry.ResponseBody, resp.Headers.Get("Content-Type")))
+		if entry.IsTruncated() {
+			fmt.Fprintf(&b, "[gray]... truncated, %s of %s recorded[-]\n",
+				formatSize(int64(len(entry.ResponseBody))), formatSize(entry.ResponseSize))
+		}
+	}
+	return b.String()
+}
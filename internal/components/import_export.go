@@ -0,0 +1,302 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// ImportDialog represents a modal dialog for importing requests
+type ImportDialog struct {
+	Container *tview.Flex
+	Form      *tview.Form
+	input     *tview.TextArea
+	message   *tview.TextView
+
+	onImport func(text string)
+	onCancel func()
+}
+
+// NewImportDialog creates a new import dialog
+func NewImportDialog() *ImportDialog {
+	id := &ImportDialog{}
+	id.build()
+	return id
+}
+
+func (id *ImportDialog) build() {
+	id.input = tview.NewTextArea().
+		SetLabel("Import: ").
+		SetPlaceholder("Paste a curl command, or enter the path of a Postman collection or OpenAPI 3 spec:\n\ncurl -X POST https://api.example.com/users -d '{\"name\": \"trex\"}'\n~/Downloads/Pets.postman_collection.json\n~/Downloads/petstore.yaml")
+	id.input.SetSize(10, 0)
+
+	id.Form = tview.NewForm().
+		AddFormItem(id.input).
+		AddButton("Import", func() {
+			if id.onImport != nil {
+				id.onImport(id.input.GetText())
+			}
+		}).
+		AddButton("Cancel", func() {
+			if id.onCancel != nil {
+				id.onCancel()
+			}
+		})
+
+	// Esc closes the dialog
+	id.Form.SetCancelFunc(func() {
+		if id.onCancel != nil {
+			id.onCancel()
+		}
+	})
+
+	id.message = tview.NewTextView().
+		SetDynamicColors(true)
+
+	body := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(id.Form, 0, 1, true).
+		AddItem(id.message, 1, 0, false)
+	body.SetBorder(true).
+		SetTitle(" Import ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the modal
+	id.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(body, 18, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// SetOnImport sets the import callback
+func (id *ImportDialog) SetOnImport(fn func(text string)) {
+	id.onImport = fn
+}
+
+// SetOnCancel sets the cancel callback
+func (id *ImportDialog) SetOnCancel(fn func()) {
+	id.onCancel = fn
+}
+
+// SetError shows an error below the input
+func (id *ImportDialog) SetError(err error) {
+	id.message.SetText("[red]" + tview.Escape(err.Error()) + "[-]")
+}
+
+// Reset clears the dialog
+func (id *ImportDialog) Reset() {
+	id.input.SetText("", true)
+	id.message.SetText("")
+	id.Form.SetFocus(0)
+}
+
+// ExportDialog represents a modal dialog showing the request as curl and
+// saving its collection to a file
+type ExportDialog struct {
+	Container *tview.Flex
+	Form      *tview.Form
+	text      *tview.TextView
+	pathInput *tview.InputField
+	message   *tview.TextView
+
+	onCopy           func(text string)
+	onSaveCollection func(path string)
+	onClose          func()
+}
+
+// NewExportDialog creates a new export dialog
+func NewExportDialog() *ExportDialog {
+	ed := &ExportDialog{}
+	ed.build()
+	return ed
+}
+
+func (ed *ExportDialog) build() {
+	ed.text = tview.NewTextView().
+		SetWrap(true)
+
+	ed.message = tview.NewTextView().
+		SetDynamicColors(true)
+
+	ed.pathInput = tview.NewInputField().
+		SetLabel("Collection file: ").
+		SetFieldWidth(0)
+
+	ed.Form = tview.NewForm().
+		AddFormItem(ed.pathInput).
+		AddButton("Copy cURL", func() {
+			if ed.onCopy != nil {
+				ed.onCopy(ed.text.GetText(false))
+			}
+		}).
+		AddButton("Save Collection", func() {
+			if ed.onSaveCollection != nil && ed.pathInput.GetText() != "" {
+				ed.onSaveCollection(ed.pathInput.GetText())
+			}
+		}).
+		AddButton("Close", func() {
+			if ed.onClose != nil {
+				ed.onClose()
+			}
+		})
+	ed.Form.SetButtonsAlign(tview.AlignCenter)
+
+	// Esc closes the dialog
+	ed.Form.SetCancelFunc(func() {
+		if ed.onClose != nil {
+			ed.onClose()
+		}
+	})
+
+	body := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(ed.text, 0, 1, false).
+		AddItem(ed.message, 1, 0, false).
+		AddItem(ed.Form, 5, 0, true)
+	body.SetBorder(true).
+		SetTitle(" Export ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the modal
+	ed.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(body, 18, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// SetText sets the exported curl command and clears any previous message
+func (ed *ExportDialog) SetText(text string) {
+	ed.text.SetText(text)
+	ed.text.ScrollToBeginning()
+	ed.message.SetText("")
+	ed.Form.SetFocus(1)
+}
+
+// SetPath sets the file the collection is saved to
+func (ed *ExportDialog) SetPath(path string) {
+	ed.pathInput.SetText(path)
+}
+
+// SetMessage shows a status message below the exported text
+func (ed *ExportDialog) SetMessage(message string) {
+	ed.message.SetText(message)
+}
+
+// SetOnCopy sets the copy callback
+func (ed *ExportDialog) SetOnCopy(fn func(text string)) {
+	ed.onCopy = fn
+}
+
+// SetOnSaveCollection sets the callback for saving the collection to a file
+func (ed *ExportDialog) SetOnSaveCollection(fn func(path string)) {
+	ed.onSaveCollection = fn
+}
+
+// SetOnClose sets the close callback
+func (ed *ExportDialog) SetOnClose(fn func()) {
+	ed.onClose = fn
+}
+
+// SaveResponseDialog represents a modal dialog for saving a response body
+// to a file
+type SaveResponseDialog struct {
+	Container *tview.Flex
+	Form      *tview.Form
+	pathInput *tview.InputField
+	message   *tview.TextView
+
+	onSave   func(path string)
+	onCancel func()
+}
+
+// NewSaveResponseDialog creates a new save response dialog
+func NewSaveResponseDialog() *SaveResponseDialog {
+	sd := &SaveResponseDialog{}
+	sd.build()
+	return sd
+}
+
+func (sd *SaveResponseDialog) build() {
+	sd.pathInput = tview.NewInputField().
+		SetLabel("Save to: ").
+		SetFieldWidth(0)
+
+	sd.Form = tview.NewForm().
+		AddFormItem(sd.pathInput).
+		AddButton("Save", sd.save).
+		AddButton("Cancel", func() {
+			if sd.onCancel != nil {
+				sd.onCancel()
+			}
+		})
+	sd.Form.SetButtonsAlign(tview.AlignCenter)
+
+	// Enter in the field saves without moving to the buttons
+	sd.pathInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			sd.save()
+		}
+	})
+
+	// Esc closes the dialog
+	sd.Form.SetCancelFunc(func() {
+		if sd.onCancel != nil {
+			sd.onCancel()
+		}
+	})
+
+	sd.message = tview.NewTextView().
+		SetDynamicColors(true)
+
+	body := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(sd.Form, 0, 1, true).
+		AddItem(sd.message, 1, 0, false)
+	body.SetBorder(true).
+		SetTitle(" Save Response ").
+		SetTitleAlign(tview.AlignCenter)
+
+	// Center the modal
+	sd.Container = tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(body, 8, 0, true).
+			AddItem(nil, 0, 1, false), 80, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+func (sd *SaveResponseDialog) save() {
+	if sd.onSave != nil && strings.TrimSpace(sd.pathInput.GetText()) != "" {
+		sd.onSave(sd.pathInput.GetText())
+	}
+}
+
+// SetPath sets the file path and clears any previous message
+func (sd *SaveResponseDialog) SetPath(path string) {
+	sd.pathInput.SetText(path)
+	sd.message.SetText("")
+	sd.Form.SetFocus(0)
+}
+
+// SetError shows an error below the path
+func (sd *SaveResponseDialog) SetError(err error) {
+	sd.message.SetText("[red]" + tview.Escape(err.Error()) + "[-]")
+}
+
+// SetOnSave sets the callback for saving to the entered path
+func (sd *SaveResponseDialog) SetOnSave(fn func(path string)) {
+	sd.onSave = fn
+}
+
+// SetOnCancel sets the cancel callback
+func (sd *SaveResponseDialog) SetOnCancel(fn func()) {
+	sd.onCancel = fn
+}
@@ -0,0 +1,301 @@
+package components
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// jsonTreeHelp lists the keys of the tree view
+const jsonTreeHelp = "[gray]←/→: collapse/expand | y: copy path | c: copy value | t: text[-]"
+
+// maxTreeValueLength is how much of a scalar value is shown in its node
+const maxTreeValueLength = 80
+
+// JSONTree shows a JSON body as a tree of collapsible objects and arrays
+type JSONTree struct {
+	Container *tview.Flex
+	Tree      *tview.TreeView
+	footer    *tview.TextView
+	body      []byte
+
+	onCopy func(text string)
+}
+
+// jsonValue is a value of the document along with its source text
+type jsonValue struct {
+	delim byte // '{' or '[' for objects and arrays, 0 for scalars
+	keys  []string
+	items []*jsonValue
+	raw   []byte
+}
+
+// jsonNode is the reference of each tree node
+type jsonNode struct {
+	value  *jsonValue
+	label  string // key or index, empty for the root
+	path   string
+	parent *tview.TreeNode
+	loaded bool
+}
+
+// NewJSONTree creates a new JSON tree view
+func NewJSONTree() *JSONTree {
+	jt := &JSONTree{}
+	jt.build()
+	return jt
+}
+
+func (jt *JSONTree) build() {
+	jt.Tree = tview.NewTreeView().
+		SetGraphics(true).
+		SetTopLevel(0)
+
+	jt.footer = tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+
+	// Enter expands or collapses objects and arrays
+	jt.Tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		jt.setExpanded(node, !node.IsExpanded())
+	})
+
+	// The footer shows where the selected node is
+	jt.Tree.SetChangedFunc(func(node *tview.TreeNode) {
+		jt.showPath(node)
+	})
+
+	jt.Tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		node := jt.Tree.GetCurrentNode()
+		if node == nil {
+			return event
+		}
+		ref, ok := node.GetReference().(*jsonNode)
+		if !ok {
+			return event
+		}
+
+		switch event.Key() {
+		case tcell.KeyRight:
+			if ref.value.delim != 0 && !node.IsExpanded() {
+				jt.setExpanded(node, true)
+			}
+			return nil
+		case tcell.KeyLeft:
+			// Collapse the selected node, or move up to its parent
+			switch {
+			case ref.value.delim != 0 && node.IsExpanded():
+				jt.setExpanded(node, false)
+			case ref.parent != nil:
+				jt.Tree.SetCurrentNode(ref.parent)
+				jt.showPath(ref.parent)
+			}
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'y':
+				jt.copy(ref.path)
+				return nil
+			case 'c':
+				jt.copy(ref.value.text())
+				return nil
+			}
+		}
+		return event
+	})
+
+	jt.Container = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(jt.Tree, 0, 1, true).
+		AddItem(jt.footer, 1, 0, false)
+}
+
+// SetJSON shows body as a tree with the top level expanded. A body that
+// isn't JSON leaves the tree empty and returns an error.
+func (jt *JSONTree) SetJSON(body []byte) error {
+	if jt.body != nil && bytes.Equal(body, jt.body) {
+		return nil
+	}
+	jt.Clear()
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	root, err := decodeJSONValue(dec, body)
+	if err == nil {
+		if _, next := dec.Token(); next != io.EOF {
+			err = errors.New("unexpected data after the top-level value")
+		}
+	}
+	if err != nil {
+		jt.footer.SetText("[gray]Body is not JSON[-]")
+		return err
+	}
+
+	node := jt.newNode(&jsonNode{value: root, path: "$"})
+	jt.Tree.SetRoot(node).SetCurrentNode(node)
+	jt.setExpanded(node, true)
+	jt.showPath(node)
+	jt.body = body
+	return nil
+}
+
+// Clear empties the tree
+func (jt *JSONTree) Clear() {
+	jt.body = nil
+	jt.Tree.SetRoot(nil)
+	jt.footer.SetText("")
+}
+
+// SetOnCopy sets the callback for copying a path or value
+func (jt *JSONTree) SetOnCopy(fn func(text string)) {
+	jt.onCopy = fn
+}
+
+// SetMessage replaces the footer until the selection changes
+func (jt *JSONTree) SetMessage(msg string) {
+	jt.footer.SetText(msg)
+}
+
+func (jt *JSONTree) copy(text string) {
+	if jt.onCopy != nil {
+		jt.onCopy(text)
+	}
+}
+
+func (jt *JSONTree) showPath(node *tview.TreeNode) {
+	if ref, ok := node.GetReference().(*jsonNode); ok {
+		jt.footer.SetText(tview.Escape(ref.path) + "  " + jsonTreeHelp)
+	}
+}
+
+// setExpanded expands or collapses an object or array, adding its children
+// the first time it is expanded
+func (jt *JSONTree) setExpanded(node *tview.TreeNode, expanded bool) {
+	ref := node.GetReference().(*jsonNode)
+	if ref.value.delim == 0 {
+		return
+	}
+	if expanded && !ref.loaded {
+		for i, item := range ref.value.items {
+			child := &jsonNode{value: item, parent: node}
+			if ref.value.delim == '{' {
+				child.label = ref.value.keys[i]
+				child.path = ref.path + jsonPathKey(ref.value.keys[i])
+			} else {
+				child.label = fmt.Sprint(i)
+				child.path = fmt.Sprintf("%s[%d]", ref.path, i)
+			}
+			node.AddChild(jt.newNode(child))
+		}
+		ref.loaded = true
+	}
+	node.SetExpanded(expanded)
+	node.SetText(jsonNodeText(ref, expanded))
+}
+
+func (jt *JSONTree) newNode(ref *jsonNode) *tview.TreeNode {
+	return tview.NewTreeNode(jsonNodeText(ref, false)).
+		SetReference(ref).
+		SetSelectable(true).
+		SetExpanded(false)
+}
+
+// jsonNodeText shows a node's key with the child count of an object or
+// array, or with its value
+func jsonNodeText(ref *jsonNode, expanded bool) string {
+	var label string
+	switch {
+	case ref.parent == nil:
+		label = "$"
+	case ref.parent.GetReference().(*jsonNode).value.delim == '[':
+		label = "[gray]" + ref.label + "[-]"
+	default:
+		h := &highlighter{}
+		h.token(ref.label, syntaxColors.Key)
+		label = h.String()
+	}
+
+	v := ref.value
+	switch v.delim {
+	case '{':
+		return fmt.Sprintf("%s %s [gray]{%d}[-]", expandMarker(expanded), label, len(v.items))
+	case '[':
+		return fmt.Sprintf("%s %s [gray][%d[][-]", expandMarker(expanded), label, len(v.items))
+	}
+
+	raw := string(v.raw)
+	if runes := []rune(raw); len(runes) > maxTreeValueLength {
+		raw = string(runes[:maxTreeValueLength]) + "…"
+	}
+	h := &highlighter{}
+	h.json(raw)
+	return label + ": " + h.String()
+}
+
+// text returns a value for copying: strings without their quotes, and
+// objects and arrays pretty-printed
+func (v *jsonValue) text() string {
+	if v.delim == 0 {
+		var s string
+		if json.Unmarshal(v.raw, &s) == nil {
+			return s
+		}
+		return string(v.raw)
+	}
+	return formatBody(string(v.raw))
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// jsonPathKey returns the JSONPath segment selecting an object key
+func jsonPathKey(key string) string {
+	if identifierPattern.MatchString(key) {
+		return "." + key
+	}
+	quoted, _ := json.Marshal(key)
+	return "[" + string(quoted) + "]"
+}
+
+// decodeJSONValue reads the next value from dec, keeping the order of
+// object keys and the source text of each value
+func decodeJSONValue(dec *json.Decoder, body []byte) (*jsonValue, error) {
+	start := dec.InputOffset()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	v := &jsonValue{}
+	if delim, ok := tok.(json.Delim); ok {
+		if delim != '{' && delim != '[' {
+			return nil, fmt.Errorf("unexpected %q", delim)
+		}
+		v.delim = byte(delim)
+		for dec.More() {
+			if v.delim == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				v.keys = append(v.keys, key.(string))
+			}
+			item, err := decodeJSONValue(dec, body)
+			if err != nil {
+				return nil, err
+			}
+			v.items = append(v.items, item)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The offset before a value includes the separator after the previous token
+	v.raw = bytes.TrimLeft(body[start:dec.InputOffset()], " \t\r\n,:")
+	return v, nil
+}
@@ -0,0 +1,408 @@
+package components
+
+import (
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyValueHelp lists the keys of the key/value table
+const keyValueHelp = "[gray]enter: edit | space: on/off | a: add | d: delete | shift+↑/↓: move[-]"
+
+// fileKeyValueHelp lists the keys of a table whose values can be files
+const fileKeyValueHelp = "[gray]enter: edit | space: on/off | f: file/text | a: add | d: delete | shift+↑/↓: move[-]"
+
+// fixedKeyValueHelp lists the keys of a table whose keys can't be changed
+const fixedKeyValueHelp = "[gray]enter: edit value | esc: stop editing[-]"
+
+// Columns of the key/value table
+const (
+	kvColumnEnabled = iota
+	kvColumnKey
+	kvColumnValue
+	kvColumnDescription
+)
+
+// kvColumnLabels label the input editing each column
+var kvColumnLabels = []string{"", "Key: ", "Value: ", "Description: "}
+
+// KeyValueEditor edits an ordered list of key/value pairs as a table. Rows
+// can be turned off without deleting them and have an optional description.
+type KeyValueEditor struct {
+	Container *tview.Flex
+	Table     *tview.Table
+	input     *tview.InputField
+	footer    *tview.TextView
+	rows      []http.KeyValue
+
+	// Whether the keys are fixed, leaving only values and descriptions to edit
+	fixed bool
+
+	// Whether values can be paths of files to upload
+	files bool
+
+	// The cell being edited; editRow is 0 when none is
+	editRow    int
+	editColumn int
+
+	onChange       func()
+	onFocus        func(p tview.Primitive)
+	onLayoutChange func()
+}
+
+// NewKeyValueEditor creates a new key/value editor with the given title
+func NewKeyValueEditor(title string) *KeyValueEditor {
+	kv := &KeyValueEditor{}
+	kv.build(title)
+	return kv
+}
+
+func (kv *KeyValueEditor) build(title string) {
+	kv.Table = tview.NewTable().
+		SetSelectable(true, true).
+		SetFixed(1, 0)
+
+	// The header row can't be selected
+	kv.Table.SetSelectionChangedFunc(func(row, column int) {
+		if row == 0 {
+			kv.Table.Select(1, column)
+		}
+	})
+
+	// Enter edits a cell, or turns the row on or off in the first column
+	kv.Table.SetSelectedFunc(func(row, column int) {
+		switch {
+		case kv.fixed:
+			if row <= len(kv.rows) {
+				kv.edit(row, max(column, kvColumnValue))
+			}
+		case row > len(kv.rows):
+			kv.rows = append(kv.rows, http.KeyValue{})
+			kv.render()
+			kv.edit(row, kvColumnKey)
+		case column == kvColumnEnabled:
+			kv.toggle(row)
+		default:
+			kv.edit(row, column)
+		}
+	})
+
+	kv.Table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if kv.fixed {
+			return event
+		}
+		row, _ := kv.Table.GetSelection()
+		switch event.Key() {
+		case tcell.KeyUp, tcell.KeyDown:
+			if event.Modifiers()&tcell.ModShift != 0 {
+				delta := 1
+				if event.Key() == tcell.KeyUp {
+					delta = -1
+				}
+				kv.move(row, delta)
+				return nil
+			}
+		case tcell.KeyDelete:
+			kv.delete(row)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case ' ':
+				kv.toggle(row)
+				return nil
+			case 'a':
+				kv.rows = append(kv.rows, http.KeyValue{})
+				kv.render()
+				kv.edit(len(kv.rows), kvColumnKey)
+				return nil
+			case 'd':
+				kv.delete(row)
+				return nil
+			case 'K':
+				kv.move(row, -1)
+				return nil
+			case 'J':
+				kv.move(row, 1)
+				return nil
+			case 'f':
+				if kv.files {
+					kv.toggleFile(row)
+					return nil
+				}
+			}
+		}
+		return event
+	})
+
+	// Input editing the selected cell, shown below the table
+	kv.input = tview.NewInputField().
+		SetFieldWidth(0)
+	kv.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			// Enter moves on to the next column of the row
+			row, column := kv.editRow, kv.editColumn
+			kv.set(row, column, kv.input.GetText())
+			if column < kvColumnDescription {
+				kv.edit(row, column+1)
+			} else {
+				kv.stopEditing()
+			}
+		case tcell.KeyEscape:
+			kv.stopEditing()
+		}
+	})
+
+	kv.footer = tview.NewTextView().
+		SetDynamicColors(true).
+		SetText(keyValueHelp)
+
+	kv.Container = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(kv.Table, 0, 1, true).
+		AddItem(kv.input, 0, 0, false).
+		AddItem(kv.footer, 1, 0, false)
+	kv.Container.SetBorder(true).
+		SetTitle(" " + title + " ").
+		SetTitleAlign(tview.AlignLeft)
+
+	kv.render()
+}
+
+// SetRows replaces the rows of the table
+func (kv *KeyValueEditor) SetRows(rows []http.KeyValue) {
+	kv.rows = append([]http.KeyValue{}, rows...)
+	if kv.editRow > 0 {
+		kv.editRow = 0
+		kv.Container.ResizeItem(kv.input, 0, 0)
+	}
+	kv.render()
+	kv.Table.Select(1, kvColumnKey)
+}
+
+// Rows returns the rows of the table, leaving out blank ones
+func (kv *KeyValueEditor) Rows() []http.KeyValue {
+	var rows []http.KeyValue
+	for _, r := range kv.rows {
+		if r.Key != "" || r.Value != "" {
+			rows = append(rows, r)
+		}
+	}
+	return rows
+}
+
+// SetFixedKeys makes the rows fixed: they can't be added, removed, moved or
+// turned off, and only their values and descriptions are edited
+func (kv *KeyValueEditor) SetFixedKeys(fixed bool) {
+	kv.fixed = fixed
+	help := keyValueHelp
+	if fixed {
+		help = fixedKeyValueHelp
+	}
+	kv.footer.SetText(help)
+	kv.render()
+}
+
+// SetFiles sets whether rows can be switched with f between a text value and
+// the path of a file to upload
+func (kv *KeyValueEditor) SetFiles(files bool) {
+	kv.files = files
+	help := keyValueHelp
+	if files {
+		help = fileKeyValueHelp
+	}
+	kv.footer.SetText(help)
+	kv.render()
+}
+
+// SetOnChange sets the callback for when a row is edited, added, removed,
+// moved or turned on or off
+func (kv *KeyValueEditor) SetOnChange(fn func()) {
+	kv.onChange = fn
+}
+
+// SetOnFocus sets the callback used to move focus between the table and the
+// input editing a cell
+func (kv *KeyValueEditor) SetOnFocus(fn func(p tview.Primitive)) {
+	kv.onFocus = fn
+}
+
+// SetOnLayoutChange sets the callback for when the input is shown or hidden
+func (kv *KeyValueEditor) SetOnLayoutChange(fn func()) {
+	kv.onLayoutChange = fn
+}
+
+// GetFocusableItems returns the table, and the input while a cell is edited
+func (kv *KeyValueEditor) GetFocusableItems() []tview.Primitive {
+	if kv.editRow > 0 {
+		return []tview.Primitive{kv.Table, kv.input}
+	}
+	return []tview.Primitive{kv.Table}
+}
+
+// render fills the table from the rows, with a last row for adding one
+func (kv *KeyValueEditor) render() {
+	kv.Table.Clear()
+	for column, label := range []string{"", "Key", "Value", "Description"} {
+		kv.Table.SetCell(0, column, tview.NewTableCell(label).
+			SetTextColor(tcell.ColorYellow).
+			SetSelectable(false))
+	}
+
+	for i, r := range kv.rows {
+		row := i + 1
+		check, color := "[x]", tview.Styles.PrimaryTextColor
+		if r.Disabled {
+			check, color = "[ ]", tcell.ColorGray
+		}
+		if kv.fixed {
+			check = ""
+		}
+		kv.Table.SetCell(row, kvColumnEnabled, tview.NewTableCell(tview.Escape(check)))
+		kv.Table.SetCell(row, kvColumnKey, tview.NewTableCell(tview.Escape(r.Key)).
+			SetTextColor(color).
+			SetMaxWidth(30).
+			SetExpansion(1))
+		value, valueColor := r.Value, color
+		if r.File && kv.files {
+			value = "@" + value
+			if !r.Disabled {
+				valueColor = tcell.ColorDarkCyan
+			}
+		}
+		kv.Table.SetCell(row, kvColumnValue, tview.NewTableCell(tview.Escape(value)).
+			SetTextColor(valueColor).
+			SetMaxWidth(50).
+			SetExpansion(2))
+		kv.Table.SetCell(row, kvColumnDescription, tview.NewTableCell(tview.Escape(r.Description)).
+			SetTextColor(tcell.ColorGray).
+			SetMaxWidth(30).
+			SetExpansion(1))
+	}
+
+	if kv.fixed {
+		return
+	}
+	add := len(kv.rows) + 1
+	kv.Table.SetCell(add, kvColumnEnabled, tview.NewTableCell(""))
+	kv.Table.SetCell(add, kvColumnKey, tview.NewTableCell("Add…").SetTextColor(tcell.ColorGray))
+	kv.Table.SetCell(add, kvColumnValue, tview.NewTableCell(""))
+	kv.Table.SetCell(add, kvColumnDescription, tview.NewTableCell(""))
+}
+
+// edit shows the input for a cell and focuses it
+func (kv *KeyValueEditor) edit(row, column int) {
+	r := kv.rows[row-1]
+	text := r.Key
+	switch column {
+	case kvColumnValue:
+		text = r.Value
+	case kvColumnDescription:
+		text = r.Description
+	}
+
+	opened := kv.editRow == 0
+	kv.editRow, kv.editColumn = row, column
+	kv.Table.Select(row, column)
+	label := kvColumnLabels[column]
+	if column == kvColumnValue && r.File && kv.files {
+		label = "File: "
+	}
+	kv.input.SetLabel(label).
+		SetText(text)
+	if opened {
+		kv.Container.ResizeItem(kv.input, 1, 0)
+		if kv.onLayoutChange != nil {
+			kv.onLayoutChange()
+		}
+	}
+	if kv.onFocus != nil {
+		kv.onFocus(kv.input)
+	}
+}
+
+// stopEditing hides the input, dropping the row edited if it was left blank
+func (kv *KeyValueEditor) stopEditing() {
+	row, column := kv.editRow, kv.editColumn
+	kv.editRow = 0
+	kv.Container.ResizeItem(kv.input, 0, 0)
+
+	if r := kv.rows[row-1]; r.Key == "" && r.Value == "" && r.Description == "" {
+		kv.rows = append(kv.rows[:row-1], kv.rows[row:]...)
+		kv.render()
+	}
+	kv.Table.Select(min(row, len(kv.rows)+1), column)
+
+	if kv.onLayoutChange != nil {
+		kv.onLayoutChange()
+	}
+	if kv.onFocus != nil {
+		kv.onFocus(kv.Table)
+	}
+}
+
+// set changes a cell of a row
+func (kv *KeyValueEditor) set(row, column int, text string) {
+	r := &kv.rows[row-1]
+	switch column {
+	case kvColumnKey:
+		r.Key = text
+	case kvColumnValue:
+		r.Value = text
+	case kvColumnDescription:
+		r.Description = text
+	}
+	kv.render()
+	kv.changed()
+}
+
+// toggle turns a row on or off
+func (kv *KeyValueEditor) toggle(row int) {
+	if row < 1 || row > len(kv.rows) {
+		return
+	}
+	kv.rows[row-1].Disabled = !kv.rows[row-1].Disabled
+	kv.render()
+	kv.changed()
+}
+
+// toggleFile switches a row between a text value and a file
+func (kv *KeyValueEditor) toggleFile(row int) {
+	if row < 1 || row > len(kv.rows) {
+		return
+	}
+	kv.rows[row-1].File = !kv.rows[row-1].File
+	kv.render()
+	kv.changed()
+}
+
+// delete removes a row
+func (kv *KeyValueEditor) delete(row int) {
+	if row < 1 || row > len(kv.rows) {
+		return
+	}
+	kv.rows = append(kv.rows[:row-1], kv.rows[row:]...)
+	kv.render()
+	_, column := kv.Table.GetSelection()
+	kv.Table.Select(min(row, len(kv.rows)+1), column)
+	kv.changed()
+}
+
+// move swaps a row with the one delta rows away
+func (kv *KeyValueEditor) move(row, delta int) {
+	target := row + delta
+	if row < 1 || row > len(kv.rows) || target < 1 || target > len(kv.rows) {
+		return
+	}
+	kv.rows[row-1], kv.rows[target-1] = kv.rows[target-1], kv.rows[row-1]
+	kv.render()
+	_, column := kv.Table.GetSelection()
+	kv.Table.Select(target, column)
+	kv.changed()
+}
+
+func (kv *KeyValueEditor) changed() {
+	if kv.onChange != nil {
+		kv.onChange()
+	}
+}
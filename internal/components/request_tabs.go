@@ -0,0 +1,96 @@
+package components
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/rivo/tview"
+)
+
+// maxTabTitle is the longest request name or URL shown in a tab label
+const maxTabTitle = 24
+
+// RequestTab is an open request as shown in the tab strip
+type RequestTab struct {
+	Request *http.Request
+	Sending bool
+}
+
+// RequestTabs represents the strip of open request tabs above the request
+// panel; clicking a label selects its tab
+type RequestTabs struct {
+	View    *tview.TextView
+	current int
+
+	onSelect func(index int)
+}
+
+// NewRequestTabs creates a new tab strip
+func NewRequestTabs() *RequestTabs {
+	rt := &RequestTabs{}
+	rt.build()
+	return rt
+}
+
+func (rt *RequestTabs) build() {
+	rt.View = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	rt.View.SetBorder(false)
+
+	// Mouse clicks highlight a region; treat that as selecting the tab
+	rt.View.SetHighlightedFunc(func(added, removed, remaining []string) {
+		if len(added) > 0 {
+			index, err := strconv.Atoi(added[0])
+			if err == nil && index != rt.current && rt.onSelect != nil {
+				rt.onSelect(index)
+			}
+			return
+		}
+		if len(remaining) == 0 {
+			// Clicking outside a label clears the highlight, restore it
+			rt.View.Highlight(strconv.Itoa(rt.current))
+		}
+	})
+}
+
+// SetTabs shows the open tabs and highlights the current one
+func (rt *RequestTabs) SetTabs(tabs []RequestTab, current int) {
+	rt.current = current
+	parts := make([]string, len(tabs))
+	for i, tab := range tabs {
+		parts[i] = fmt.Sprintf(`["%d"] %s [""]`, i, tabLabel(tab))
+	}
+	rt.View.SetText(strings.Join(parts, "[gray]|[-]"))
+	rt.View.Highlight(strconv.Itoa(current))
+}
+
+// tabLabel shows a request's method and name, or its URL when unsaved, with
+// a marker while it is being sent
+func tabLabel(tab RequestTab) string {
+	req := tab.Request
+	title := req.Name
+	if title == "" {
+		title = req.URL
+	}
+	if title == "" {
+		title = "New Request"
+	}
+	if runes := []rune(title); len(runes) > maxTabTitle {
+		title = string(runes[:maxTabTitle-1]) + "…"
+	}
+
+	label := fmt.Sprintf("[%s]%s[-] %s", getMethodColor(req.Method), req.Method, tview.Escape(title))
+	if tab.Sending {
+		label += " [yellow]●[-]"
+	}
+	return label
+}
+
+// SetOnSelect sets the callback for when a tab label is clicked
+func (rt *RequestTabs) SetOnSelect(fn func(index int)) {
+	rt.onSelect = fn
+}
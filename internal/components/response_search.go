@@ -0,0 +1,299 @@
+package components
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// searchableTabs are the tabs whose text can be searched
+var searchableTabs = []string{"body", "headers", "cookies", "raw"}
+
+// maxSearchMatches caps how many matches are highlighted in a view
+const maxSearchMatches = 5000
+
+// responseSearch is the state of the search within the response
+type responseSearch struct {
+	open          bool // whether the search input is shown
+	query         string
+	caseSensitive bool
+	regex         bool
+	err           error
+	matches       [][]int // byte ranges of the matches in the current view
+	current       int
+	marked        string // the tab whose view has matches marked
+}
+
+func (rv *ResponseView) buildSearch() {
+	rv.SearchInput = tview.NewInputField().
+		SetFieldWidth(0)
+	rv.updateSearchLabel()
+
+	// Search as the query is typed
+	rv.SearchInput.SetChangedFunc(func(text string) {
+		rv.search.query = text
+		rv.search.current = 0
+		rv.refreshSearch()
+	})
+
+	// Enter jumps to the next match, Esc goes back to the view keeping the
+	// matches highlighted
+	rv.SearchInput.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			rv.nextMatch(1)
+		case tcell.KeyEscape:
+			rv.closeSearch()
+		}
+	})
+
+	rv.SearchInput.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			rv.nextMatch(-1)
+			return nil
+		case tcell.KeyDown:
+			rv.nextMatch(1)
+			return nil
+		case tcell.KeyRune:
+			if event.Modifiers()&tcell.ModAlt == 0 {
+				return event
+			}
+			switch event.Rune() {
+			case 'c':
+				rv.search.caseSensitive = !rv.search.caseSensitive
+			case 'r':
+				rv.search.regex = !rv.search.regex
+			default:
+				return event
+			}
+			rv.updateSearchLabel()
+			rv.refreshSearch()
+			return nil
+		}
+		return event
+	})
+
+	// Clicking a match makes it the current one
+	for _, name := range searchableTabs {
+		rv.textView(name).SetHighlightedFunc(func(added, removed, remaining []string) {
+			if len(added) == 0 {
+				return
+			}
+			if i, err := strconv.Atoi(strings.TrimPrefix(added[0], "m")); err == nil && i != rv.search.current {
+				rv.search.current = i
+				rv.updateStatus()
+			}
+		})
+	}
+}
+
+// searchKeys handles the keys of the search in a view: '/' opens the search,
+// n and N move between matches and Esc clears them
+func (rv *ResponseView) searchKeys(event *tcell.EventKey) *tcell.EventKey {
+	if !rv.searchable() {
+		return event
+	}
+	switch event.Key() {
+	case tcell.KeyEscape:
+		if rv.search.query != "" {
+			rv.ClearSearch()
+			return nil
+		}
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case '/':
+			rv.openSearch()
+			return nil
+		case 'n':
+			rv.nextMatch(1)
+			return nil
+		case 'N':
+			rv.nextMatch(-1)
+			return nil
+		}
+	}
+	return event
+}
+
+// searchable reports whether the current tab shows text that can be searched
+func (rv *ResponseView) searchable() bool {
+	name := rv.Tabs.Current()
+	return rv.textView(name) != nil && !(name == "body" && rv.treeMode)
+}
+
+// textView returns the text view of a searchable tab
+func (rv *ResponseView) textView(name string) *tview.TextView {
+	switch name {
+	case "body":
+		return rv.BodyView
+	case "headers":
+		return rv.HeadersView
+	case "cookies":
+		return rv.CookiesView
+	case "raw":
+		return rv.RawView
+	}
+	return nil
+}
+
+func (rv *ResponseView) openSearch() {
+	rv.search.open = true
+	rv.contentBox.ResizeItem(rv.SearchInput, 1, 0)
+	if rv.onTabChange != nil {
+		rv.onTabChange(rv.Tabs.Current())
+	}
+	if rv.onFocus != nil {
+		rv.onFocus(rv.SearchInput)
+	}
+}
+
+// closeSearch hides the search input and focuses the view searched
+func (rv *ResponseView) closeSearch() {
+	if !rv.search.open {
+		return
+	}
+	rv.search.open = false
+	rv.contentBox.ResizeItem(rv.SearchInput, 0, 0)
+	if rv.onTabChange != nil {
+		rv.onTabChange(rv.Tabs.Current())
+	}
+	if rv.onFocus != nil {
+		rv.onFocus(rv.CurrentView())
+	}
+}
+
+// ClearSearch closes the search and removes the highlighted matches
+func (rv *ResponseView) ClearSearch() {
+	rv.closeSearch()
+	rv.SearchInput.SetText("")
+}
+
+func (rv *ResponseView) updateSearchLabel() {
+	var options []string
+	if rv.search.caseSensitive {
+		options = append(options, "Aa")
+	}
+	if rv.search.regex {
+		options = append(options, ".*")
+	}
+	label := "Search: "
+	if len(options) > 0 {
+		label = fmt.Sprintf("Search (%s): ", strings.Join(options, " "))
+	}
+	rv.SearchInput.SetLabel(label)
+	rv.SearchInput.SetPlaceholder("Alt+C: match case | Alt+R: regex | ↑/↓: previous/next")
+}
+
+// refreshSearch renders the current view again with the matches marked,
+// and the view that had them before without
+func (rv *ResponseView) refreshSearch() {
+	if marked := rv.search.marked; marked != "" && marked != rv.Tabs.Current() {
+		rv.renderContent(marked)
+	}
+	if rv.textView(rv.Tabs.Current()) != nil {
+		rv.renderContent(rv.Tabs.Current())
+	}
+	rv.showMatch()
+}
+
+// renderContent shows the text of a searchable view, with the matches of the
+// search when it's the current view
+func (rv *ResponseView) renderContent(name string) {
+	view := rv.textView(name)
+	render := rv.contents[name]
+	if render == nil {
+		view.SetText("")
+		return
+	}
+
+	h := &highlighter{}
+	render(h)
+	if rv.search.marked == name {
+		rv.search.marked = ""
+	}
+	if rv.search.query != "" && name == rv.Tabs.Current() && rv.searchable() {
+		rv.findMatches(h.source.String())
+		if len(rv.search.matches) > 0 {
+			h = &highlighter{matches: rv.search.matches}
+			render(h)
+			rv.search.marked = name
+		}
+	}
+	view.SetText(h.String())
+}
+
+// findMatches finds the matches of the query in text
+func (rv *ResponseView) findMatches(text string) {
+	rv.search.matches, rv.search.err = nil, nil
+
+	pattern := rv.search.query
+	if !rv.search.regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !rv.search.caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		rv.search.err = err
+		return
+	}
+
+	for _, m := range re.FindAllStringIndex(text, maxSearchMatches) {
+		// Empty matches can't be highlighted
+		if m[0] < m[1] {
+			rv.search.matches = append(rv.search.matches, m)
+		}
+	}
+}
+
+// showMatch highlights the current match and scrolls to it
+func (rv *ResponseView) showMatch() {
+	if !rv.searchable() || rv.search.query == "" {
+		rv.search.matches, rv.search.err = nil, nil
+	}
+	if view := rv.textView(rv.Tabs.Current()); view != nil {
+		if n := len(rv.search.matches); n > 0 {
+			rv.search.current = min(rv.search.current, n-1)
+			view.Highlight(fmt.Sprintf("m%d", rv.search.current)).
+				ScrollToHighlight()
+		} else {
+			view.Highlight()
+		}
+	}
+	rv.updateStatus()
+}
+
+// nextMatch moves by delta matches, wrapping around at either end
+func (rv *ResponseView) nextMatch(delta int) {
+	n := len(rv.search.matches)
+	if n == 0 {
+		return
+	}
+	rv.search.current = ((rv.search.current+delta)%n + n) % n
+	rv.showMatch()
+}
+
+// updateStatus shows the status with the match count of the search
+func (rv *ResponseView) updateStatus() {
+	text := rv.status
+	if rv.search.query != "" && rv.searchable() {
+		n := len(rv.search.matches)
+		switch {
+		case rv.search.err != nil:
+			text += " | [red]Invalid regex[-]"
+		case n == 0:
+			text += " | [red]No matches[-]"
+		case n == maxSearchMatches:
+			text += fmt.Sprintf(" | Match %d/%d+", rv.search.current+1, n)
+		default:
+			text += fmt.Sprintf(" | Match %d/%d", rv.search.current+1, n)
+		}
+	}
+	rv.StatusBar.SetText(text)
+}
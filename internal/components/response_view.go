@@ -1,31 +1,74 @@
 package components
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	nethttp "net/http"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/YashIIT0909/TRexT/internal/http"
 	"github.com/YashIIT0909/TRexT/internal/utils"
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
 
+const (
+	// filterDelay is how long typing in the filter has to pause before it's run
+	filterDelay = 300 * time.Millisecond
+
+	// filterTimeout is how long a filter may run before it's given up on
+	filterTimeout = 2 * time.Second
+)
+
 // ResponseView represents the response display panel
 type ResponseView struct {
 	Container   *tview.Flex
 	StatusBar   *tview.TextView
+	Tabs        *TabBar
+	TabPages    *tview.Pages
 	HeadersView *tview.TextView
 	BodyView    *tview.TextView
-	tabs        *tview.TextView
-	currentTab  string
+	FilterInput *tview.InputField
+	JSONTree    *JSONTree
+	CookiesView *tview.TextView
+	TimingView  *tview.TextView
+	RawView     *tview.TextView
+	SearchInput *tview.InputField
+	bodyPages   *tview.Pages
+	contentBox  *tview.Flex
 	response    *http.Response
+	treeMode    bool
+	status      string
+
+	// contents renders the text of each searchable view
+	contents map[string]func(h *highlighter)
+
+	search responseSearch
+
+	// The body decoded for filtering, once a filter is set
+	filterData    any
+	filterErr     error
+	filterDecoded bool
+
+	// cancelFilter stops the filter waiting to run or running, if any
+	cancelFilter context.CancelFunc
+
+	// queueUpdate runs a function on the UI goroutine and redraws
+	queueUpdate func(fn func())
+
+	onTabChange  func(name string)
+	onFocus      func(p tview.Primitive)
+	onFilterDone func(filter string)
+	onSave       func(resp *http.Response)
 }
 
 // NewResponseView creates a new response view
 func NewResponseView() *ResponseView {
-	rv := &ResponseView{
-		currentTab: "body",
-	}
+	rv := &ResponseView{contents: make(map[string]func(h *highlighter))}
 	rv.build()
 	return rv
 }
@@ -37,86 +80,163 @@ func (rv *ResponseView) build() {
 		SetTextAlign(tview.AlignLeft)
 	rv.StatusBar.SetBorder(false)
 
-	// Tabs (will be placed at the bottom)
-	rv.tabs = tview.NewTextView().
-		SetDynamicColors(true).
-		SetText("[\"body\"][darkcyan]Body[white][\"\"] | [\"headers\"]Headers[\"\"]")
-	rv.tabs.SetBorder(false)
-
 	// Headers view
 	rv.HeadersView = tview.NewTextView().
 		SetDynamicColors(true).
+		SetRegions(true).
 		SetScrollable(true)
 	rv.HeadersView.SetBorder(false)
 
 	// Body view
 	rv.BodyView = tview.NewTextView().
 		SetDynamicColors(true).
+		SetRegions(true).
 		SetScrollable(true).
 		SetWrap(true)
 	rv.BodyView.SetBorder(false)
 
-	// Content container (shows either body or headers)
-	contentBox := tview.NewFlex().
-		AddItem(rv.BodyView, 0, 1, false)
-	contentBox.SetBorder(true).
+	// Filter input - shows the result of a jq or JSONPath filter as it's typed
+	rv.FilterInput = tview.NewInputField().
+		SetLabel("Filter: ").
+		SetPlaceholder("jq (.items[].id) or JSONPath ($.items[*].id)").
+		SetFieldWidth(0)
+	rv.FilterInput.SetChangedFunc(func(text string) {
+		rv.filterBody(filterDelay)
+	})
+	rv.FilterInput.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter && rv.onFilterDone != nil {
+			rv.onFilterDone(rv.Filter())
+		}
+	})
+
+	bodyText := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(rv.BodyView, 0, 1, false).
+		AddItem(rv.FilterInput, 1, 0, false)
+
+	// Tree view - the alternative to the body text for JSON
+	rv.JSONTree = NewJSONTree()
+
+	// 't' switches the body between text and tree
+	rv.bodyPages = tview.NewPages().
+		AddPage("text", bodyText, true, true).
+		AddPage("tree", rv.JSONTree.Container, true, false)
+	rv.bodyPages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyRune && event.Rune() == 't' && !rv.FilterInput.HasFocus() {
+			rv.SetTreeMode(!rv.treeMode)
+			return nil
+		}
+		return event
+	})
+
+	// Cookies view
+	rv.CookiesView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetScrollable(true)
+	rv.CookiesView.SetBorder(false)
+
+	// Timing view
+	rv.TimingView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWrap(false)
+	rv.TimingView.SetBorder(false)
+
+	// Raw view - the response as received, without formatting
+	rv.RawView = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetScrollable(true).
+		SetWrap(true)
+	rv.RawView.SetBorder(false)
+
+	// Tabs switch the content shown
+	rv.Tabs = NewTabBar(
+		[]string{"body", "headers", "cookies", "timing", "raw"},
+		[]string{"Body", "Headers", "Cookies", "Timing", "Raw"},
+	)
+	rv.TabPages = tview.NewPages().
+		AddPage("body", rv.bodyPages, true, true).
+		AddPage("headers", rv.HeadersView, true, false).
+		AddPage("cookies", rv.CookiesView, true, false).
+		AddPage("timing", rv.TimingView, true, false).
+		AddPage("raw", rv.RawView, true, false)
+	rv.Tabs.SetOnChange(func(name string) {
+		rv.TabPages.SwitchToPage(name)
+		rv.refreshSearch()
+		if rv.onTabChange != nil {
+			rv.onTabChange(name)
+		}
+	})
+
+	// Search input - hidden until '/' is pressed
+	rv.buildSearch()
+
+	// 's' saves the body, other keys search the view
+	rv.TabPages.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if rv.FilterInput.HasFocus() {
+			return event
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() == 's' {
+			if rv.onSave != nil && rv.response != nil && rv.response.Error == nil {
+				rv.onSave(rv.response)
+			}
+			return nil
+		}
+		return rv.searchKeys(event)
+	})
+
+	// Content container (shows the current tab and the search input)
+	rv.contentBox = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(rv.TabPages, 0, 1, false).
+		AddItem(rv.SearchInput, 0, 0, false)
+	rv.contentBox.SetBorder(true).
 		SetTitle(" Response ").
 		SetTitleAlign(tview.AlignLeft)
 
-	// Footer row with tabs
-	footerRow := tview.NewFlex().
-		SetDirection(tview.FlexColumn).
-		AddItem(rv.StatusBar, 0, 1, false).
-		AddItem(rv.tabs, 30, 0, false)
-
-	// Main container - content on top, tabs at bottom
+	// Main container - tabs on top, status bar at bottom
 	rv.Container = tview.NewFlex().
 		SetDirection(tview.FlexRow).
-		AddItem(contentBox, 0, 1, false).
-		AddItem(footerRow, 1, 0, false)
+		AddItem(rv.Tabs.View, 1, 0, false).
+		AddItem(rv.contentBox, 0, 1, false).
+		AddItem(rv.StatusBar, 1, 0, false)
 }
 
 // SetResponse displays the response
 func (rv *ResponseView) SetResponse(resp *http.Response) {
 	rv.response = resp
+	rv.filterData, rv.filterErr, rv.filterDecoded = nil, nil, false
+	rv.TimingView.SetText(formatTiming(resp))
 
-	if resp.Error != nil {
-		rv.StatusBar.SetText(fmt.Sprintf("[red]Error:[-] %s", resp.Error.Error()))
-		rv.BodyView.SetText("")
-		rv.HeadersView.SetText("")
+	if errors.Is(resp.Error, context.Canceled) {
+		rv.setStatus(fmt.Sprintf("[yellow]Cancelled after %.1fs[-]", resp.Duration.Seconds()))
+		rv.clearContents()
 		return
 	}
 
-	// Status bar
-	statusColor := "green"
-	switch {
-	case resp.StatusCode >= 300 && resp.StatusCode < 400:
-		statusColor = "yellow"
-	case resp.StatusCode >= 400 && resp.StatusCode < 500:
-		statusColor = "orange"
-	case resp.StatusCode >= 500:
-		statusColor = "red"
+	if resp.Error != nil {
+		rv.setStatus(fmt.Sprintf("[red]Error:[-] %s", tview.Escape(resp.Error.Error())))
+		rv.clearContents()
+		return
 	}
 
+	// Status bar
 	statusText := fmt.Sprintf("[%s]%s[-] | %dms | %s",
-		statusColor,
+		statusColor(resp.StatusCode),
 		resp.Status,
 		resp.Duration.Milliseconds(),
 		formatSize(resp.Size),
 	)
-	rv.StatusBar.SetText(statusText)
+	rv.setStatus(statusText)
 
-	// Format body
-	body := resp.BodyString()
-	if utils.IsValidJSON(body) {
-		if formatted, err := utils.FormatJSON(body); err == nil {
-			body = formatted
-		}
+	rv.showBody()
+	rv.JSONTree.Clear()
+	if rv.treeMode {
+		_ = rv.JSONTree.SetJSON(resp.Body)
 	}
-	rv.BodyView.SetText(body)
 
-	// Format headers
-	var headerLines []string
 	// Sort headers for consistent display
 	keys := make([]string, 0, len(resp.Headers))
 	for k := range resp.Headers {
@@ -124,47 +244,330 @@ func (rv *ResponseView) SetResponse(resp *http.Response) {
 	}
 	sort.Strings(keys)
 
-	for _, key := range keys {
-		values := resp.Headers[key]
-		for _, v := range values {
-			headerLines = append(headerLines, fmt.Sprintf("[darkcyan]%s:[-] %s", key, v))
+	rv.setContent("headers", func(h *highlighter) {
+		first := true
+		for _, key := range keys {
+			for _, v := range resp.Headers[key] {
+				if !first {
+					h.text("\n")
+				}
+				first = false
+				h.token(key+":", tcell.ColorDarkCyan)
+				h.text(" " + v)
+			}
 		}
+	})
+	rv.setContent("cookies", func(h *highlighter) {
+		writeCookies(h, resp.Cookies())
+	})
+
+	// Raw response: status line, headers, blank line, body
+	rv.setContent("raw", func(h *highlighter) {
+		statusLine := resp.Status
+		if resp.Proto != "" {
+			statusLine = resp.Proto + " " + resp.Status
+		}
+		h.text(statusLine + "\n")
+		for _, key := range keys {
+			for _, v := range resp.Headers[key] {
+				h.text(key + ": " + v + "\n")
+			}
+		}
+		h.text("\n")
+		if resp.BodyFile != "" {
+			writeBodyFile(h, resp)
+		} else if utf8.Valid(resp.Body) {
+			h.text(resp.BodyString())
+		} else {
+			h.token("Binary body, "+formatSize(resp.Size), tcell.ColorGray)
+		}
+	})
+}
+
+// showBody shows the formatted and highlighted body, or the result of the
+// filter when one is set
+func (rv *ResponseView) showBody() {
+	rv.filterBody(0)
+}
+
+// filterBody is showBody with the filter run after a delay, so that it isn't
+// run for every key typed. Filters run off the UI goroutine with a timeout,
+// since jq programs can take any time, and a newer filter cancels the last.
+func (rv *ResponseView) filterBody(delay time.Duration) {
+	rv.stopFilter()
+	rv.updateTitle()
+	resp := rv.response
+	if resp == nil || resp.Error != nil {
+		return
+	}
+
+	if resp.BodyFile != "" {
+		rv.setContent("body", func(h *highlighter) {
+			writeBodyFile(h, resp)
+		})
+		return
+	}
+
+	filter := strings.TrimSpace(rv.FilterInput.GetText())
+	if filter == "" {
+		rv.setContent("body", func(h *highlighter) {
+			h.body(resp.BodyString(), resp.Headers.Get("Content-Type"))
+		})
+		return
+	}
+
+	if !rv.filterDecoded {
+		rv.filterData, rv.filterErr = utils.DecodeJSON(resp.Body)
+		rv.filterDecoded = true
+	}
+	if rv.filterErr != nil {
+		rv.setContent("body", func(h *highlighter) {
+			h.token("Can't filter a body that isn't JSON", tcell.ColorRed)
+		})
+		return
+	}
+
+	if rv.queueUpdate == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), filterTimeout)
+		defer cancel()
+		rv.showFilterResult(runFilter(ctx, rv.filterData, filter))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	rv.cancelFilter = cancel
+	data := rv.filterData
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+
+		runCtx, stop := context.WithTimeout(ctx, filterTimeout)
+		result, err := runFilter(runCtx, data, filter)
+		stop()
+
+		rv.queueUpdate(func() {
+			// Another filter or response has replaced this one
+			if ctx.Err() != nil {
+				return
+			}
+			cancel()
+			rv.cancelFilter = nil
+			rv.showFilterResult(result, err)
+		})
+	}()
+}
+
+// stopFilter cancels the filter waiting to run or running, if any
+func (rv *ResponseView) stopFilter() {
+	if rv.cancelFilter != nil {
+		rv.cancelFilter()
+		rv.cancelFilter = nil
+	}
+}
+
+// runFilter runs a filter, explaining one that didn't finish in time
+func runFilter(ctx context.Context, data any, filter string) (string, error) {
+	result, err := utils.FilterJSON(ctx, data, filter)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("the filter took longer than %s and was stopped", filterTimeout)
+	}
+	return result, err
+}
+
+// showFilterResult shows the output of the filter in place of the body
+func (rv *ResponseView) showFilterResult(result string, err error) {
+	if err != nil {
+		rv.setContent("body", func(h *highlighter) {
+			h.token(err.Error(), tcell.ColorRed)
+		})
+		return
+	}
+	rv.BodyView.ScrollToBeginning()
+	rv.setContent("body", func(h *highlighter) {
+		h.body(result, "application/json")
+	})
+}
+
+// SetFilter sets the filter applied to the body
+func (rv *ResponseView) SetFilter(filter string) {
+	if filter != rv.FilterInput.GetText() {
+		rv.FilterInput.SetText(filter)
+	}
+}
+
+// Filter returns the filter applied to the body
+func (rv *ResponseView) Filter() string {
+	return strings.TrimSpace(rv.FilterInput.GetText())
+}
+
+// SetQueueUpdate sets how filters running in the background show their
+// result, which must be on the UI goroutine. Without it filters are run in
+// place.
+func (rv *ResponseView) SetQueueUpdate(fn func(fn func())) {
+	rv.queueUpdate = fn
+}
+
+// SetOnFilterDone sets the callback for when Enter is pressed in the filter
+func (rv *ResponseView) SetOnFilterDone(fn func(filter string)) {
+	rv.onFilterDone = fn
+}
+
+// updateTitle shows whether the body is a tree or filtered
+func (rv *ResponseView) updateTitle() {
+	switch {
+	case rv.treeMode:
+		rv.contentBox.SetTitle(" Response (tree) ")
+	case rv.Filter() != "":
+		rv.contentBox.SetTitle(" Response (filtered) ")
+	default:
+		rv.contentBox.SetTitle(" Response ")
+	}
+}
+
+// SetError displays an error that prevented the request from being sent
+func (rv *ResponseView) SetError(err error) {
+	rv.SetResponse(&http.Response{Error: err})
+}
+
+// SetSending shows how long the request in flight has been waiting and, once
+// the body is arriving, how much of it has been received. A total below zero
+// means the size of the body is unknown.
+func (rv *ResponseView) SetSending(elapsed time.Duration, received, total int64) {
+	if received == 0 {
+		rv.setStatus(fmt.Sprintf("[yellow]Sending request... %.1fs[-] [gray](Esc to cancel)[-]", elapsed.Seconds()))
+		return
 	}
-	rv.HeadersView.SetText(strings.Join(headerLines, "\n"))
+
+	progress := formatSize(received)
+	if total > 0 {
+		progress += fmt.Sprintf(" of %s (%d%%)", formatSize(total), received*100/total)
+	}
+	rv.setStatus(fmt.Sprintf("[yellow]Receiving %s... %.1fs[-] [gray](Esc to cancel)[-]", progress, elapsed.Seconds()))
+}
+
+// SetOnSave sets the callback for saving the response body to a file
+func (rv *ResponseView) SetOnSave(fn func(resp *http.Response)) {
+	rv.onSave = fn
+}
+
+// Response returns the response on display, or nil when there is none
+func (rv *ResponseView) Response() *http.Response {
+	return rv.response
 }
 
 // Clear resets the response view
 func (rv *ResponseView) Clear() {
 	rv.response = nil
-	rv.StatusBar.SetText("[gray]No response yet[-]")
-	rv.BodyView.SetText("")
-	rv.HeadersView.SetText("")
+	rv.setStatus("[gray]No response yet[-]")
+	rv.clearContents()
+	rv.TimingView.SetText("")
+}
+
+// setStatus sets the status bar text, which the search adds its match count to
+func (rv *ResponseView) setStatus(text string) {
+	rv.status = text
+	rv.updateStatus()
+}
+
+// setContent sets how the text of a searchable view is rendered, and shows it
+func (rv *ResponseView) setContent(name string, render func(h *highlighter)) {
+	rv.contents[name] = render
+	rv.renderContent(name)
+	if name == rv.Tabs.Current() {
+		rv.showMatch()
+	}
+}
+
+// clearContents empties the body and the searchable views
+func (rv *ResponseView) clearContents() {
+	rv.stopFilter()
+	rv.JSONTree.Clear()
+	for _, name := range searchableTabs {
+		rv.setContent(name, nil)
+	}
 }
 
-// ShowTab switches between body and headers view
+// ShowTab switches to the tab with the given name
 func (rv *ResponseView) ShowTab(tab string) {
-	rv.currentTab = tab
-	// Update tab highlighting
-	switch tab {
-	case "body":
-		rv.tabs.SetText("[darkcyan]Body[-] | [gray]Headers[-]")
+	rv.Tabs.SetCurrent(tab)
+}
+
+// ToggleTab switches to the next tab
+func (rv *ResponseView) ToggleTab() {
+	rv.Tabs.Next()
+}
+
+// GetCurrentTab returns the current tab
+func (rv *ResponseView) GetCurrentTab() string {
+	return rv.Tabs.Current()
+}
+
+// CurrentView returns the content view of the current tab
+func (rv *ResponseView) CurrentView() tview.Primitive {
+	switch rv.Tabs.Current() {
 	case "headers":
-		rv.tabs.SetText("[gray]Body[-] | [darkcyan]Headers[-]")
+		return rv.HeadersView
+	case "cookies":
+		return rv.CookiesView
+	case "timing":
+		return rv.TimingView
+	case "raw":
+		return rv.RawView
+	}
+	if rv.treeMode {
+		return rv.JSONTree.Tree
 	}
+	return rv.BodyView
 }
 
-// ToggleTab switches between tabs
-func (rv *ResponseView) ToggleTab() {
-	if rv.currentTab == "body" {
-		rv.ShowTab("headers")
+// SetTreeMode shows the body as a JSON tree or as text
+func (rv *ResponseView) SetTreeMode(tree bool) {
+	rv.treeMode = tree
+	if tree {
+		if rv.response != nil && rv.response.Error == nil {
+			_ = rv.JSONTree.SetJSON(rv.response.Body)
+		}
+		rv.bodyPages.SwitchToPage("tree")
 	} else {
-		rv.ShowTab("body")
+		rv.bodyPages.SwitchToPage("text")
+	}
+	rv.updateTitle()
+	rv.refreshSearch()
+
+	if rv.onTabChange != nil {
+		rv.onTabChange(rv.Tabs.Current())
+	}
+	if rv.Tabs.Current() == "body" && rv.onFocus != nil {
+		rv.onFocus(rv.CurrentView())
 	}
 }
 
-// GetCurrentTab returns the current tab
-func (rv *ResponseView) GetCurrentTab() string {
-	return rv.currentTab
+// SetOnFocus sets the callback used to focus the body after switching
+// between text and tree
+func (rv *ResponseView) SetOnFocus(fn func(p tview.Primitive)) {
+	rv.onFocus = fn
+}
+
+// GetFocusableItems returns the tab bar and the content of the current tab
+func (rv *ResponseView) GetFocusableItems() []tview.Primitive {
+	items := []tview.Primitive{rv.Tabs.View, rv.CurrentView()}
+	if rv.Tabs.Current() == "body" && !rv.treeMode {
+		items = append(items, rv.FilterInput)
+	}
+	if rv.search.open {
+		items = append(items, rv.SearchInput)
+	}
+	return items
+}
+
+// SetOnTabChange sets the callback for when the visible tab changes
+func (rv *ResponseView) SetOnTabChange(fn func(name string)) {
+	rv.onTabChange = fn
 }
 
 // formatSize formats bytes to human readable format
@@ -180,3 +583,153 @@ func formatSize(bytes int64) string {
 	}
 	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
 }
+
+// writeBodyFile tells where a body too large to show was streamed to
+func writeBodyFile(h *highlighter, resp *http.Response) {
+	h.token(fmt.Sprintf("Body of %s saved to %s", formatSize(resp.Size), resp.BodyFile), tcell.ColorGray)
+}
+
+// writeCookies lists cookies set by the response with their attributes
+func writeCookies(h *highlighter, cookies []*nethttp.Cookie) {
+	if len(cookies) == 0 {
+		h.token("No cookies set", tcell.ColorGray)
+		return
+	}
+
+	for i, c := range cookies {
+		if i > 0 {
+			h.text("\n")
+		}
+		h.token(c.Name, tcell.ColorDarkCyan)
+		h.text(" = " + c.Value + "\n")
+		if c.Domain != "" {
+			h.text("  Domain: " + c.Domain + "\n")
+		}
+		if c.Path != "" {
+			h.text("  Path: " + c.Path + "\n")
+		}
+		if !c.Expires.IsZero() {
+			h.text("  Expires: " + c.Expires.Local().Format(time.RFC1123) + "\n")
+		}
+		if c.MaxAge > 0 {
+			h.text(fmt.Sprintf("  Max-Age: %ds\n", c.MaxAge))
+		} else if c.MaxAge < 0 {
+			h.text("  Max-Age: 0 (delete)\n")
+		}
+
+		var flags []string
+		if c.Secure {
+			flags = append(flags, "Secure")
+		}
+		if c.HttpOnly {
+			flags = append(flags, "HttpOnly")
+		}
+		switch c.SameSite {
+		case nethttp.SameSiteLaxMode:
+			flags = append(flags, "SameSite=Lax")
+		case nethttp.SameSiteStrictMode:
+			flags = append(flags, "SameSite=Strict")
+		case nethttp.SameSiteNoneMode:
+			flags = append(flags, "SameSite=None")
+		}
+		if c.Partitioned {
+			flags = append(flags, "Partitioned")
+		}
+		if len(flags) > 0 {
+			h.text("  " + strings.Join(flags, ", ") + "\n")
+		}
+	}
+}
+
+// waterfallWidth is the width of the bar showing the whole request in the
+// timing tab
+const waterfallWidth = 30
+
+// formatTiming shows the phases of a request as a waterfall, each bar
+// starting where the previous one ended
+func formatTiming(resp *http.Response) string {
+	t := resp.Timing
+	if t.IsZero() {
+		return "[gray]No timing recorded[-]"
+	}
+
+	phases := []struct {
+		label    string
+		duration time.Duration
+		color    string
+	}{
+		{"DNS lookup", t.DNS, "teal"},
+		{"TCP connect", t.Connect, "yellow"},
+		{"TLS handshake", t.TLS, "purple"},
+		{"Waiting (TTFB)", t.FirstByte, "green"},
+		{"Content transfer", t.Transfer, "blue"},
+	}
+
+	total := resp.Duration
+	var sum time.Duration
+	for _, p := range phases {
+		sum += p.duration
+	}
+	if sum > total {
+		total = sum
+	}
+
+	var sb strings.Builder
+	var offset time.Duration
+	for _, p := range phases {
+		start := int(int64(offset) * waterfallWidth / int64(max(total, 1)))
+		width := int(int64(p.duration) * waterfallWidth / int64(max(total, 1)))
+		if p.duration > 0 {
+			width = max(width, 1)
+		}
+		start = min(start, waterfallWidth-width)
+		if p.duration == 0 {
+			fmt.Fprintf(&sb, "%-17s %8s\n", p.label, "-")
+			continue
+		}
+		fmt.Fprintf(&sb, "%-17s %8s  %s[%s]%s[-]\n", p.label, formatDuration(p.duration),
+			strings.Repeat(" ", start), p.color, strings.Repeat("█", width))
+		offset += p.duration
+	}
+	fmt.Fprintf(&sb, "%-17s %8s\n", "Total", formatDuration(resp.Duration))
+	if t.Reused {
+		sb.WriteString("\n[gray]Connection reused[-]\n")
+	}
+	return sb.String()
+}
+
+// formatDuration shows a duration in milliseconds, or seconds from 1s up
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= 10*time.Millisecond:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	default:
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	}
+}
+
+// statusColor returns a color for an HTTP status code
+func statusColor(code int) string {
+	switch {
+	case code >= 300 && code < 400:
+		return "yellow"
+	case code >= 400 && code < 500:
+		return "orange"
+	case code >= 500:
+		return "red"
+	default:
+		return "green"
+	}
+}
+
+// formatBody pretty-prints JSON bodies
+func formatBody(body string) string {
+	if utils.IsValidJSON(body) {
+		if formatted, err := utils.FormatJSON(body); err == nil {
+			return formatted
+		}
+	}
+	return body
+}
@@ -0,0 +1,108 @@
+package components
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// TabBar represents a row of tab labels that can be switched with the
+// arrow keys when focused or by clicking a label
+type TabBar struct {
+	View    *tview.TextView
+	names   []string
+	labels  []string
+	current int
+
+	onChange func(name string)
+}
+
+// NewTabBar creates a tab bar; names identify the tabs, labels are displayed
+func NewTabBar(names, labels []string) *TabBar {
+	tb := &TabBar{
+		names:  names,
+		labels: labels,
+	}
+	tb.build()
+	return tb
+}
+
+func (tb *TabBar) build() {
+	tb.View = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false)
+	tb.View.SetBorder(false)
+
+	var parts []string
+	for i, name := range tb.names {
+		parts = append(parts, fmt.Sprintf(`["%s"] %s [""]`, name, tb.labels[i]))
+	}
+	tb.View.SetText(strings.Join(parts, "[gray]|[-]"))
+
+	// Mouse clicks highlight a region; treat that as selecting the tab
+	tb.View.SetHighlightedFunc(func(added, removed, remaining []string) {
+		if len(added) > 0 {
+			tb.SetCurrent(added[0])
+			return
+		}
+		if len(remaining) == 0 {
+			// Clicking outside a label clears the highlight, restore it
+			tb.View.Highlight(tb.names[tb.current])
+		}
+	})
+
+	tb.View.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyLeft || event.Rune() == 'h':
+			tb.Prev()
+			return nil
+		case event.Key() == tcell.KeyRight || event.Rune() == 'l':
+			tb.Next()
+			return nil
+		}
+		return event
+	})
+
+	tb.View.Highlight(tb.names[0])
+}
+
+// SetCurrent switches to the tab with the given name
+func (tb *TabBar) SetCurrent(name string) {
+	for i, n := range tb.names {
+		if n != name {
+			continue
+		}
+		changed := i != tb.current
+		tb.current = i
+		if highlights := tb.View.GetHighlights(); len(highlights) != 1 || highlights[0] != name {
+			tb.View.Highlight(name)
+		}
+		if changed && tb.onChange != nil {
+			tb.onChange(name)
+		}
+		return
+	}
+}
+
+// Current returns the name of the selected tab
+func (tb *TabBar) Current() string {
+	return tb.names[tb.current]
+}
+
+// Next selects the tab to the right, wrapping around
+func (tb *TabBar) Next() {
+	tb.SetCurrent(tb.names[(tb.current+1)%len(tb.names)])
+}
+
+// Prev selects the tab to the left, wrapping around
+func (tb *TabBar) Prev() {
+	tb.SetCurrent(tb.names[(tb.current+len(tb.names)-1)%len(tb.names)])
+}
+
+// SetOnChange sets the callback for when the selected tab changes
+func (tb *TabBar) SetOnChange(fn func(name string)) {
+	tb.onChange = fn
+}
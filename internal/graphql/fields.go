@@ -0,0 +1,74 @@
+package graphql
+
+// TypeAt returns the type whose fields can be selected at a byte offset of
+// query, following the fields of the selection sets around it. It works on
+// queries still being typed, and returns nil outside a selection set or when
+// the type can't be told.
+func (s *Schema) TypeAt(query string, offset int) *Type {
+	if offset > len(query) {
+		offset = len(query)
+	}
+	lex := &lexer{src: query[:offset]}
+
+	var (
+		stack   []*Type // the types of the selection sets the offset is in
+		pending *Type   // the type of the next selection set
+		field   string  // the last field name seen, which the next selection set belongs to
+		parens  int     // depth of argument and variable lists, whose braces are values
+	)
+	var prev token
+	for {
+		tok, err := lex.next()
+		if err != nil || tok.kind == tokenEOF {
+			break
+		}
+
+		switch {
+		case tok.kind == tokenPunct && tok.value == "(":
+			parens++
+		case tok.kind == tokenPunct && tok.value == ")":
+			parens = max(parens-1, 0)
+		case parens > 0:
+		case tok.kind == tokenPunct && tok.value == "{":
+			t := pending
+			switch {
+			case t != nil:
+			case len(stack) == 0:
+				// A query without the query keyword
+				t = s.rootType("query")
+			case stack[len(stack)-1] != nil && field != "":
+				if f := s.field(stack[len(stack)-1], field); f != nil {
+					t = s.Types[f.Type.NamedType()]
+				}
+			}
+			stack = append(stack, t)
+			pending, field = nil, ""
+		case tok.kind == tokenPunct && tok.value == "}":
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			pending, field = nil, ""
+		case tok.kind != tokenName:
+		case prev.kind == tokenPunct && prev.value == "@":
+			// A directive, which leaves the field it's on to be selected
+		case prev.kind == tokenPunct && prev.value == "...":
+			// A fragment spread or inline fragment
+			field = ""
+		case prev.kind == tokenName && prev.value == "on":
+			// The type condition of a fragment
+			pending = s.Types[tok.value]
+		case len(stack) == 0:
+			if tok.value == "query" || tok.value == "mutation" || tok.value == "subscription" {
+				pending = s.rootType(tok.value)
+			}
+		case tok.value != "on":
+			field = tok.value
+		}
+		prev = tok
+	}
+
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
@@ -0,0 +1,577 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Error is a syntax or validation error in a GraphQL query. Line and Column
+// are 0 for errors that aren't about a place in the query.
+type Error struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return e.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// newError returns an error at a byte offset of src
+func newError(src string, pos int, format string, args ...any) *Error {
+	e := &Error{Message: fmt.Sprintf(format, args...), Line: 1, Column: 1}
+	if pos > len(src) {
+		pos = len(src)
+	}
+	if i := strings.LastIndexByte(src[:pos], '\n'); i >= 0 {
+		e.Line += strings.Count(src[:pos], "\n")
+		e.Column += utf8.RuneCountInString(src[i+1 : pos])
+	} else {
+		e.Column += utf8.RuneCountInString(src[:pos])
+	}
+	return e
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string // the punctuator, name or number; strings keep their quotes
+	pos   int
+}
+
+// String describes the token for error messages
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "the end of the query"
+	case tokenName:
+		return fmt.Sprintf("name %q", t.value)
+	case tokenString:
+		return "a string"
+	case tokenInt, tokenFloat:
+		return "number " + t.value
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// lexer splits a query into tokens, skipping whitespace, commas and comments
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, *Error) {
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' && l.src[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "\uFEFF"):
+			// Byte order mark
+			l.pos += len("\uFEFF")
+		default:
+			return l.token()
+		}
+	}
+	return token{kind: tokenEOF, pos: l.pos}, nil
+}
+
+func (l *lexer) token() (token, *Error) {
+	start := l.pos
+	c := l.src[start]
+	switch {
+	case strings.HasPrefix(l.src[start:], "..."):
+		l.pos += 3
+		return token{kind: tokenPunct, value: "...", pos: start}, nil
+	case strings.IndexByte("!$&():=@[]{|}", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), pos: start}, nil
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.src[start:l.pos], pos: start}, nil
+	case c == '-' || isDigit(c):
+		return l.number()
+	case c == '"':
+		return l.string()
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[start:])
+	return token{}, newError(l.src, start, "unexpected character %q", r)
+}
+
+func (l *lexer) number() (token, *Error) {
+	start := l.pos
+	kind := tokenInt
+	if l.src[l.pos] == '-' {
+		l.pos++
+	}
+	digits := func() bool {
+		from := l.pos
+		for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+			l.pos++
+		}
+		return l.pos > from
+	}
+	if !digits() {
+		return token{}, newError(l.src, start, "invalid number")
+	}
+	if l.pos < len(l.src) && l.src[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if !digits() {
+			return token{}, newError(l.src, start, "invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+			l.pos++
+		}
+		if !digits() {
+			return token{}, newError(l.src, start, "invalid number")
+		}
+	}
+	if l.pos < len(l.src) && (l.src[l.pos] == '_' || isLetter(l.src[l.pos]) || l.src[l.pos] == '.') {
+		return token{}, newError(l.src, start, "invalid number")
+	}
+	return token{kind: kind, value: l.src[start:l.pos], pos: start}, nil
+}
+
+func (l *lexer) string() (token, *Error) {
+	start := l.pos
+	if strings.HasPrefix(l.src[start:], `"""`) {
+		// Block strings end at the first """ that isn't escaped
+		for l.pos += 3; l.pos < len(l.src); l.pos++ {
+			if strings.HasPrefix(l.src[l.pos:], `\"""`) {
+				l.pos += 3
+			} else if strings.HasPrefix(l.src[l.pos:], `"""`) {
+				l.pos += 3
+				return token{kind: tokenString, value: l.src[start:l.pos], pos: start}, nil
+			}
+		}
+		return token{}, newError(l.src, start, "unterminated string")
+	}
+
+	for l.pos++; l.pos < len(l.src); l.pos++ {
+		switch l.src[l.pos] {
+		case '\\':
+			l.pos++
+		case '"':
+			l.pos++
+			return token{kind: tokenString, value: l.src[start:l.pos], pos: start}, nil
+		case '\n', '\r':
+			return token{}, newError(l.src, start, "unterminated string")
+		}
+	}
+	return token{}, newError(l.src, start, "unterminated string")
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// document is a parsed query with its operations and fragments
+type document struct {
+	operations []*operation
+	fragments  []*fragment
+}
+
+type operation struct {
+	kind       string // query, mutation or subscription
+	name       string
+	variables  []*variableDef
+	directives []*directive
+	selections []*selection
+	pos        int
+}
+
+type variableDef struct {
+	name         string
+	typ          *TypeRef
+	defaultValue *value
+	pos          int
+}
+
+type fragment struct {
+	name          string
+	typeCondition string
+	directives    []*directive
+	selections    []*selection
+	pos           int
+}
+
+type selectionKind int
+
+const (
+	selectField selectionKind = iota
+	selectSpread
+	selectInline
+)
+
+// selection is a field, a fragment spread or an inline fragment
+type selection struct {
+	kind          selectionKind
+	alias         string
+	name          string // the field or the fragment spread
+	typeCondition string // of an inline fragment, if it has one
+	arguments     []*argument
+	directives    []*directive
+	selections    []*selection
+	pos           int
+}
+
+// argument is an argument, or a field of an object value
+type argument struct {
+	name  string
+	value *value
+	pos   int
+}
+
+type directive struct {
+	name      string
+	arguments []*argument
+	pos       int
+}
+
+type valueKind int
+
+const (
+	valueVariable valueKind = iota
+	valueInt
+	valueFloat
+	valueString
+	valueBoolean
+	valueNull
+	valueEnum
+	valueList
+	valueObject
+)
+
+type value struct {
+	kind   valueKind
+	raw    string // the variable name, literal or enum value
+	list   []*value
+	fields []*argument
+	pos    int
+}
+
+// String describes the value for error messages
+func (v *value) String() string {
+	switch v.kind {
+	case valueVariable:
+		return "$" + v.raw
+	case valueList:
+		return "a list"
+	case valueObject:
+		return "an object"
+	default:
+		return v.raw
+	}
+}
+
+// parser builds a document from the tokens of a query. Errors end parsing
+// at the first one, so they are raised as panics and recovered in parse.
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+// syntaxError carries a parse error up to parse
+type syntaxError struct{ err *Error }
+
+// parse parses a query into a document
+func parse(src string) (doc *document, err *Error) {
+	p := &parser{lex: &lexer{src: src}}
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(syntaxError)
+			if !ok {
+				panic(r)
+			}
+			doc, err = nil, se.err
+		}
+	}()
+
+	p.advance()
+	doc = &document{}
+	if p.tok.kind == tokenEOF {
+		p.fail(p.tok.pos, "the query is empty")
+	}
+	for p.tok.kind != tokenEOF {
+		switch {
+		case p.is("{"):
+			doc.operations = append(doc.operations, p.operation())
+		case p.tok.kind == tokenName && (p.tok.value == "query" || p.tok.value == "mutation" || p.tok.value == "subscription"):
+			doc.operations = append(doc.operations, p.operation())
+		case p.tok.kind == tokenName && p.tok.value == "fragment":
+			doc.fragments = append(doc.fragments, p.fragment())
+		default:
+			p.fail(p.tok.pos, "expected an operation or fragment, found %s", p.tok)
+		}
+	}
+	return doc, nil
+}
+
+func (p *parser) fail(pos int, format string, args ...any) {
+	panic(syntaxError{newError(p.lex.src, pos, format, args...)})
+}
+
+func (p *parser) advance() {
+	tok, err := p.lex.next()
+	if err != nil {
+		panic(syntaxError{err})
+	}
+	p.tok = tok
+}
+
+// is reports whether the current token is the punctuator s
+func (p *parser) is(s string) bool {
+	return p.tok.kind == tokenPunct && p.tok.value == s
+}
+
+func (p *parser) expect(s string) {
+	if !p.is(s) {
+		p.fail(p.tok.pos, "expected %q, found %s", s, p.tok)
+	}
+	p.advance()
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokenName {
+		p.fail(p.tok.pos, "expected a name, found %s", p.tok)
+	}
+	name := p.tok.value
+	p.advance()
+	return name
+}
+
+func (p *parser) operation() *operation {
+	op := &operation{kind: "query", pos: p.tok.pos}
+	if p.is("{") {
+		op.selections = p.selectionSet()
+		return op
+	}
+
+	op.kind = p.name()
+	if p.tok.kind == tokenName {
+		op.name = p.name()
+	}
+	if p.is("(") {
+		p.advance()
+		for !p.is(")") {
+			op.variables = append(op.variables, p.variableDef())
+		}
+		p.advance()
+	}
+	op.directives = p.directives(false)
+	op.selections = p.selectionSet()
+	return op
+}
+
+func (p *parser) variableDef() *variableDef {
+	v := &variableDef{pos: p.tok.pos}
+	p.expect("$")
+	v.name = p.name()
+	p.expect(":")
+	v.typ = p.typeRef()
+	if p.is("=") {
+		p.advance()
+		v.defaultValue = p.value(true)
+	}
+	p.directives(true)
+	return v
+}
+
+func (p *parser) typeRef() *TypeRef {
+	var t *TypeRef
+	if p.is("[") {
+		p.advance()
+		t = &TypeRef{Kind: kindList, OfType: p.typeRef()}
+		p.expect("]")
+	} else {
+		t = &TypeRef{Name: p.name()}
+	}
+	if p.is("!") {
+		p.advance()
+		t = &TypeRef{Kind: kindNonNull, OfType: t}
+	}
+	return t
+}
+
+func (p *parser) fragment() *fragment {
+	f := &fragment{pos: p.tok.pos}
+	p.advance()
+	if p.tok.kind == tokenName && p.tok.value == "on" {
+		p.fail(p.tok.pos, "expected a fragment name, found %s", p.tok)
+	}
+	f.name = p.name()
+	if p.tok.kind != tokenName || p.tok.value != "on" {
+		p.fail(p.tok.pos, `expected "on", found %s`, p.tok)
+	}
+	p.advance()
+	f.typeCondition = p.name()
+	f.directives = p.directives(false)
+	f.selections = p.selectionSet()
+	return f
+}
+
+func (p *parser) selectionSet() []*selection {
+	p.expect("{")
+	if p.is("}") {
+		p.fail(p.tok.pos, "expected a field, found %s", p.tok)
+	}
+	var selections []*selection
+	for !p.is("}") {
+		selections = append(selections, p.selection())
+	}
+	p.advance()
+	return selections
+}
+
+func (p *parser) selection() *selection {
+	sel := &selection{pos: p.tok.pos}
+	if p.is("...") {
+		p.advance()
+		switch {
+		case p.tok.kind == tokenName && p.tok.value == "on":
+			p.advance()
+			sel.kind = selectInline
+			sel.typeCondition = p.name()
+		case p.tok.kind == tokenName:
+			sel.kind = selectSpread
+			sel.name = p.name()
+			sel.directives = p.directives(false)
+			return sel
+		default:
+			sel.kind = selectInline
+		}
+		sel.directives = p.directives(false)
+		sel.selections = p.selectionSet()
+		return sel
+	}
+
+	sel.kind = selectField
+	sel.name = p.name()
+	if p.is(":") {
+		p.advance()
+		sel.alias, sel.name = sel.name, p.name()
+	}
+	if p.is("(") {
+		sel.arguments = p.arguments(false)
+	}
+	sel.directives = p.directives(false)
+	if p.is("{") {
+		sel.selections = p.selectionSet()
+	}
+	return sel
+}
+
+func (p *parser) arguments(constant bool) []*argument {
+	p.expect("(")
+	if p.is(")") {
+		p.fail(p.tok.pos, "expected an argument, found %s", p.tok)
+	}
+	var args []*argument
+	for !p.is(")") {
+		arg := &argument{pos: p.tok.pos}
+		arg.name = p.name()
+		p.expect(":")
+		arg.value = p.value(constant)
+		args = append(args, arg)
+	}
+	p.advance()
+	return args
+}
+
+func (p *parser) directives(constant bool) []*directive {
+	var directives []*directive
+	for p.is("@") {
+		d := &directive{pos: p.tok.pos}
+		p.advance()
+		d.name = p.name()
+		if p.is("(") {
+			d.arguments = p.arguments(constant)
+		}
+		directives = append(directives, d)
+	}
+	return directives
+}
+
+// value parses a value; constant values, such as variable defaults, can't
+// contain variables
+func (p *parser) value(constant bool) *value {
+	v := &value{pos: p.tok.pos, raw: p.tok.value}
+	switch p.tok.kind {
+	case tokenInt:
+		v.kind = valueInt
+	case tokenFloat:
+		v.kind = valueFloat
+	case tokenString:
+		v.kind = valueString
+	case tokenName:
+		switch p.tok.value {
+		case "true", "false":
+			v.kind = valueBoolean
+		case "null":
+			v.kind = valueNull
+		default:
+			v.kind = valueEnum
+		}
+	case tokenPunct:
+		switch p.tok.value {
+		case "$":
+			if constant {
+				p.fail(p.tok.pos, "variables can't be used here")
+			}
+			p.advance()
+			v.kind = valueVariable
+			v.raw = p.name()
+			return v
+		case "[":
+			p.advance()
+			v.kind = valueList
+			for !p.is("]") {
+				v.list = append(v.list, p.value(constant))
+			}
+			p.advance()
+			return v
+		case "{":
+			p.advance()
+			v.kind = valueObject
+			for !p.is("}") {
+				field := &argument{pos: p.tok.pos}
+				field.name = p.name()
+				p.expect(":")
+				field.value = p.value(constant)
+				v.fields = append(v.fields, field)
+			}
+			p.advance()
+			return v
+		}
+		p.fail(p.tok.pos, "expected a value, found %s", p.tok)
+	default:
+		p.fail(p.tok.pos, "expected a value, found %s", p.tok)
+	}
+	p.advance()
+	return v
+}
@@ -0,0 +1,75 @@
+package graphql
+
+import "testing"
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"empty", "", "1:1: the query is empty"},
+		{"only a comment", "# nothing yet", "1:14: the query is empty"},
+		{"unclosed selection set", "{ user(id: 1) { name }", "1:23: expected a name, found the end of the query"},
+		{"extra closing brace", "{ user(id: 1) { name } } }", `1:26: expected an operation or fragment, found "}"`},
+		{"unknown keyword", "subscribe { a }", `1:1: expected an operation or fragment, found name "subscribe"`},
+		{"unterminated string on a later line", "query {\n  user(id: \"1) { name }\n}", "2:12: unterminated string"},
+		{"variable without a type", "query Q($id ID) { a }", `1:13: expected ":", found name "ID"`},
+		{"missing argument value", "{ a(b: ) }", `1:8: expected a value, found ")"`},
+		{"unclosed list", "{ a(b: [1, 2) }", `1:13: expected a value, found ")"`},
+		{"invalid number", "{ a(b: 1.) }", "1:8: invalid number"},
+		{"empty selection set", "{ }", `1:3: expected a field, found "}"`},
+		{"directive without a name", "{ a @ }", `1:7: expected a name, found "}"`},
+		{"fragment named on", "{ a }\nfragment on User { b }", `2:10: expected a fragment name, found name "on"`},
+		{"columns count characters, not bytes", `{ a(b: "é", c: ) }`, `1:16: expected a value, found ")"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.query)
+			if err == nil {
+				t.Fatalf("parse(%q) succeeded", tt.query)
+			}
+			if got := err.Error(); got != tt.want {
+				t.Errorf("parse(%q) error = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc, err := parse(`# A comment
+query Users($role: Role = ADMIN, $first: Int!) @cached {
+  users(role: $role, first: $first) { id ...Names ... on User @include(if: true) { posts { title } } }
+}
+mutation { createUser(input: {name: "Ada", tags: ["a", """b"""]}) { id } }
+fragment Names on User { name, nick: name }`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc.operations) != 2 || len(doc.fragments) != 1 {
+		t.Fatalf("parsed %d operations and %d fragments, want 2 and 1", len(doc.operations), len(doc.fragments))
+	}
+
+	users := doc.operations[0]
+	if users.kind != "query" || users.name != "Users" || len(users.variables) != 2 || len(users.directives) != 1 {
+		t.Errorf("operation = %+v", users)
+	}
+	if typ := users.variables[1].typ.String(); typ != "Int!" {
+		t.Errorf("$first is of type %q, want Int!", typ)
+	}
+	sel := users.selections[0]
+	if sel.name != "users" || len(sel.arguments) != 2 || len(sel.selections) != 3 {
+		t.Fatalf("users selection = %+v", sel)
+	}
+	if kinds := [3]selectionKind{sel.selections[0].kind, sel.selections[1].kind, sel.selections[2].kind}; kinds != [3]selectionKind{selectField, selectSpread, selectInline} {
+		t.Errorf("selection kinds = %v", kinds)
+	}
+
+	if create := doc.operations[1]; create.kind != "mutation" || create.name != "" {
+		t.Errorf("operation = %+v", create)
+	}
+	if f := doc.fragments[0]; f.name != "Names" || f.typeCondition != "User" || len(f.selections) != 2 {
+		t.Errorf("fragment = %+v", f)
+	}
+}
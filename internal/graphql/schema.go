@@ -0,0 +1,332 @@
+// Package graphql introspects GraphQL schemas and checks queries against them.
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+)
+
+// Kinds of types, as reported by introspection
+const (
+	kindScalar      = "SCALAR"
+	kindObject      = "OBJECT"
+	kindInterface   = "INTERFACE"
+	kindUnion       = "UNION"
+	kindEnum        = "ENUM"
+	kindInputObject = "INPUT_OBJECT"
+	kindList        = "LIST"
+	kindNonNull     = "NON_NULL"
+)
+
+// IntrospectionQuery asks an endpoint for its schema
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives { name args { ...InputValue } }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  enumValues(includeDeprecated: true) { name description isDeprecated deprecationReason }
+  possibleTypes { name }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType { kind name }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// Schema is the schema of a GraphQL endpoint, as obtained by introspection
+type Schema struct {
+	QueryType        string
+	MutationType     string
+	SubscriptionType string
+	Types            map[string]*Type
+	Directives       map[string]*Directive
+}
+
+// Type is a named type of a schema
+type Type struct {
+	Kind          string        `json:"kind"`
+	Name          string        `json:"name"`
+	Description   string        `json:"description"`
+	Fields        []*Field      `json:"fields"`
+	InputFields   []*InputValue `json:"inputFields"`
+	EnumValues    []*EnumValue  `json:"enumValues"`
+	PossibleTypes []*TypeRef    `json:"possibleTypes"`
+}
+
+// Field is a field of an object or interface type
+type Field struct {
+	Name              string        `json:"name"`
+	Description       string        `json:"description"`
+	Args              []*InputValue `json:"args"`
+	Type              *TypeRef      `json:"type"`
+	IsDeprecated      bool          `json:"isDeprecated"`
+	DeprecationReason string        `json:"deprecationReason"`
+}
+
+// InputValue is an argument or a field of an input object type
+type InputValue struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Type         *TypeRef `json:"type"`
+	DefaultValue *string  `json:"defaultValue"`
+}
+
+// EnumValue is a value of an enum type
+type EnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// Directive is a directive the schema accepts, such as @include
+type Directive struct {
+	Name string        `json:"name"`
+	Args []*InputValue `json:"args"`
+}
+
+// TypeRef refers to a type, wrapped in lists and non-null markers
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// String returns the type as written in GraphQL, e.g. [User!]!
+func (t *TypeRef) String() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.Kind == kindNonNull:
+		return t.OfType.String() + "!"
+	case t.Kind == kindList:
+		return "[" + t.OfType.String() + "]"
+	default:
+		return t.Name
+	}
+}
+
+// NamedType returns the name of the type inside the lists and non-null markers
+func (t *TypeRef) NamedType() string {
+	for t != nil && t.OfType != nil {
+		t = t.OfType
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
+// required reports whether a value of the type must be given
+func (v *InputValue) required() bool {
+	return v.Type != nil && v.Type.Kind == kindNonNull && v.DefaultValue == nil
+}
+
+// String returns the field as it's listed, e.g. user(id: ID!): User
+func (f *Field) String() string {
+	var b strings.Builder
+	b.WriteString(f.Name)
+	if len(f.Args) > 0 {
+		args := make([]string, len(f.Args))
+		for i, arg := range f.Args {
+			args[i] = arg.Name + ": " + arg.Type.String()
+		}
+		b.WriteString("(" + strings.Join(args, ", ") + ")")
+	}
+	b.WriteString(": " + f.Type.String())
+	return b.String()
+}
+
+// IsComposite reports whether fields are selected on the type
+func (t *Type) IsComposite() bool {
+	return t.Kind == kindObject || t.Kind == kindInterface || t.Kind == kindUnion
+}
+
+// isLeaf reports whether the type is a scalar or enum, which has no fields
+func (t *Type) isLeaf() bool {
+	return t.Kind == kindScalar || t.Kind == kindEnum
+}
+
+// isInput reports whether the type can be the type of a variable
+func (t *Type) isInput() bool {
+	return t.Kind == kindScalar || t.Kind == kindEnum || t.Kind == kindInputObject
+}
+
+// typenameField is the __typename field every composite type has
+var typenameField = &Field{Name: "__typename", Type: &TypeRef{Kind: kindNonNull, OfType: &TypeRef{Kind: kindScalar, Name: "String"}}}
+
+// field returns the field of a type with the given name, including the
+// introspection fields, or nil if it has none
+func (s *Schema) field(t *Type, name string) *Field {
+	if name == "__typename" && t.IsComposite() {
+		return typenameField
+	}
+	if t.Name == s.QueryType {
+		switch name {
+		case "__schema":
+			return &Field{Name: name, Type: &TypeRef{Kind: kindNonNull, OfType: &TypeRef{Kind: kindObject, Name: "__Schema"}}}
+		case "__type":
+			return &Field{
+				Name: name,
+				Args: []*InputValue{{Name: "name", Type: &TypeRef{Kind: kindNonNull, OfType: &TypeRef{Kind: kindScalar, Name: "String"}}}},
+				Type: &TypeRef{Kind: kindObject, Name: "__Type"},
+			}
+		}
+	}
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// rootType returns the type operations of a kind start from
+func (s *Schema) rootType(kind string) *Type {
+	switch kind {
+	case "mutation":
+		return s.Types[s.MutationType]
+	case "subscription":
+		return s.Types[s.SubscriptionType]
+	default:
+		return s.Types[s.QueryType]
+	}
+}
+
+// introspectionResponse is the body of the answer to IntrospectionQuery
+type introspectionResponse struct {
+	Data *struct {
+		Schema *struct {
+			QueryType        *TypeRef     `json:"queryType"`
+			MutationType     *TypeRef     `json:"mutationType"`
+			SubscriptionType *TypeRef     `json:"subscriptionType"`
+			Types            []*Type      `json:"types"`
+			Directives       []*Directive `json:"directives"`
+		} `json:"__schema"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// ParseSchema reads the schema from the response to IntrospectionQuery
+func ParseSchema(data []byte) (*Schema, error) {
+	var resp introspectionResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("invalid introspection response: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, errors.New(resp.Errors[0].Message)
+	}
+	if resp.Data == nil || resp.Data.Schema == nil || resp.Data.Schema.QueryType == nil {
+		return nil, errors.New("the response has no schema")
+	}
+
+	raw := resp.Data.Schema
+	schema := &Schema{
+		QueryType:  raw.QueryType.Name,
+		Types:      make(map[string]*Type, len(raw.Types)),
+		Directives: make(map[string]*Directive, len(raw.Directives)),
+	}
+	if raw.MutationType != nil {
+		schema.MutationType = raw.MutationType.Name
+	}
+	if raw.SubscriptionType != nil {
+		schema.SubscriptionType = raw.SubscriptionType.Name
+	}
+	for _, t := range raw.Types {
+		schema.Types[t.Name] = t
+	}
+	for _, d := range raw.Directives {
+		schema.Directives[d.Name] = d
+	}
+	return schema, nil
+}
+
+// NewIntrospectionRequest returns a request for the schema of the endpoint
+// req is sent to, with its headers, auth and settings
+func NewIntrospectionRequest(req *http.Request) *http.Request {
+	introspection := req.Clone()
+	introspection.Method = "POST"
+	introspection.BodyType = http.BodyGraphQL
+	introspection.GraphQL = http.GraphQL{Query: IntrospectionQuery, OperationName: "IntrospectionQuery"}
+	return introspection
+}
+
+// Introspect sends an introspection request and reads the schema from the
+// response. Large schemas streamed to disk are read back from their file,
+// which is then removed.
+func Introspect(ctx context.Context, client *http.Client, req *http.Request) (*Schema, error) {
+	resp := client.Execute(ctx, req)
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	body := resp.Body
+	if resp.BodyFile != "" {
+		data, err := os.ReadFile(resp.BodyFile)
+		os.Remove(resp.BodyFile)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+
+	schema, err := ParseSchema(body)
+	if err != nil && !resp.IsSuccess() {
+		return nil, fmt.Errorf("introspection failed with %s: %w", resp.Status, err)
+	}
+	return schema, err
+}
@@ -0,0 +1,177 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+)
+
+func TestParseSchema(t *testing.T) {
+	schema := loadSchema(t)
+
+	if schema.QueryType != "Query" || schema.MutationType != "Mutation" || schema.SubscriptionType != "" {
+		t.Errorf("root types = %q, %q, %q", schema.QueryType, schema.MutationType, schema.SubscriptionType)
+	}
+	if len(schema.Types) != 12 {
+		t.Errorf("parsed %d types, want 12", len(schema.Types))
+	}
+	for _, name := range []string{"include", "skip", "deprecated"} {
+		if schema.Directives[name] == nil {
+			t.Errorf("directive @%s is missing", name)
+		}
+	}
+
+	user := schema.Types["User"]
+	if user == nil || user.Kind != kindObject || user.Description != "A registered user" {
+		t.Fatalf("User = %+v", user)
+	}
+	fields := make([]string, len(user.Fields))
+	for i, f := range user.Fields {
+		fields[i] = f.String()
+	}
+	want := "id: ID!, name: String, email: String, contact: String, role: Role, posts(first: Int): [Post]"
+	if got := strings.Join(fields, ", "); got != want {
+		t.Errorf("User fields = %s, want %s", got, want)
+	}
+	if email := schema.field(user, "email"); !email.IsDeprecated || email.DeprecationReason != "Use contact" {
+		t.Errorf("User.email = %+v, want deprecated", email)
+	}
+
+	users := schema.field(schema.rootType("query"), "users")
+	if got := users.Type.String(); got != "[User!]!" {
+		t.Errorf("Query.users is of type %s, want [User!]!", got)
+	}
+	if users.Type.NamedType() != "User" {
+		t.Errorf("Query.users named type = %s, want User", users.Type.NamedType())
+	}
+	if first := users.Args[0]; first.DefaultValue == nil || *first.DefaultValue != "10" || first.required() {
+		t.Errorf("Query.users(first) = %+v, want an optional argument defaulting to 10", first)
+	}
+
+	if node := schema.Types["Node"]; node.Kind != kindInterface || len(node.PossibleTypes) != 2 || !node.IsComposite() {
+		t.Errorf("Node = %+v", node)
+	}
+	if role := schema.Types["Role"]; len(role.EnumValues) != 3 || !role.EnumValues[2].IsDeprecated || !role.isLeaf() {
+		t.Errorf("Role = %+v", role)
+	}
+	if input := schema.Types["UserInput"]; len(input.InputFields) != 2 || !input.InputFields[0].required() || input.InputFields[1].required() {
+		t.Errorf("UserInput = %+v", input)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not JSON", `<html>`, "invalid introspection response"},
+		{"GraphQL error", `{"errors": [{"message": "introspection is disabled"}]}`, "introspection is disabled"},
+		{"no data", `{"data": null}`, "the response has no schema"},
+		{"no query type", `{"data": {"__schema": {"types": []}}}`, "the response has no schema"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseSchema([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestIntrospect(t *testing.T) {
+	fixture, err := os.ReadFile("testdata/introspection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		var body struct {
+			Query         string `json:"query"`
+			OperationName string `json:"operationName"`
+		}
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &body); err != nil || body.Query != IntrospectionQuery || body.OperationName != "IntrospectionQuery" {
+			nethttp.Error(w, "not an introspection query", nethttp.StatusBadRequest)
+			return
+		}
+		if r.Method != "POST" || r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			w.Write([]byte(`{"errors": [{"message": "not signed in"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	client, err := http.NewClient(http.DefaultTransportConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The introspection request keeps the headers and auth of the request
+	req := &http.Request{
+		Method:   "GET",
+		URL:      server.URL,
+		BodyType: http.BodyJSON,
+		Body:     `{"ignored": true}`,
+		Auth:     http.Auth{Type: http.AuthBearer, Token: "abc"},
+	}
+	schema, err := Introspect(context.Background(), client, NewIntrospectionRequest(req))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Types["User"] == nil {
+		t.Error("the introspected schema has no User type")
+	}
+
+	req.Auth = http.Auth{}
+	_, err = Introspect(context.Background(), client, NewIntrospectionRequest(req))
+	if err == nil || !strings.Contains(err.Error(), "not signed in") {
+		t.Errorf("error = %v, want the GraphQL error", err)
+	}
+}
+
+func TestTypeAt(t *testing.T) {
+	schema := loadSchema(t)
+
+	tests := []struct {
+		name  string
+		query string // | marks the offset
+		want  string
+	}{
+		{"outside a selection set", "|query { user }", ""},
+		{"query shorthand", "{ |", "Query"},
+		{"query keyword", "query Q { u|", "Query"},
+		{"mutation", "mutation { |", "Mutation"},
+		{"nested field", "{ user(id: 1) { posts { |", "Post"},
+		{"field with an object argument", `mutation { createUser(input: {name: "a"}) { |`, "User"},
+		{"back out of a selection set", "{ user(id: 1) { posts { title } | } }", "User"},
+		{"inline fragment", `{ search(q: "a") { ... on Post { |`, "Post"},
+		{"fragment definition", "fragment F on User { |", "User"},
+		{"unknown field", "{ viewer { |", ""},
+		{"unfinished query", "{ user(id: 1) { posts(first: 2) { author { |", "User"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := strings.Index(tt.query, "|")
+			query := strings.Replace(tt.query, "|", "", 1)
+			got := ""
+			if typ := schema.TypeAt(query, offset); typ != nil {
+				got = typ.Name
+			}
+			if got != tt.want {
+				t.Errorf("TypeAt(%q, %d) = %q, want %q", query, offset, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,489 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+)
+
+// Validate checks a GraphQL operation, returning the syntax error of its
+// query or the problems found in it. Without a schema only the query itself
+// is checked: its syntax, fragments, variables and operation name. With one,
+// fields, arguments, selections, types and values are checked against it too.
+func Validate(schema *Schema, op http.GraphQL) []*Error {
+	doc, err := parse(op.Query)
+	if err != nil {
+		return []*Error{err}
+	}
+
+	v := &validator{schema: schema, src: op.Query, doc: doc, fragments: make(map[string]*fragment)}
+	v.checkDefinitions()
+	for _, f := range doc.fragments {
+		v.checkSelections(v.typeCondition(f.typeCondition, f.pos), f.selections)
+	}
+	for _, o := range doc.operations {
+		v.checkOperation(o)
+	}
+	v.checkFragmentCycles()
+	v.checkOperationName(strings.TrimSpace(op.OperationName))
+	v.checkVariableValues(op)
+
+	// In query order, with the errors about no place in it last
+	sort.SliceStable(v.errs, func(i, j int) bool {
+		a, b := v.errs[i], v.errs[j]
+		if (a.Line == 0) != (b.Line == 0) {
+			return b.Line == 0
+		}
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+	return v.errs
+}
+
+// validator collects the problems found in a parsed query
+type validator struct {
+	schema    *Schema
+	src       string
+	doc       *document
+	fragments map[string]*fragment
+	errs      []*Error
+}
+
+func (v *validator) errorf(pos int, format string, args ...any) {
+	v.errs = append(v.errs, newError(v.src, pos, format, args...))
+}
+
+// checkDefinitions checks that operations and fragments have unique names,
+// and that an anonymous operation is the only one
+func (v *validator) checkDefinitions() {
+	operations := make(map[string]bool)
+	for _, o := range v.doc.operations {
+		switch {
+		case o.name == "" && len(v.doc.operations) > 1:
+			v.errorf(o.pos, "an anonymous operation must be the only operation")
+		case o.name != "" && operations[o.name]:
+			v.errorf(o.pos, "operation %q is defined more than once", o.name)
+		}
+		operations[o.name] = true
+	}
+	for _, f := range v.doc.fragments {
+		if v.fragments[f.name] != nil {
+			v.errorf(f.pos, "fragment %q is defined more than once", f.name)
+			continue
+		}
+		v.fragments[f.name] = f
+	}
+}
+
+// typeCondition returns the type a fragment applies to, reporting it if it
+// doesn't exist or has no fields
+func (v *validator) typeCondition(name string, pos int) *Type {
+	if v.schema == nil || name == "" {
+		return nil
+	}
+	t := v.schema.Types[name]
+	switch {
+	case t == nil:
+		v.errorf(pos, "unknown type %q", name)
+	case !t.IsComposite():
+		v.errorf(pos, "fragment can't apply to %s type %q", strings.ToLower(t.Kind), name)
+		return nil
+	}
+	return t
+}
+
+func (v *validator) checkOperation(o *operation) {
+	var root *Type
+	if v.schema != nil {
+		if root = v.schema.rootType(o.kind); root == nil {
+			v.errorf(o.pos, "the schema doesn't support %ss", o.kind)
+		}
+	}
+	v.checkDirectives(o.directives)
+
+	// Variables must be defined once, with an input type, and used
+	defined := make(map[string]*variableDef)
+	for _, d := range o.variables {
+		if defined[d.name] != nil {
+			v.errorf(d.pos, "variable $%s is defined more than once", d.name)
+			continue
+		}
+		defined[d.name] = d
+		if v.schema != nil {
+			if t := v.schema.Types[d.typ.NamedType()]; t == nil {
+				v.errorf(d.pos, "unknown type %q", d.typ.NamedType())
+			} else if !t.isInput() {
+				v.errorf(d.pos, "variable $%s can't be of %s type %q", d.name, strings.ToLower(t.Kind), t.Name)
+			} else if d.defaultValue != nil {
+				v.checkValue(d.defaultValue, d.typ)
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, usage := range v.variableUsages(o) {
+		used[usage.raw] = true
+		if defined[usage.raw] == nil {
+			v.errorf(usage.pos, "variable $%s is not defined%s", usage.raw, operationLabel(o))
+		}
+	}
+	for _, d := range o.variables {
+		if !used[d.name] {
+			v.errorf(d.pos, "variable $%s is never used%s", d.name, operationLabel(o))
+		}
+	}
+
+	v.checkSelections(root, o.selections)
+}
+
+// operationLabel names an operation in error messages
+func operationLabel(o *operation) string {
+	if o.name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" by operation %q", o.name)
+}
+
+// checkSelections checks the fields selected on a type, which is nil when
+// it isn't known
+func (v *validator) checkSelections(parent *Type, selections []*selection) {
+	for _, sel := range selections {
+		v.checkDirectives(sel.directives)
+		switch sel.kind {
+		case selectSpread:
+			if v.fragments[sel.name] == nil {
+				v.errorf(sel.pos, "unknown fragment %q", sel.name)
+			}
+		case selectInline:
+			t := parent
+			if sel.typeCondition != "" {
+				t = v.typeCondition(sel.typeCondition, sel.pos)
+			}
+			v.checkSelections(t, sel.selections)
+		case selectField:
+			v.checkField(parent, sel)
+		}
+	}
+}
+
+func (v *validator) checkField(parent *Type, sel *selection) {
+	if parent == nil {
+		v.checkSelections(nil, sel.selections)
+		return
+	}
+
+	field := v.schema.field(parent, sel.name)
+	if field == nil {
+		v.errorf(sel.pos, "type %q has no field %q", parent.Name, sel.name)
+		v.checkSelections(nil, sel.selections)
+		return
+	}
+	v.checkArguments(sel.arguments, field.Args, fmt.Sprintf("field %q", parent.Name+"."+sel.name), "argument", sel.pos)
+
+	t := v.schema.Types[field.Type.NamedType()]
+	switch {
+	case t == nil:
+		v.checkSelections(nil, sel.selections)
+	case t.isLeaf() && len(sel.selections) > 0:
+		v.errorf(sel.pos, "field %q of type %q has no subfields to select", sel.name, field.Type)
+	case t.IsComposite() && len(sel.selections) == 0:
+		v.errorf(sel.pos, "field %q of type %q needs a selection of subfields", sel.name, field.Type)
+	default:
+		v.checkSelections(t, sel.selections)
+	}
+}
+
+// checkArguments checks the arguments given to a field or directive, or the
+// fields of an input object, against those it accepts
+func (v *validator) checkArguments(args []*argument, accepted []*InputValue, owner, noun string, pos int) {
+	given := make(map[string]bool)
+	for _, arg := range args {
+		if given[arg.name] {
+			v.errorf(arg.pos, "%s %q is given more than once", noun, arg.name)
+			continue
+		}
+		given[arg.name] = true
+
+		def := findInputValue(accepted, arg.name)
+		if def == nil {
+			v.errorf(arg.pos, "%s has no %s %q", owner, noun, arg.name)
+			continue
+		}
+		v.checkValue(arg.value, def.Type)
+	}
+	for _, def := range accepted {
+		if def.required() && !given[def.Name] {
+			v.errorf(pos, "%s needs %s %q of type %q", owner, noun, def.Name, def.Type)
+		}
+	}
+}
+
+func (v *validator) checkDirectives(directives []*directive) {
+	for _, d := range directives {
+		if v.schema == nil || len(v.schema.Directives) == 0 {
+			continue
+		}
+		def := v.schema.Directives[d.name]
+		if def == nil {
+			v.errorf(d.pos, "unknown directive @%s", d.name)
+			continue
+		}
+		v.checkArguments(d.arguments, def.Args, "directive @"+d.name, "argument", d.pos)
+	}
+}
+
+func findInputValue(values []*InputValue, name string) *InputValue {
+	for _, iv := range values {
+		if iv.Name == name {
+			return iv
+		}
+	}
+	return nil
+}
+
+// checkValue checks that a literal value can be of a type. Variables are
+// left to the server, which knows the values they are given.
+func (v *validator) checkValue(val *value, typ *TypeRef) {
+	if val.kind == valueVariable || typ == nil {
+		return
+	}
+	if typ.Kind == kindNonNull {
+		if val.kind == valueNull {
+			v.errorf(val.pos, "expected a value of type %q, found null", typ)
+			return
+		}
+		typ = typ.OfType
+	}
+	if val.kind == valueNull {
+		return
+	}
+	if typ.Kind == kindList {
+		// A single value is taken as a list of one
+		if val.kind != valueList {
+			v.checkValue(val, typ.OfType)
+			return
+		}
+		for _, item := range val.list {
+			v.checkValue(item, typ.OfType)
+		}
+		return
+	}
+
+	t := v.schema.Types[typ.Name]
+	if t == nil {
+		return
+	}
+	mismatch := func() {
+		v.errorf(val.pos, "expected a value of type %q, found %s", typ, val)
+	}
+	switch t.Kind {
+	case kindScalar:
+		if !scalarAccepts(t.Name, val.kind) {
+			mismatch()
+		}
+	case kindEnum:
+		if val.kind != valueEnum {
+			mismatch()
+			return
+		}
+		for _, ev := range t.EnumValues {
+			if ev.Name == val.raw {
+				return
+			}
+		}
+		v.errorf(val.pos, "enum %q has no value %s", t.Name, val.raw)
+	case kindInputObject:
+		if val.kind != valueObject {
+			mismatch()
+			return
+		}
+		v.checkArguments(val.fields, t.InputFields, fmt.Sprintf("input type %q", t.Name), "field", val.pos)
+	}
+}
+
+// scalarAccepts reports whether a built-in scalar accepts a literal of the
+// given kind. Custom scalars accept any literal.
+func scalarAccepts(scalar string, kind valueKind) bool {
+	switch scalar {
+	case "Int":
+		return kind == valueInt
+	case "Float":
+		return kind == valueInt || kind == valueFloat
+	case "String":
+		return kind == valueString
+	case "Boolean":
+		return kind == valueBoolean
+	case "ID":
+		return kind == valueString || kind == valueInt
+	default:
+		return true
+	}
+}
+
+// variableUsages returns the variables used by an operation, including
+// those in the fragments it spreads
+func (v *validator) variableUsages(o *operation) []*value {
+	var usages []*value
+	visited := make(map[string]bool)
+
+	var addValue func(val *value)
+	addValue = func(val *value) {
+		switch val.kind {
+		case valueVariable:
+			usages = append(usages, val)
+		case valueList:
+			for _, item := range val.list {
+				addValue(item)
+			}
+		case valueObject:
+			for _, f := range val.fields {
+				addValue(f.value)
+			}
+		}
+	}
+	addDirectives := func(directives []*directive) {
+		for _, d := range directives {
+			for _, arg := range d.arguments {
+				addValue(arg.value)
+			}
+		}
+	}
+
+	var addSelections func(selections []*selection)
+	addSelections = func(selections []*selection) {
+		for _, sel := range selections {
+			addDirectives(sel.directives)
+			for _, arg := range sel.arguments {
+				addValue(arg.value)
+			}
+			if sel.kind == selectSpread {
+				if f := v.fragments[sel.name]; f != nil && !visited[sel.name] {
+					visited[sel.name] = true
+					addDirectives(f.directives)
+					addSelections(f.selections)
+				}
+				continue
+			}
+			addSelections(sel.selections)
+		}
+	}
+
+	addDirectives(o.directives)
+	addSelections(o.selections)
+	return usages
+}
+
+// checkFragmentCycles reports fragments that spread themselves, directly or
+// through others, and fragments no operation uses
+func (v *validator) checkFragmentCycles() {
+	used := make(map[string]bool)
+	var mark func(selections []*selection)
+	mark = func(selections []*selection) {
+		for _, sel := range selections {
+			if sel.kind == selectSpread {
+				if f := v.fragments[sel.name]; f != nil && !used[sel.name] {
+					used[sel.name] = true
+					mark(f.selections)
+				}
+				continue
+			}
+			mark(sel.selections)
+		}
+	}
+	for _, o := range v.doc.operations {
+		mark(o.selections)
+	}
+
+	for _, f := range v.doc.fragments {
+		if !used[f.name] {
+			v.errorf(f.pos, "fragment %q is never used", f.name)
+		}
+		if v.spreads(f.selections, f.name, make(map[string]bool)) {
+			v.errorf(f.pos, "fragment %q spreads itself", f.name)
+		}
+	}
+}
+
+// spreads reports whether selections spread the named fragment, directly or
+// through other fragments
+func (v *validator) spreads(selections []*selection, name string, visited map[string]bool) bool {
+	for _, sel := range selections {
+		if sel.kind != selectSpread {
+			if v.spreads(sel.selections, name, visited) {
+				return true
+			}
+			continue
+		}
+		if sel.name == name {
+			return true
+		}
+		if f := v.fragments[sel.name]; f != nil && !visited[sel.name] {
+			visited[sel.name] = true
+			if v.spreads(f.selections, name, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkOperationName checks that the operation to run is known, and given
+// when the query has several
+func (v *validator) checkOperationName(name string) {
+	if name == "" {
+		if len(v.doc.operations) > 1 {
+			v.errs = append(v.errs, &Error{Message: "the query has several operations, so the operation name must be set"})
+		}
+		return
+	}
+	if v.operation(name) == nil {
+		v.errs = append(v.errs, &Error{Message: fmt.Sprintf("the query has no operation %q", name)})
+	}
+}
+
+// operation returns the operation with the given name, or the only one when
+// name is empty
+func (v *validator) operation(name string) *operation {
+	if name == "" {
+		if len(v.doc.operations) == 1 {
+			return v.doc.operations[0]
+		}
+		return nil
+	}
+	for _, o := range v.doc.operations {
+		if o.name == name {
+			return o
+		}
+	}
+	return nil
+}
+
+// checkVariableValues checks that the variables are a JSON object giving a
+// value for every required variable of the operation run. Variables holding
+// {{placeholders}} are only checked once those are substituted.
+func (v *validator) checkVariableValues(op http.GraphQL) {
+	values := make(map[string]json.RawMessage)
+	if text := strings.TrimSpace(op.Variables); text != "" {
+		if err := json.Unmarshal([]byte(text), &values); err != nil || values == nil {
+			if !strings.Contains(text, "{{") {
+				v.errs = append(v.errs, &Error{Message: "the variables must be a JSON object"})
+			}
+			return
+		}
+	}
+
+	o := v.operation(strings.TrimSpace(op.OperationName))
+	if o == nil {
+		return
+	}
+	for _, d := range o.variables {
+		if d.typ.Kind != kindNonNull || d.defaultValue != nil {
+			continue
+		}
+		if value, ok := values[d.name]; !ok || string(value) == "null" {
+			v.errs = append(v.errs, &Error{Message: fmt.Sprintf("variable $%s of type %q has no value", d.name, d.typ)})
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package graphql
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+)
+
+// loadSchema reads the schema from the recorded introspection response
+func loadSchema(t *testing.T) *Schema {
+	t.Helper()
+	data, err := os.ReadFile("testdata/introspection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema, err := ParseSchema(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+// errorStrings returns the messages of errs with their positions
+func errorStrings(errs []*Error) []string {
+	var s []string
+	for _, err := range errs {
+		s = append(s, err.Error())
+	}
+	return s
+}
+
+func TestValidate(t *testing.T) {
+	schema := loadSchema(t)
+
+	tests := []struct {
+		name string
+		op   http.GraphQL
+		want []string
+	}{
+		// Fields and arguments
+		{
+			name: "valid query",
+			op:   http.GraphQL{Query: `{ users(first: 5, role: ADMIN) { id name posts(first: 2) { title } } }`},
+		},
+		{
+			name: "unknown field",
+			op:   http.GraphQL{Query: "{ user(id: 1) { nam } }"},
+			want: []string{`1:17: type "User" has no field "nam"`},
+		},
+		{
+			name: "unknown field on a later line",
+			op:   http.GraphQL{Query: "{\n  user(id: 1) {\n    name\n    age\n  }\n}"},
+			want: []string{`4:5: type "User" has no field "age"`},
+		},
+		{
+			name: "fields of an unknown field aren't checked",
+			op:   http.GraphQL{Query: "{ viewer { anything } }"},
+			want: []string{`1:3: type "Query" has no field "viewer"`},
+		},
+		{
+			name: "unknown argument",
+			op:   http.GraphQL{Query: "{ user(id: 1, foo: 2) { name } }"},
+			want: []string{`1:15: field "Query.user" has no argument "foo"`},
+		},
+		{
+			name: "missing required argument",
+			op:   http.GraphQL{Query: "{ user { name } }"},
+			want: []string{`1:3: field "Query.user" needs argument "id" of type "ID!"`},
+		},
+		{
+			name: "argument given twice",
+			op:   http.GraphQL{Query: "{ user(id: 1, id: 2) { name } }"},
+			want: []string{`1:15: argument "id" is given more than once`},
+		},
+		{
+			name: "introspection fields",
+			op:   http.GraphQL{Query: `{ __typename __schema { types { name } } __type(name: "User") { name } }`},
+		},
+
+		// Leaf and composite selections
+		{
+			name: "object without a selection",
+			op:   http.GraphQL{Query: "{ user(id: 1) }"},
+			want: []string{`1:3: field "user" of type "User" needs a selection of subfields`},
+		},
+		{
+			name: "list of objects without a selection",
+			op:   http.GraphQL{Query: "{ users }"},
+			want: []string{`1:3: field "users" of type "[User!]!" needs a selection of subfields`},
+		},
+		{
+			name: "scalar with a selection",
+			op:   http.GraphQL{Query: "{ user(id: 1) { name { first } } }"},
+			want: []string{`1:17: field "name" of type "String" has no subfields to select`},
+		},
+		{
+			name: "enum with a selection",
+			op:   http.GraphQL{Query: "{ user(id: 1) { role { name } } }"},
+			want: []string{`1:17: field "role" of type "Role" has no subfields to select`},
+		},
+		{
+			name: "union needs fragments",
+			op:   http.GraphQL{Query: `{ search(q: "a") { name } }`},
+			want: []string{`1:20: type "SearchResult" has no field "name"`},
+		},
+		{
+			name: "union and interface fragments",
+			op:   http.GraphQL{Query: `{ search(q: "a") { __typename ... on User { name } ... on Post { title } } node(id: 1) { id ... on Post { title } } }`},
+		},
+		{
+			name: "inline fragment on a leaf type",
+			op:   http.GraphQL{Query: "{ node(id: 1) { ... on Role { x } } }"},
+			want: []string{`1:17: fragment can't apply to enum type "Role"`},
+		},
+
+		// Values
+		{
+			name: "unknown enum value",
+			op:   http.GraphQL{Query: "{ users(role: BOSS) { id } }"},
+			want: []string{`1:15: enum "Role" has no value BOSS`},
+		},
+		{
+			name: "wrong scalar",
+			op:   http.GraphQL{Query: `{ users(first: "ten") { id } }`},
+			want: []string{`1:16: expected a value of type "Int", found "ten"`},
+		},
+		{
+			name: "null for a non-null argument",
+			op:   http.GraphQL{Query: "{ user(id: null) { id } }"},
+			want: []string{`1:12: expected a value of type "ID!", found null`},
+		},
+		{
+			name: "input object fields",
+			op:   http.GraphQL{Query: `mutation { a: createUser(input: {role: ADMIN}) { id } b: createUser(input: {name: "a", age: 3}) { id } }`},
+			want: []string{
+				`1:33: input type "UserInput" needs field "name" of type "String!"`,
+				`1:88: input type "UserInput" has no field "age"`,
+			},
+		},
+		{
+			name: "directives",
+			op:   http.GraphQL{Query: "{ user(id: 1) { id @include name @nope } }"},
+			want: []string{
+				`1:20: directive @include needs argument "if" of type "Boolean!"`,
+				`1:34: unknown directive @nope`,
+			},
+		},
+
+		// Fragments
+		{
+			name: "fragment spreads",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...UserFields } }\nfragment UserFields on User { id posts { ...PostFields } }\nfragment PostFields on Post { title }"},
+		},
+		{
+			name: "unknown fragment",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...Missing } }"},
+			want: []string{`1:17: unknown fragment "Missing"`},
+		},
+		{
+			name: "unused fragment",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...A } }\nfragment A on User { id }\nfragment B on User { id }"},
+			want: []string{`3:1: fragment "B" is never used`},
+		},
+		{
+			name: "fragment spreading itself",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...A } }\nfragment A on User { id posts { author { ...A } } }"},
+			want: []string{`2:1: fragment "A" spreads itself`},
+		},
+		{
+			name: "fragment cycle",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...A } }\nfragment A on User { ...B }\nfragment B on User { ...A }"},
+			want: []string{`2:1: fragment "A" spreads itself`, `3:1: fragment "B" spreads itself`},
+		},
+		{
+			name: "fragment on an unknown type",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...A } }\nfragment A on Nope { id }"},
+			want: []string{`2:1: unknown type "Nope"`},
+		},
+		{
+			name: "fragment defined twice",
+			op:   http.GraphQL{Query: "{ user(id: 1) { ...A } }\nfragment A on User { id }\nfragment A on User { name }"},
+			want: []string{`3:1: fragment "A" is defined more than once`},
+		},
+
+		// Variables
+		{
+			name: "variables used directly and in fragments",
+			op: http.GraphQL{
+				Query:     "query Q($id: ID!, $first: Int = 3, $posts: Boolean!) { user(id: $id) { ...F @include(if: $posts) } }\nfragment F on User { posts(first: $first) { title } }",
+				Variables: `{"id": "1", "posts": true}`,
+			},
+		},
+		{
+			name: "undefined and unused variables",
+			op:   http.GraphQL{Query: "query Q($id: ID!, $n: Int) { user(id: $id) { posts(first: $x) { title } } }", Variables: `{"id": "1"}`},
+			want: []string{`1:19: variable $n is never used by operation "Q"`, `1:59: variable $x is not defined by operation "Q"`},
+		},
+		{
+			name: "variable undefined in a fragment",
+			op:   http.GraphQL{Query: "query Q { user(id: 1) { ...F } }\nfragment F on User { posts(first: $n) { title } }"},
+			want: []string{`2:35: variable $n is not defined by operation "Q"`},
+		},
+		{
+			name: "variable defined twice",
+			op:   http.GraphQL{Query: "query Q($id: ID!, $id: ID) { user(id: $id) { id } }", Variables: `{"id": "1"}`},
+			want: []string{`1:19: variable $id is defined more than once`},
+		},
+		{
+			name: "variable types",
+			op:   http.GraphQL{Query: "query Q($u: User, $v: Nope) { a: user(id: $u) { id } b: user(id: $v) { id } }"},
+			want: []string{`1:9: variable $u can't be of object type "User"`, `1:19: unknown type "Nope"`},
+		},
+		{
+			name: "default value of the wrong type",
+			op:   http.GraphQL{Query: `query Q($first: Int = "x") { users(first: $first) { id } }`},
+			want: []string{`1:23: expected a value of type "Int", found "x"`},
+		},
+		{
+			name: "missing variable value",
+			op:   http.GraphQL{Query: "query Q($id: ID!) { user(id: $id) { id } }", Variables: `{"id": null}`},
+			want: []string{`variable $id of type "ID!" has no value`},
+		},
+		{
+			name: "variables that aren't an object",
+			op:   http.GraphQL{Query: "query Q($id: ID!) { user(id: $id) { id } }", Variables: "[1]"},
+			want: []string{"the variables must be a JSON object"},
+		},
+		{
+			name: "variables with placeholders",
+			op:   http.GraphQL{Query: "query Q($id: ID!) { user(id: $id) { id } }", Variables: "{{userVariables}}"},
+		},
+
+		// Operations
+		{
+			name: "unsupported operation type",
+			op:   http.GraphQL{Query: "subscription { users { id } }"},
+			want: []string{"1:1: the schema doesn't support subscriptions"},
+		},
+		{
+			name: "several operations need a name",
+			op:   http.GraphQL{Query: "query A { __typename }\nquery B { __typename }"},
+			want: []string{"the query has several operations, so the operation name must be set"},
+		},
+		{
+			name: "operation name picks one",
+			op:   http.GraphQL{Query: "query A { __typename }\nquery B { __typename }", OperationName: "B"},
+		},
+		{
+			name: "unknown operation name",
+			op:   http.GraphQL{Query: "query A { __typename }", OperationName: "C"},
+			want: []string{`the query has no operation "C"`},
+		},
+		{
+			name: "anonymous operation among others",
+			op:   http.GraphQL{Query: "{ __typename }\nquery B { __typename }", OperationName: "B"},
+			want: []string{"1:1: an anonymous operation must be the only operation"},
+		},
+		{
+			name: "syntax error",
+			op:   http.GraphQL{Query: "{ user(id: 1) { name }"},
+			want: []string{"1:23: expected a name, found the end of the query"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorStrings(Validate(schema, tt.op))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q)\n got  %q\n want %q", tt.op.Query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateWithoutSchema(t *testing.T) {
+	op := http.GraphQL{Query: "query Q($a: Int) { anything(x: $b) { ...F } }\nfragment G on Whatever { y }"}
+	want := []string{
+		`1:9: variable $a is never used by operation "Q"`,
+		`1:32: variable $b is not defined by operation "Q"`,
+		`1:38: unknown fragment "F"`,
+		`2:1: fragment "G" is never used`,
+	}
+	if got := errorStrings(Validate(nil, op)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate\n got  %q\n want %q", got, want)
+	}
+}
@@ -0,0 +1,253 @@
+package http
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AuthType identifies how a request is authenticated
+type AuthType string
+
+// Supported authentication types
+const (
+	AuthNone   AuthType = ""
+	AuthBasic  AuthType = "basic"
+	AuthBearer AuthType = "bearer"
+	AuthAPIKey AuthType = "apikey"
+	AuthDigest AuthType = "digest"
+	AuthOAuth2 AuthType = "oauth2"
+)
+
+// API key locations
+const (
+	APIKeyInHeader = "header"
+	APIKeyInQuery  = "query"
+)
+
+// Auth holds the credentials used to authenticate a request
+type Auth struct {
+	Type     AuthType `json:"type,omitempty"`
+	Username string   `json:"username,omitempty"` // basic, digest, oauth2 password grant
+	Password string   `json:"password,omitempty"` // basic, digest, oauth2 password grant
+	Token    string   `json:"token,omitempty"`    // bearer
+	Key      string   `json:"key,omitempty"`      // api key name
+	Value    string   `json:"value,omitempty"`    // api key value
+	In       string   `json:"in,omitempty"`       // api key location: header or query
+
+	// OAuth 2.0
+	GrantType    string `json:"grantType,omitempty"`
+	TokenURL     string `json:"tokenUrl,omitempty"`
+	AuthURL      string `json:"authUrl,omitempty"`     // authorization code grant
+	RedirectURL  string `json:"redirectUrl,omitempty"` // authorization code grant, loopback only
+	ClientID     string `json:"clientId,omitempty"`
+	ClientSecret string `json:"clientSecret,omitempty"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// apply adds the credentials to an outgoing request. Digest and OAuth 2.0
+// credentials are applied by the client since they need extra round trips.
+func (a Auth) apply(req *http.Request) {
+	switch a.Type {
+	case AuthBasic:
+		req.SetBasicAuth(a.Username, a.Password)
+	case AuthBearer:
+		req.Header.Set("Authorization", "Bearer "+a.Token)
+	case AuthAPIKey:
+		if a.Key == "" {
+			return
+		}
+		if a.In == APIKeyInQuery {
+			param := url.QueryEscape(a.Key) + "=" + url.QueryEscape(a.Value)
+			if req.URL.RawQuery != "" {
+				req.URL.RawQuery += "&" + param
+			} else {
+				req.URL.RawQuery = param
+			}
+		} else {
+			req.Header.Set(a.Key, a.Value)
+		}
+	}
+}
+
+// Redacted returns the credentials with their secrets blanked: the password,
+// token, API key value and client secret. Secrets that only refer to
+// {{variables}} are kept, since they name where the secret lives rather than
+// holding it.
+func (a Auth) Redacted() Auth {
+	redact := func(s string) string {
+		if variablePattern.ReplaceAllString(s, "") == "" {
+			return s
+		}
+		return ""
+	}
+	a.Password = redact(a.Password)
+	a.Token = redact(a.Token)
+	a.Value = redact(a.Value)
+	a.ClientSecret = redact(a.ClientSecret)
+	return a
+}
+
+// digestChallenge holds the parameters of a WWW-Authenticate: Digest header
+type digestChallenge map[string]string
+
+// parseDigestChallenge finds the Digest challenge among the WWW-Authenticate headers
+func parseDigestChallenge(headers http.Header) (digestChallenge, bool) {
+	for _, header := range headers.Values("WWW-Authenticate") {
+		index := strings.Index(strings.ToLower(header), "digest ")
+		if index < 0 {
+			continue
+		}
+		return parseAuthParams(header[index+len("digest "):]), true
+	}
+	return nil, false
+}
+
+// parseAuthParams parses comma-separated key=value pairs, honoring quoted values
+func parseAuthParams(input string) map[string]string {
+	params := make(map[string]string)
+	for input != "" {
+		input = strings.TrimLeft(input, " ,")
+		eq := strings.IndexByte(input, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.ToLower(strings.TrimSpace(input[:eq]))
+		input = strings.TrimLeft(input[eq+1:], " ")
+
+		var value string
+		if strings.HasPrefix(input, `"`) {
+			var sb strings.Builder
+			i := 1
+			for ; i < len(input) && input[i] != '"'; i++ {
+				if input[i] == '\\' && i+1 < len(input) {
+					i++
+				}
+				sb.WriteByte(input[i])
+			}
+			value = sb.String()
+			input = input[min(i+1, len(input)):]
+		} else {
+			end := strings.IndexByte(input, ',')
+			if end < 0 {
+				end = len(input)
+			}
+			value = strings.TrimSpace(input[:end])
+			input = input[end:]
+		}
+		params[key] = value
+	}
+	return params
+}
+
+// digestAuthorization computes the Authorization header answering a Digest
+// challenge (RFC 7616). getBody reads the encoded body, which is hashed for
+// qop=auth-int; it is nil when the request has none.
+func digestAuthorization(challenge digestChallenge, auth Auth, method, uri string, getBody bodyFunc) (string, error) {
+	algorithm := challenge["algorithm"]
+	if algorithm == "" {
+		algorithm = "MD5"
+	}
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(strings.ToUpper(algorithm), "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", algorithm)
+	}
+	h := func(s string) string {
+		hasher := newHash()
+		hasher.Write([]byte(s))
+		return hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	realm, nonce := challenge["realm"], challenge["nonce"]
+	cnonce, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	const nc = "00000001"
+
+	// Prefer qop=auth, fall back to auth-int when it's the only option
+	qop := ""
+	if options := challenge["qop"]; options != "" {
+		for _, option := range strings.Split(options, ",") {
+			option = strings.TrimSpace(option)
+			if option == "auth" {
+				qop = "auth"
+				break
+			}
+			if option == "auth-int" {
+				qop = "auth-int"
+			}
+		}
+	}
+
+	ha1 := h(auth.Username + ":" + realm + ":" + auth.Password)
+	if strings.HasSuffix(strings.ToUpper(algorithm), "-SESS") {
+		ha1 = h(ha1 + ":" + nonce + ":" + cnonce)
+	}
+
+	ha2 := h(method + ":" + uri)
+	if qop == "auth-int" {
+		hasher := newHash()
+		if getBody != nil {
+			body, err := getBody()
+			if err != nil {
+				return "", err
+			}
+			_, err = io.Copy(hasher, body)
+			body.Close()
+			if err != nil {
+				return "", err
+			}
+		}
+		ha2 = h(method + ":" + uri + ":" + hex.EncodeToString(hasher.Sum(nil)))
+	}
+
+	var response string
+	if qop != "" {
+		response = h(strings.Join([]string{ha1, nonce, nc, cnonce, qop, ha2}, ":"))
+	} else {
+		response = h(ha1 + ":" + nonce + ":" + ha2)
+	}
+
+	parts := []string{
+		fmt.Sprintf(`username="%s"`, escapeQuotes(auth.Username)),
+		fmt.Sprintf(`realm="%s"`, escapeQuotes(realm)),
+		fmt.Sprintf(`nonce="%s"`, escapeQuotes(nonce)),
+		fmt.Sprintf(`uri="%s"`, escapeQuotes(uri)),
+		fmt.Sprintf(`algorithm=%s`, algorithm),
+		fmt.Sprintf(`response="%s"`, response),
+	}
+	if qop != "" {
+		parts = append(parts,
+			fmt.Sprintf(`qop=%s`, qop),
+			fmt.Sprintf(`nc=%s`, nc),
+			fmt.Sprintf(`cnonce="%s"`, cnonce),
+		)
+	}
+	if opaque, ok := challenge["opaque"]; ok {
+		parts = append(parts, fmt.Sprintf(`opaque="%s"`, escapeQuotes(opaque)))
+	}
+	return "Digest " + strings.Join(parts, ", "), nil
+}
+
+// randomHex returns n random bytes encoded as hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newDigestServer returns a server that challenges requests without valid
+// Digest credentials for user and password, and answers those with them
+func newDigestServer(t *testing.T, algorithm, qop, user, password string) *httptest.Server {
+	const realm, nonce, opaque = `test "realm"`, "dcd98b7102dd2f0e8b11d0f600bfb0c093", "5ccc069c403ebaf9f0171e9517f40e41"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading body: %v", err)
+		}
+
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, "Digest ") {
+			challenge := fmt.Sprintf(`Digest realm="%s", nonce="%s", opaque="%s", algorithm=%s`,
+				escapeQuotes(realm), nonce, opaque, algorithm)
+			if qop != "" {
+				challenge += fmt.Sprintf(`, qop="%s"`, qop)
+			}
+			w.Header().Set("WWW-Authenticate", challenge)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		params := parseAuthParams(strings.TrimPrefix(header, "Digest "))
+		var newHash func() hash.Hash = md5.New
+		if strings.HasPrefix(algorithm, "SHA-256") {
+			newHash = sha256.New
+		}
+		h := func(s string) string {
+			hasher := newHash()
+			hasher.Write([]byte(s))
+			return hex.EncodeToString(hasher.Sum(nil))
+		}
+
+		ha1 := h(user + ":" + realm + ":" + password)
+		if strings.HasSuffix(algorithm, "-sess") {
+			ha1 = h(ha1 + ":" + nonce + ":" + params["cnonce"])
+		}
+		ha2 := h(r.Method + ":" + r.URL.RequestURI())
+		if params["qop"] == "auth-int" {
+			ha2 = h(r.Method + ":" + r.URL.RequestURI() + ":" + h(string(body)))
+		}
+		want := h(ha1 + ":" + nonce + ":" + ha2)
+		if params["qop"] != "" {
+			want = h(strings.Join([]string{ha1, nonce, params["nc"], params["cnonce"], params["qop"], ha2}, ":"))
+		}
+
+		switch {
+		case params["username"] != user, params["realm"] != realm, params["uri"] != r.URL.RequestURI(),
+			params["opaque"] != opaque, params["algorithm"] != algorithm:
+			t.Errorf("Authorization: %s", header)
+			w.WriteHeader(http.StatusBadRequest)
+		case params["response"] != want:
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			fmt.Fprint(w, params["qop"])
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDigestAuth(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "upload.txt")
+	if err := os.WriteFile(file, []byte("file contents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		algorithm string
+		qop       string
+		user      string
+		req       Request
+		wantQop   string
+	}{
+		{"MD5 without qop", "MD5", "", "Mufasa", Request{Method: "GET"}, ""},
+		{"MD5", "MD5", "auth", "Mufasa", Request{Method: "GET"}, "auth"},
+		{"SHA-256", "SHA-256", "auth", "Mufasa", Request{Method: "GET"}, "auth"},
+		{"MD5-sess", "MD5-sess", "auth", "Mufasa", Request{Method: "GET"}, "auth"},
+		{"SHA-256-sess", "SHA-256-sess", "auth", "Mufasa", Request{Method: "GET"}, "auth"},
+		{"auth preferred over auth-int", "MD5", "auth-int, auth", "Mufasa", Request{Method: "POST", Body: "raw"}, "auth"},
+		{"quotes in the username", "MD5", "auth", `Mu"fa\sa`, Request{Method: "GET"}, "auth"},
+		{
+			"auth-int raw body", "SHA-256", "auth-int", "Mufasa",
+			Request{Method: "POST", Body: `{"a":1}`}, "auth-int",
+		},
+		{
+			"auth-int no body", "MD5", "auth-int", "Mufasa",
+			Request{Method: "GET"}, "auth-int",
+		},
+		{
+			"auth-int form body", "MD5", "auth-int", "Mufasa",
+			Request{Method: "POST", BodyType: BodyURLEncoded, Form: []KeyValue{{Key: "a b", Value: "c&d"}}}, "auth-int",
+		},
+		{
+			"auth-int multipart body", "MD5-sess", "auth-int", "Mufasa",
+			Request{Method: "POST", BodyType: BodyMultipart, Form: []KeyValue{{Key: "name", Value: "x"}, {Key: "file", Value: file, File: true}}}, "auth-int",
+		},
+		{
+			"auth-int binary body", "SHA-256", "auth-int", "Mufasa",
+			Request{Method: "PUT", BodyType: BodyBinary, BodyFile: file}, "auth-int",
+		},
+		{
+			"auth-int GraphQL body", "MD5", "auth-int", "Mufasa",
+			Request{Method: "POST", BodyType: BodyGraphQL, GraphQL: GraphQL{Query: "{ me { id } }", Variables: `{"a": 1}`}}, "auth-int",
+		},
+	}
+
+	c, err := NewClient(DefaultTransportConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const password = "Circle of Life"
+			server := newDigestServer(t, tt.algorithm, tt.qop, tt.user, password)
+
+			req := tt.req
+			req.URL = server.URL + "/dir/index.html?a=1&b=%22"
+			req.Auth = Auth{Type: AuthDigest, Username: tt.user, Password: password}
+			resp := c.Execute(context.Background(), &req)
+			if resp.Error != nil {
+				t.Fatal(resp.Error)
+			}
+			if resp.StatusCode != http.StatusOK || string(resp.Body) != tt.wantQop {
+				t.Errorf("got %s with qop %q, want 200 OK with qop %q", resp.Status, resp.Body, tt.wantQop)
+			}
+		})
+	}
+
+	t.Run("wrong password", func(t *testing.T) {
+		server := newDigestServer(t, "MD5", "auth", "Mufasa", "Circle of Life")
+		req := &Request{Method: "GET", URL: server.URL, Auth: Auth{Type: AuthDigest, Username: "Mufasa", Password: "wrong"}}
+		if resp := c.Execute(context.Background(), req); resp.StatusCode != http.StatusForbidden {
+			t.Errorf("got %s, want 403 Forbidden", resp.Status)
+		}
+	})
+}
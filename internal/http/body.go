@@ -0,0 +1,293 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/utils"
+)
+
+// BodyType identifies how the body of a request is encoded
+type BodyType string
+
+// Supported body types. JSON is the zero value so that requests saved before
+// body types existed are still sent as they were.
+const (
+	BodyJSON       BodyType = ""
+	BodyNone       BodyType = "none"
+	BodyText       BodyType = "text"
+	BodyXML        BodyType = "xml"
+	BodyGraphQL    BodyType = "graphql"
+	BodyURLEncoded BodyType = "urlencoded"
+	BodyMultipart  BodyType = "multipart"
+	BodyBinary     BodyType = "binary"
+)
+
+// GraphQL is the operation sent by a GraphQL body
+type GraphQL struct {
+	Query         string `json:"query,omitempty"`
+	Variables     string `json:"variables,omitempty"` // a JSON object, as typed
+	OperationName string `json:"operationName,omitempty"`
+}
+
+// Payload encodes the operation as the JSON body of a GraphQL request. Blank
+// variables and operation name are left out.
+func (g GraphQL) Payload() ([]byte, error) {
+	payload := struct {
+		Query         string          `json:"query"`
+		Variables     json.RawMessage `json:"variables,omitempty"`
+		OperationName string          `json:"operationName,omitempty"`
+	}{Query: g.Query, OperationName: strings.TrimSpace(g.OperationName)}
+
+	if variables := strings.TrimSpace(g.Variables); variables != "" {
+		var object map[string]any
+		if err := json.Unmarshal([]byte(variables), &object); err != nil || object == nil {
+			return nil, errors.New("GraphQL variables must be a JSON object")
+		}
+		payload.Variables = json.RawMessage(variables)
+	}
+	return json.Marshal(payload)
+}
+
+// HasBody reports whether the request sends a body
+func (r *Request) HasBody() bool {
+	switch r.BodyType {
+	case BodyNone:
+		return false
+	case BodyURLEncoded, BodyMultipart:
+		return len(enabledFields(r.Form)) > 0
+	case BodyBinary:
+		return r.BodyFile != ""
+	case BodyGraphQL:
+		return strings.TrimSpace(r.GraphQL.Query) != ""
+	default:
+		return r.Body != ""
+	}
+}
+
+// ContentType returns the Content-Type the body is sent with when the
+// request doesn't set one. Multipart bodies get theirs, with the boundary,
+// when they are encoded.
+func (r *Request) ContentType() string {
+	switch r.BodyType {
+	case BodyText:
+		return "text/plain; charset=utf-8"
+	case BodyXML:
+		return "application/xml"
+	case BodyURLEncoded:
+		return "application/x-www-form-urlencoded"
+	case BodyMultipart:
+		return "multipart/form-data"
+	case BodyBinary:
+		if contentType := mime.TypeByExtension(filepath.Ext(r.BodyFile)); contentType != "" {
+			return contentType
+		}
+		return "application/octet-stream"
+	default:
+		return "application/json"
+	}
+}
+
+// bodyFunc returns a reader of the body from its start. It's called again
+// when the body has to be sent anew, as on a 307 or 308 redirect.
+type bodyFunc func() (io.ReadCloser, error)
+
+// bytesBody returns a bodyFunc reading data
+func bytesBody(data []byte) bodyFunc {
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+// newBody returns the encoded body, its size and its Content-Type. Files are
+// read when the body is, so large uploads aren't held in memory.
+func (r *Request) newBody() (bodyFunc, int64, string, error) {
+	if !r.HasBody() {
+		return nil, 0, "", nil
+	}
+
+	switch r.BodyType {
+	case BodyURLEncoded:
+		body := EncodeForm(r.Form)
+		return bytesBody([]byte(body)), int64(len(body)), r.ContentType(), nil
+	case BodyMultipart:
+		return multipartBody(r.Form)
+	case BodyBinary:
+		path := utils.ExpandPath(r.BodyFile)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if info.IsDir() {
+			return nil, 0, "", fmt.Errorf("%s is a directory", path)
+		}
+		open := func() (io.ReadCloser, error) {
+			return os.Open(path)
+		}
+		return open, info.Size(), r.ContentType(), nil
+	case BodyGraphQL:
+		body, err := r.GraphQL.Payload()
+		if err != nil {
+			return nil, 0, "", err
+		}
+		return bytesBody(body), int64(len(body)), r.ContentType(), nil
+	default:
+		return bytesBody([]byte(r.Body)), int64(len(r.Body)), r.ContentType(), nil
+	}
+}
+
+// EncodeForm encodes the enabled fields as an
+// application/x-www-form-urlencoded body, in order
+func EncodeForm(fields []KeyValue) string {
+	var pairs []string
+	for _, f := range enabledFields(fields) {
+		pairs = append(pairs, url.QueryEscape(f.Key)+"="+url.QueryEscape(f.Value))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// multipartBody encodes the enabled fields as a multipart/form-data body.
+// File fields are streamed from disk as the body is read.
+func multipartBody(fields []KeyValue) (bodyFunc, int64, string, error) {
+	var chunks [][]byte
+	var files []string // files[i] is sent after chunks[i]
+	var size int64
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	// The writer's output is cut after each part header so the file can be
+	// read in between
+	flush := func() {
+		data := bytes.Clone(buf.Bytes())
+		chunks = append(chunks, data)
+		size += int64(len(data))
+		buf.Reset()
+	}
+
+	for _, f := range enabledFields(fields) {
+		if !f.File {
+			if err := writer.WriteField(f.Key, f.Value); err != nil {
+				return nil, 0, "", err
+			}
+			continue
+		}
+
+		path := utils.ExpandPath(f.Value)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, 0, "", err
+		}
+		if info.IsDir() {
+			return nil, 0, "", fmt.Errorf("%s is a directory", path)
+		}
+		contentType := mime.TypeByExtension(filepath.Ext(path))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.Key), escapeQuotes(filepath.Base(path))))
+		header.Set("Content-Type", contentType)
+		if _, err := writer.CreatePart(header); err != nil {
+			return nil, 0, "", err
+		}
+		flush()
+		files = append(files, path)
+		size += info.Size()
+	}
+	if err := writer.Close(); err != nil {
+		return nil, 0, "", err
+	}
+	flush()
+
+	// Each reading has the same boundary, which the Content-Type gives
+	open := func() (io.ReadCloser, error) {
+		body := &multipartReader{}
+		var parts []io.Reader
+		for i, chunk := range chunks {
+			parts = append(parts, bytes.NewReader(chunk))
+			if i < len(files) {
+				file := &lazyFile{path: files[i]}
+				body.files = append(body.files, file)
+				parts = append(parts, file)
+			}
+		}
+		body.Reader = io.MultiReader(parts...)
+		return body, nil
+	}
+	return open, size, writer.FormDataContentType(), nil
+}
+
+// multipartReader reads a multipart body. Closing it closes the file being
+// read, when the body isn't read to its end.
+type multipartReader struct {
+	io.Reader
+	files []*lazyFile
+}
+
+func (r *multipartReader) Close() error {
+	var errs []error
+	for _, file := range r.files {
+		errs = append(errs, file.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// lazyFile opens a file when it's first read and closes it at its end, so a
+// body with many attachments doesn't hold them all open
+type lazyFile struct {
+	path string
+	f    *os.File
+	done bool
+}
+
+func (lf *lazyFile) Read(p []byte) (int, error) {
+	if lf.done {
+		return 0, io.EOF
+	}
+	if lf.f == nil {
+		f, err := os.Open(lf.path)
+		if err != nil {
+			return 0, err
+		}
+		lf.f = f
+	}
+	n, err := lf.f.Read(p)
+	if err == io.EOF {
+		lf.Close()
+	}
+	return n, err
+}
+
+// Close closes the file if it's open; it isn't read any further
+func (lf *lazyFile) Close() error {
+	lf.done = true
+	if lf.f == nil {
+		return nil
+	}
+	err := lf.f.Close()
+	lf.f = nil
+	return err
+}
+
+// enabledFields returns the form fields that are sent
+func enabledFields(fields []KeyValue) []KeyValue {
+	var enabled []KeyValue
+	for _, f := range fields {
+		if !f.Disabled && f.Key != "" {
+			enabled = append(enabled, f)
+		}
+	}
+	return enabled
+}
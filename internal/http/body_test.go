@@ -0,0 +1,218 @@
+package http
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeForm(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []KeyValue
+		want   string
+	}{
+		{"empty", nil, ""},
+		{"in order", []KeyValue{{Key: "b", Value: "2"}, {Key: "a", Value: "1"}, {Key: "b", Value: "3"}}, "b=2&a=1&b=3"},
+		{"escaped", []KeyValue{{Key: "a b", Value: "1&2=3"}, {Key: "é", Value: "+/?"}}, "a+b=1%262%3D3&%C3%A9=%2B%2F%3F"},
+		{"empty value", []KeyValue{{Key: "a"}}, "a="},
+		{"disabled and nameless fields left out", []KeyValue{{Key: "a", Value: "1", Disabled: true}, {Value: "2"}, {Key: "c", Value: "3"}}, "c=3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EncodeForm(tt.fields); got != tt.want {
+				t.Errorf("EncodeForm(%+v) = %q, want %q", tt.fields, got, tt.want)
+			}
+		})
+	}
+}
+
+// part is a part of a multipart body as read back
+type part struct {
+	name, filename, contentType, data string
+}
+
+// readMultipart reads a multipart body with the boundary of its Content-Type
+func readMultipart(t *testing.T, body io.Reader, contentType string) []part {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		t.Fatalf("Content-Type = %q", contentType)
+	}
+
+	var parts []part
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts = append(parts, part{p.FormName(), p.FileName(), p.Header.Get("Content-Type"), string(data)})
+	}
+}
+
+func TestMultipartBody(t *testing.T) {
+	dir := t.TempDir()
+	notes := filepath.Join(dir, `my "notes".txt`)
+	if err := os.WriteFile(notes, []byte("line 1\nline 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	blob := filepath.Join(dir, "blob")
+	if err := os.WriteFile(blob, []byte{0, 1, 2, 255}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := []KeyValue{
+		{Key: "name", Value: "ada"},
+		{Key: "notes", Value: notes, File: true},
+		{Key: "skipped", Value: "x", Disabled: true},
+		{Key: `a "quoted" name`, Value: "é"},
+		{Key: "blob", Value: blob, File: true},
+	}
+	getBody, size, contentType, err := multipartBody(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := getBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(data)) != size {
+		t.Errorf("size = %d, but the body is %d bytes", size, len(data))
+	}
+
+	want := []part{
+		{"name", "", "", "ada"},
+		{"notes", `my "notes".txt`, "text/plain; charset=utf-8", "line 1\nline 2\n"},
+		{`a "quoted" name`, "", "", "é"},
+		{"blob", "blob", "application/octet-stream", "\x00\x01\x02\xff"},
+	}
+	if got := readMultipart(t, strings.NewReader(string(data)), contentType); !reflect.DeepEqual(got, want) {
+		t.Errorf("parts\n got  %q\n want %q", got, want)
+	}
+
+	// The body can be read again, with the same boundary
+	again, err := getBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer again.Close()
+	if dataAgain, _ := io.ReadAll(again); string(dataAgain) != string(data) {
+		t.Error("reading the body again gave another body")
+	}
+}
+
+func TestMultipartBodyErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{filepath.Join(dir, "missing"), dir} {
+		if _, _, _, err := multipartBody([]KeyValue{{Key: "f", Value: path, File: true}}); err == nil {
+			t.Errorf("multipartBody with file %s succeeded", path)
+		}
+	}
+}
+
+func TestMultipartBodyClose(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(file, make([]byte, 1<<16), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	getBody, _, _, err := multipartBody([]KeyValue{{Key: "f", Value: file, File: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := getBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stop partway through the file, as a failed upload does
+	if _, err := io.ReadFull(body, make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	lf := body.(*multipartReader).files[0]
+	if lf.f == nil {
+		t.Fatal("the file isn't open partway through it")
+	}
+	if err := body.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if lf.f != nil {
+		t.Error("closing the body left the file open")
+	}
+
+	// The rest of the file isn't read after all
+	io.ReadAll(body)
+	if lf.f != nil {
+		t.Error("reading the body after Close opened the file again")
+	}
+}
+
+// TestRedirectResendsBody checks that file and multipart bodies are sent again
+// on redirects that keep the method
+func TestRedirectResendsBody(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "data.csv")
+	if err := os.WriteFile(file, []byte("a,b\n1,2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			io.Copy(io.Discard, r.Body)
+			w.Header().Set("Location", "/upload")
+			w.WriteHeader(http.StatusTemporaryRedirect)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		if r.Method == "POST" && strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+			for _, p := range readMultipart(t, strings.NewReader(string(data)), r.Header.Get("Content-Type")) {
+				received = append(received, p.name+"="+p.data)
+			}
+			return
+		}
+		received = append(received, r.Method+" "+string(data))
+	}))
+	defer server.Close()
+
+	c, err := NewClient(DefaultTransportConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := []*Request{
+		{Method: "PUT", URL: server.URL + "/moved", BodyType: BodyBinary, BodyFile: file},
+		{Method: "POST", URL: server.URL + "/moved", BodyType: BodyMultipart, Form: []KeyValue{{Key: "name", Value: "ada"}, {Key: "data", Value: file, File: true}}},
+	}
+	for _, req := range requests {
+		if resp := c.Execute(context.Background(), req); resp.Error != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s %s: status %d, error %v", req.Method, req.BodyType, resp.StatusCode, resp.Error)
+		}
+	}
+
+	want := []string{"PUT a,b\n1,2\n", "name=ada", "data=a,b\n1,2\n"}
+	if !reflect.DeepEqual(received, want) {
+		t.Errorf("received %q, want %q", received, want)
+	}
+}
@@ -1,45 +1,104 @@
 package http
 
 import (
-	"io"
+	"context"
 	"net/http"
+	"net/http/httptrace"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Client wraps the HTTP client with custom configuration
 type Client struct {
 	httpClient *http.Client
-	timeout    time.Duration
+	config     TransportConfig
+	download   DownloadConfig
+
+	// Clients for requests that override the default settings, keyed by config
+	mu        sync.Mutex
+	overrides map[string]*http.Client
+
+	oauth2 *oauth2Manager
 }
 
-// NewClient creates a new HTTP client with default settings
-func NewClient() *Client {
+// NewClient creates a new HTTP client from the given network settings
+func NewClient(cfg TransportConfig) (*Client, error) {
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Client{
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
-		timeout: 30 * time.Second,
+		httpClient: httpClient,
+		config:     cfg,
+		overrides:  make(map[string]*http.Client),
+		oauth2:     newOAuth2Manager(),
+	}, nil
+}
+
+// newHTTPClient builds a standard library client for the given settings
+func newHTTPClient(cfg TransportConfig) (*http.Client, error) {
+	transport, err := NewTransport(cfg)
+	if err != nil {
+		return nil, err
 	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+	}, nil
 }
 
 // SetTimeout sets the client timeout
 func (c *Client) SetTimeout(d time.Duration) {
-	c.timeout = d
+	c.config.Timeout = d
 	c.httpClient.Timeout = d
 }
 
-// Execute performs the HTTP request and returns the response
-func (c *Client) Execute(req *Request) *Response {
-	startTime := time.Now()
+// SetDownloadConfig sets when response bodies are streamed to disk
+func (c *Client) SetDownloadConfig(cfg DownloadConfig) {
+	c.download = cfg
+}
 
-	// Create HTTP request
-	var bodyReader io.Reader
-	if req.Body != "" {
-		bodyReader = strings.NewReader(req.Body)
+// SetAuthorizationHandler sets the callback invoked with the URL the user must
+// open in a browser during the OAuth 2.0 authorization code flow
+func (c *Client) SetAuthorizationHandler(fn func(authURL string)) {
+	c.oauth2.onAuthorize = fn
+}
+
+// clientFor returns the client to use for a request, honoring its settings overrides
+func (c *Client) clientFor(req *Request) (*http.Client, error) {
+	if req.Settings.IsZero() {
+		return c.httpClient, nil
+	}
+
+	cfg := c.config.WithSettings(req.Settings)
+	key := cfg.key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if httpClient, ok := c.overrides[key]; ok {
+		return httpClient, nil
 	}
+	httpClient, err := newHTTPClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.overrides[key] = httpClient
+	return httpClient, nil
+}
+
+// Execute performs the HTTP request and returns the response. Cancelling ctx
+// aborts the request, including any OAuth 2.0 authorization it is waiting on.
+// Bodies over the download threshold are streamed to a file rather than
+// kept in memory.
+func (c *Client) Execute(ctx context.Context, req *Request) *Response {
+	startTime := time.Now()
+	trace := &timingTrace{}
+	ctx = httptrace.WithClientTrace(ctx, trace.clientTrace())
 
-	httpReq, err := http.NewRequest(req.Method, req.URL, bodyReader)
+	httpClient, err := c.clientFor(req)
 	if err != nil {
 		return &Response{
 			Error:    err,
@@ -47,46 +106,161 @@ func (c *Client) Execute(req *Request) *Response {
 		}
 	}
 
-	// Set headers
-	for key, value := range req.Headers {
-		httpReq.Header.Set(key, value)
+	// Create HTTP request
+	httpReq, err := newHTTPRequest(ctx, req)
+	if err == nil && req.Auth.Type == AuthOAuth2 {
+		err = c.authorizeOAuth2(ctx, httpClient, req, httpReq, false)
 	}
-
-	// Set default Content-Type for requests with body
-	if req.Body != "" && httpReq.Header.Get("Content-Type") == "" {
-		httpReq.Header.Set("Content-Type", "application/json")
+	if err != nil {
+		return &Response{
+			Error:    err,
+			Duration: time.Since(startTime),
+			Timing:   trace.result(),
+		}
 	}
 
 	// Execute request
-	resp, err := c.httpClient.Do(httpReq)
+	resp, err := httpClient.Do(httpReq)
 	if err != nil {
 		return &Response{
 			Error:    err,
 			Duration: time.Since(startTime),
+			Timing:   trace.result(),
+		}
+	}
+
+	// A rejected OAuth 2.0 token may have been revoked early; refresh it and retry once
+	if req.Auth.Type == AuthOAuth2 && resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+
+		httpReq, err = newHTTPRequest(ctx, req)
+		if err == nil {
+			err = c.authorizeOAuth2(ctx, httpClient, req, httpReq, true)
+		}
+		if err == nil {
+			resp, err = httpClient.Do(httpReq)
+		}
+		if err != nil {
+			return &Response{
+				Error:    err,
+				Duration: time.Since(startTime),
+				Timing:   trace.result(),
+			}
+		}
+	}
+
+	// Answer a Digest challenge by resending the request with credentials
+	if req.Auth.Type == AuthDigest && resp.StatusCode == http.StatusUnauthorized {
+		if challenge, ok := parseDigestChallenge(resp.Header); ok {
+			resp.Body.Close()
+
+			resp, err = c.retryWithDigest(ctx, httpClient, req, challenge)
+			if err != nil {
+				return &Response{
+					Error:    err,
+					Duration: time.Since(startTime),
+					Timing:   trace.result(),
+				}
+			}
 		}
 	}
 	defer resp.Body.Close()
 
 	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	body, bodyFile, size, err := c.readBody(ctx, req, resp)
 	if err != nil {
 		return &Response{
 			StatusCode: resp.StatusCode,
 			Status:     resp.Status,
+			Proto:      resp.Proto,
 			Headers:    resp.Header,
 			Error:      err,
 			Duration:   time.Since(startTime),
+			Timing:     trace.result(),
 		}
 	}
 
 	return &Response{
 		StatusCode: resp.StatusCode,
 		Status:     resp.Status,
+		Proto:      resp.Proto,
 		Headers:    resp.Header,
 		Body:       body,
+		BodyFile:   bodyFile,
 		Duration:   time.Since(startTime),
-		Size:       int64(len(body)),
+		Timing:     trace.result(),
+		Size:       size,
+	}
+}
+
+// retryWithDigest resends the request with an Authorization header answering the challenge
+func (c *Client) retryWithDigest(ctx context.Context, httpClient *http.Client, req *Request, challenge digestChallenge) (*http.Response, error) {
+	httpReq, err := newHTTPRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	authorization, err := digestAuthorization(challenge, req.Auth, httpReq.Method, httpReq.URL.RequestURI(), httpReq.GetBody)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Authorization", authorization)
+
+	return httpClient.Do(httpReq)
+}
+
+// authorizeOAuth2 sets the Authorization header from a cached or newly obtained token
+func (c *Client) authorizeOAuth2(ctx context.Context, httpClient *http.Client, req *Request, httpReq *http.Request, forceRefresh bool) error {
+	token, err := c.oauth2.Token(ctx, httpClient, req, forceRefresh)
+	if err != nil {
+		return err
 	}
+	httpReq.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
+
+// newHTTPRequest builds a standard library request with headers and auth applied
+func newHTTPRequest(ctx context.Context, req *Request) (*http.Request, error) {
+	getBody, size, contentType, err := req.newBody()
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if getBody != nil && size > 0 {
+		// GetBody lets redirects that keep the method send the body again
+		if httpReq.Body, err = getBody(); err != nil {
+			return nil, err
+		}
+		httpReq.GetBody = getBody
+		httpReq.ContentLength = size
+	}
+
+	// Set headers in order, repeated keys included; Go sends Host from the
+	// request rather than the header map
+	for _, h := range req.Headers {
+		if h.Disabled || h.Key == "" {
+			continue
+		}
+		if strings.EqualFold(h.Key, "Host") {
+			httpReq.Host = h.Value
+			continue
+		}
+		httpReq.Header.Add(h.Key, h.Value)
+	}
+
+	// Set the Content-Type of the body type unless one is set. Multipart
+	// bodies always need the boundary they were encoded with.
+	if current := httpReq.Header.Get("Content-Type"); contentType != "" &&
+		(current == "" || req.BodyType == BodyMultipart && !strings.Contains(current, "boundary=")) {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+
+	req.Auth.apply(httpReq)
+	return httpReq, nil
 }
 
 // SupportedMethods returns the list of supported HTTP methods
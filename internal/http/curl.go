@@ -0,0 +1,776 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// curlShortFlags maps the short curl options understood by ParseCurl to their long names
+var curlShortFlags = map[byte]string{
+	'X': "request",
+	'H': "header",
+	'd': "data",
+	'u': "user",
+	'F': "form",
+	'A': "user-agent",
+	'b': "cookie",
+	'e': "referer",
+	'x': "proxy",
+	'm': "max-time",
+	'k': "insecure",
+	'I': "head",
+	'G': "get",
+
+	// Ignored, but they take a value that must be skipped
+	'o': "output",
+	'w': "write-out",
+	'c': "cookie-jar",
+	'E': "cert",
+	'T': "upload-file",
+	'r': "range",
+	'K': "config",
+	'U': "proxy-user",
+	'D': "dump-header",
+	'C': "continue-at",
+	'Y': "speed-limit",
+	'y': "speed-time",
+	'z': "time-cond",
+	'P': "ftp-port",
+	'Q': "quote",
+	't': "telnet-option",
+
+	// Ignored
+	's': "silent",
+	'S': "show-error",
+	'L': "location",
+	'v': "verbose",
+	'i': "include",
+	'f': "fail",
+	'g': "globoff",
+	'N': "no-buffer",
+	'n': "netrc",
+	'q': "disable",
+	'j': "junk-session-cookies",
+	'J': "remote-header-name",
+	'O': "remote-name",
+	'R': "remote-time",
+	'Z': "parallel",
+	'#': "progress-bar",
+	'p': "proxytunnel",
+	'0': "http1.0",
+	'1': "tlsv1",
+	'2': "sslv2",
+	'3': "sslv3",
+	'4': "ipv4",
+	'6': "ipv6",
+}
+
+// curlValueFlags lists the long curl options that take a value
+var curlValueFlags = map[string]bool{
+	"request": true, "header": true, "url": true, "user": true, "user-agent": true,
+	"data": true, "data-raw": true, "data-binary": true, "data-ascii": true, "data-urlencode": true,
+	"json": true, "form": true, "form-string": true, "cookie": true, "referer": true,
+	"oauth2-bearer": true, "proxy": true, "noproxy": true, "max-time": true,
+
+	// Ignored
+	"output": true, "output-dir": true, "write-out": true, "cookie-jar": true, "cert": true,
+	"key": true, "cacert": true, "capath": true, "cert-type": true, "key-type": true,
+	"pass": true, "crlfile": true, "pinnedpubkey": true, "engine": true, "ciphers": true,
+	"tls13-ciphers": true, "curves": true, "tlsuser": true, "tlspassword": true,
+	"tlsauthtype": true, "connect-timeout": true, "retry": true, "retry-delay": true,
+	"retry-max-time": true, "resolve": true, "connect-to": true, "interface": true,
+	"local-port": true, "limit-rate": true, "rate": true, "range": true,
+	"dump-header": true, "upload-file": true, "config": true, "variable": true,
+	"url-query": true, "request-target": true, "max-redirs": true, "max-filesize": true,
+	"continue-at": true, "speed-limit": true, "speed-time": true, "time-cond": true,
+	"expect100-timeout": true, "keepalive-time": true, "happy-eyeballs-timeout-ms": true,
+	"unix-socket": true, "abstract-unix-socket": true, "aws-sigv4": true,
+	"proxy-user": true, "proxy-header": true, "proxy-cacert": true, "proxy-capath": true,
+	"proxy-cert": true, "proxy-cert-type": true, "proxy-key": true, "proxy-key-type": true,
+	"proxy-pass": true, "proxy-ciphers": true, "proxy-tls13-ciphers": true,
+	"proxy-crlfile": true, "proxy-pinnedpubkey": true, "proxy-service-name": true,
+	"preproxy": true, "socks4": true, "socks4a": true, "socks5": true,
+	"socks5-hostname": true, "socks5-gssapi-service": true, "service-name": true,
+	"dns-servers": true, "dns-interface": true, "dns-ipv4-addr": true,
+	"dns-ipv6-addr": true, "doh-url": true, "login-options": true, "sasl-authzid": true,
+	"delegation": true, "krb": true, "netrc-file": true, "alt-svc": true, "hsts": true,
+	"etag-save": true, "etag-compare": true, "proto": true, "proto-redir": true,
+	"proto-default": true, "trace": true, "trace-ascii": true, "stderr": true,
+	"ftp-port": true, "ftp-account": true, "ftp-method": true, "quote": true,
+	"telnet-option": true, "mail-from": true, "mail-rcpt": true, "mail-auth": true,
+	"hostpubmd5": true, "hostpubsha256": true, "pubkey": true, "parallel-max": true,
+	"create-file-mode": true, "ip-tos": true, "vlan-priority": true,
+}
+
+// curlSwitches lists the long curl options that take no value. Options in
+// neither list are rejected, since whether the next word is their value or
+// the URL can't be told.
+var curlSwitches = map[string]bool{
+	"get": true, "head": true, "insecure": true, "digest": true, "basic": true,
+
+	// Ignored
+	"silent": true, "show-error": true, "location": true, "location-trusted": true,
+	"verbose": true, "include": true, "fail": true, "fail-with-body": true,
+	"fail-early": true, "globoff": true, "compressed": true, "compressed-ssh": true,
+	"http0.9": true, "http1.0": true, "http1.1": true, "http2": true,
+	"http2-prior-knowledge": true, "http3": true, "http3-only": true, "ipv4": true,
+	"ipv6": true, "tlsv1": true, "tlsv1.0": true, "tlsv1.1": true, "tlsv1.2": true,
+	"tlsv1.3": true, "sslv2": true, "sslv3": true, "ssl": true, "ssl-reqd": true,
+	"ssl-no-revoke": true, "ssl-revoke-best-effort": true, "ssl-allow-beast": true,
+	"ca-native": true, "proxy-insecure": true, "proxytunnel": true, "no-buffer": true,
+	"no-keepalive": true, "no-sessionid": true, "no-alpn": true, "no-npn": true,
+	"no-progress-meter": true, "progress-bar": true, "remote-name": true,
+	"remote-name-all": true, "remote-header-name": true, "remote-time": true,
+	"create-dirs": true, "no-clobber": true, "remove-on-error": true, "xattr": true,
+	"path-as-is": true, "raw": true, "tr-encoding": true, "ignore-content-length": true,
+	"trace-time": true, "netrc": true, "netrc-optional": true, "anyauth": true,
+	"ntlm": true, "ntlm-wb": true, "negotiate": true, "proxy-anyauth": true,
+	"proxy-basic": true, "proxy-digest": true, "proxy-ntlm": true,
+	"proxy-negotiate": true, "junk-session-cookies": true, "disable": true,
+	"disable-eprt": true, "disable-epsv": true, "styled-output": true, "post301": true,
+	"post302": true, "post303": true, "retry-connrefused": true,
+	"retry-all-errors": true, "tcp-nodelay": true, "tcp-fastopen": true,
+	"parallel": true, "parallel-immediate": true, "false-start": true,
+	"suppress-connect-headers": true, "doh-insecure": true, "list-only": true,
+	"append": true, "crlf": true, "use-ascii": true, "skip-existing": true,
+}
+
+// curlCommand collects the options of a parsed curl command
+type curlCommand struct {
+	req      *Request
+	method   string
+	data     []string
+	form     []KeyValue
+	user     *string
+	digest   bool
+	get      bool
+	head     bool
+	urlFound bool
+}
+
+// LooksLikeCurl reports whether the text is a curl command rather than a URL
+func LooksLikeCurl(text string) bool {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "$ ")
+	return strings.HasPrefix(text, "curl ") || strings.HasPrefix(text, "curl\t")
+}
+
+// ParseCurl converts a curl command line into a request. Options that have
+// no equivalent, such as -s, -L or --compressed, are accepted and ignored;
+// options curl doesn't have, or that aren't known here, are an error.
+func ParseCurl(command string) (*Request, error) {
+	args, err := splitShellWords(strings.TrimPrefix(strings.TrimSpace(command), "$ "))
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 || args[0] != "curl" {
+		return nil, errors.New("not a curl command")
+	}
+
+	cmd := &curlCommand{req: NewRequest()}
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			// Everything after -- is a URL
+			for _, rest := range args[i+1:] {
+				cmd.setURL(rest)
+			}
+			i = len(args)
+
+		case strings.HasPrefix(arg, "--"):
+			// Like curl, long options take their value as the next argument;
+			// --name=value is an unknown option
+			name, value := arg[2:], ""
+			if !curlValueFlags[name] && !curlSwitches[name] {
+				// --no-name turns a switch off
+				if negated, ok := strings.CutPrefix(name, "no-"); !ok || !curlSwitches[negated] {
+					return nil, fmt.Errorf("unsupported curl option --%s", name)
+				}
+				continue
+			}
+			if curlValueFlags[name] {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("curl option --%s requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+			if err := cmd.apply(name, value); err != nil {
+				return nil, err
+			}
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// Short options may be combined (-sSL) or carry their value (-XPOST)
+			for j := 1; j < len(arg); j++ {
+				name, ok := curlShortFlags[arg[j]]
+				if !ok {
+					return nil, fmt.Errorf("unsupported curl option -%c", arg[j])
+				}
+				if !curlValueFlags[name] {
+					if err := cmd.apply(name, ""); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				value := arg[j+1:]
+				if value == "" {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("curl option -%c requires a value", arg[j])
+					}
+					i++
+					value = args[i]
+				}
+				if err := cmd.apply(name, value); err != nil {
+					return nil, err
+				}
+				break
+			}
+
+		default:
+			cmd.setURL(arg)
+		}
+	}
+
+	return cmd.build()
+}
+
+// setURL sets the request URL; curl accepts several, only the first is used
+func (c *curlCommand) setURL(rawURL string) {
+	if !c.urlFound {
+		c.req.URL = rawURL
+		c.urlFound = true
+	}
+}
+
+// apply records a single curl option
+func (c *curlCommand) apply(name, value string) error {
+	switch name {
+	case "request":
+		c.method = strings.ToUpper(value)
+	case "url":
+		c.setURL(value)
+	case "header":
+		key, val, ok := strings.Cut(value, ":")
+		if !ok {
+			// "Name;" sends an empty header
+			key, ok = strings.CutSuffix(value, ";")
+			if !ok {
+				return nil
+			}
+		} else if val = strings.TrimSpace(val); val == "" {
+			// "Name:" removes a default header
+			return nil
+		}
+		c.req.Headers.Add(strings.TrimSpace(key), val)
+	case "user-agent":
+		c.req.Headers.Set("User-Agent", value)
+	case "referer":
+		c.req.Headers.Set("Referer", value)
+	case "cookie":
+		// Without '=' the value names a cookie file, which isn't supported
+		if strings.Contains(value, "=") {
+			if existing := c.req.Headers.Get("Cookie"); existing != "" {
+				value = existing + "; " + value
+			}
+			c.req.Headers.Set("Cookie", value)
+		}
+	case "data", "data-ascii", "data-binary", "data-raw", "data-urlencode":
+		data, err := curlData(name, value)
+		if err != nil {
+			return err
+		}
+		c.data = append(c.data, data)
+	case "json":
+		// --json is -d with JSON content and accept headers
+		data, err := curlData(name, value)
+		if err != nil {
+			return err
+		}
+		c.data = append(c.data, data)
+		setDefaultHeader(&c.req.Headers, "Content-Type", "application/json")
+		setDefaultHeader(&c.req.Headers, "Accept", "application/json")
+	case "form", "form-string":
+		part, err := curlFormField(name, value)
+		if err != nil {
+			return err
+		}
+		c.form = append(c.form, part)
+	case "user":
+		c.user = &value
+	case "digest":
+		c.digest = true
+	case "basic":
+		c.digest = false
+	case "oauth2-bearer":
+		c.req.Auth = Auth{Type: AuthBearer, Token: value}
+	case "get":
+		c.get = true
+	case "head":
+		c.head = true
+	case "insecure":
+		verify := false
+		c.req.Settings.SSLVerify = &verify
+	case "proxy":
+		c.req.Settings.Proxy = value
+	case "noproxy":
+		if value == "*" {
+			c.req.Settings.Proxy = ProxyDirect
+		}
+	case "max-time":
+		seconds, err := strconv.ParseFloat(value, 64)
+		if err != nil || seconds < 0 {
+			return fmt.Errorf("invalid curl --max-time %q", value)
+		}
+		c.req.Settings.Timeout = int(math.Ceil(seconds))
+	}
+	return nil
+}
+
+// build assembles the request from the collected options
+func (c *curlCommand) build() (*Request, error) {
+	req := c.req
+	if req.URL == "" {
+		return nil, errors.New("curl command has no URL")
+	}
+
+	if c.user != nil {
+		username, password, _ := strings.Cut(*c.user, ":")
+		req.Auth = Auth{Type: AuthBasic, Username: username, Password: password}
+		if c.digest {
+			req.Auth.Type = AuthDigest
+		}
+	}
+
+	switch {
+	case len(c.form) > 0:
+		req.BodyType = BodyMultipart
+		req.Form = c.form
+	case len(c.data) > 0 && c.get:
+		// -G sends the data in the query string
+		separator := "?"
+		if strings.Contains(req.URL, "?") {
+			separator = "&"
+		}
+		req.URL += separator + strings.Join(c.data, "&")
+	case len(c.data) > 0:
+		curlDataBody(req, strings.Join(c.data, "&"))
+	}
+
+	switch {
+	case c.method != "":
+		req.Method = c.method
+	case c.head:
+		req.Method = "HEAD"
+	case req.HasBody():
+		req.Method = "POST"
+	default:
+		req.Method = "GET"
+	}
+	return req, nil
+}
+
+// curlDataBody sets the body sent with -d, picking the body type from the
+// Content-Type. Like curl, data without one is sent as a url-encoded form.
+func curlDataBody(req *Request, data string) {
+	contentType := strings.ToLower(req.Headers.Get("Content-Type"))
+	switch {
+	case strings.Contains(contentType, "json"):
+		req.Body = data
+	case strings.Contains(contentType, "xml"):
+		req.BodyType, req.Body = BodyXML, data
+	case contentType == "" || strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		if form, ok := parseForm(data); ok {
+			req.BodyType, req.Form = BodyURLEncoded, form
+			return
+		}
+		// Data that isn't name=value pairs is sent as it is
+		req.BodyType, req.Body = BodyText, data
+		setDefaultHeader(&req.Headers, "Content-Type", "application/x-www-form-urlencoded")
+	default:
+		req.BodyType, req.Body = BodyText, data
+	}
+}
+
+// parseForm splits a url-encoded body into its fields, reporting whether
+// every part is a validly encoded name=value pair
+func parseForm(data string) ([]KeyValue, bool) {
+	var fields []KeyValue
+	for _, pair := range strings.Split(data, "&") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, false
+		}
+		decodedKey, err := url.QueryUnescape(key)
+		if err != nil {
+			return nil, false
+		}
+		decodedValue, err := url.QueryUnescape(value)
+		if err != nil {
+			return nil, false
+		}
+		fields = append(fields, KeyValue{Key: decodedKey, Value: decodedValue})
+	}
+	return fields, true
+}
+
+// curlData reads the value of a --data option the way curl does
+func curlData(name, value string) (string, error) {
+	switch name {
+	case "data-raw":
+		return value, nil
+	case "data-urlencode":
+		return curlURLEncode(value)
+	}
+
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	content, err := os.ReadFile(value[1:])
+	if err != nil {
+		return "", fmt.Errorf("curl --%s: %w", name, err)
+	}
+	if name == "data-binary" || name == "json" {
+		return string(content), nil
+	}
+	// -d strips line breaks from files
+	return strings.NewReplacer("\r", "", "\n", "").Replace(string(content)), nil
+}
+
+// curlURLEncode implements the content forms of --data-urlencode
+func curlURLEncode(value string) (string, error) {
+	if name, file, ok := strings.Cut(value, "@"); ok && !strings.Contains(name, "=") {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", fmt.Errorf("curl --data-urlencode: %w", err)
+		}
+		if name == "" {
+			return url.QueryEscape(string(content)), nil
+		}
+		return name + "=" + url.QueryEscape(string(content)), nil
+	}
+
+	name, content, ok := strings.Cut(value, "=")
+	switch {
+	case !ok:
+		return url.QueryEscape(value), nil
+	case name == "":
+		return url.QueryEscape(content), nil
+	default:
+		return name + "=" + url.QueryEscape(content), nil
+	}
+}
+
+// curlFormField parses a -F name=value field. Files given with @path are
+// attached when the request is sent; the ;type= and ;filename= attributes
+// aren't kept.
+func curlFormField(option, value string) (KeyValue, error) {
+	name, content, ok := strings.Cut(value, "=")
+	if !ok {
+		return KeyValue{}, fmt.Errorf("curl --%s: expected name=value, got %q", option, value)
+	}
+	field := KeyValue{Key: name, Value: content}
+	if option == "form-string" {
+		return field, nil
+	}
+
+	// Split off attributes such as ;type=
+	field.Value, _, _ = strings.Cut(content, ";")
+
+	switch {
+	case strings.HasPrefix(field.Value, "@"):
+		// Upload a file, which is read from the same place whatever
+		// directory the request is later sent from
+		field.Value, field.File = field.Value[1:], true
+		if path, err := filepath.Abs(field.Value); err == nil {
+			field.Value = path
+		}
+	case strings.HasPrefix(field.Value, "<"):
+		// Send a file's content as a text field
+		data, err := os.ReadFile(field.Value[1:])
+		if err != nil {
+			return KeyValue{}, fmt.Errorf("curl --form: %w", err)
+		}
+		field.Value = string(data)
+	}
+	return field, nil
+}
+
+// curlBody returns the options sending the body
+func (r *Request) curlBody() []string {
+	var args []string
+	switch r.BodyType {
+	case BodyURLEncoded:
+		for _, f := range enabledFields(r.Form) {
+			args = append(args, "--data-urlencode", url.QueryEscape(f.Key)+"="+f.Value)
+		}
+	case BodyMultipart:
+		for _, f := range enabledFields(r.Form) {
+			switch {
+			case f.File:
+				args = append(args, "-F", f.Key+"=@"+f.Value)
+			case strings.HasPrefix(f.Value, "@") || strings.HasPrefix(f.Value, "<") || strings.Contains(f.Value, ";"):
+				// -F would read a file or take attributes from these
+				args = append(args, "--form-string", f.Key+"="+f.Value)
+			default:
+				args = append(args, "-F", f.Key+"="+f.Value)
+			}
+		}
+	case BodyBinary:
+		args = append(args, "--data-binary", "@"+r.BodyFile)
+	case BodyGraphQL:
+		body, err := r.GraphQL.Payload()
+		if err != nil {
+			// Leave out variables that aren't valid JSON rather than the body
+			body, _ = GraphQL{Query: r.GraphQL.Query, OperationName: r.GraphQL.OperationName}.Payload()
+		}
+		args = append(args, "--data-raw", string(body))
+	default:
+		args = append(args, "--data-raw", r.Body)
+	}
+	return args
+}
+
+// escapeQuotes escapes a value for a quoted MIME or auth parameter
+func escapeQuotes(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// setDefaultHeader sets a header unless it is already present
+func setDefaultHeader(headers *Headers, key, value string) {
+	if !headers.Has(key) {
+		headers.Add(key, value)
+	}
+}
+
+// errUnterminatedQuote is returned for a command with an unclosed quote
+var errUnterminatedQuote = errors.New("unterminated quote in curl command")
+
+// splitShellWords splits a command line into words following POSIX shell
+// quoting rules, plus bash's $'...' strings used by browsers' "Copy as cURL"
+func splitShellWords(input string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+		runes  = []rune(input)
+	)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+
+		case r == '\\':
+			if i+1 < len(runes) {
+				i++
+				// A backslash before a line break continues the line
+				if runes[i] == '\r' && i+1 < len(runes) && runes[i+1] == '\n' {
+					i++
+					continue
+				}
+				if runes[i] == '\n' {
+					continue
+				}
+				word.WriteRune(runes[i])
+				inWord = true
+			}
+
+		case r == '\'':
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, errUnterminatedQuote
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			inWord = true
+			i = end
+
+		case r == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			end, err := readANSIString(runes, i+2, &word)
+			if err != nil {
+				return nil, err
+			}
+			inWord = true
+			i = end
+
+		case r == '"':
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errUnterminatedQuote
+			}
+			inWord = true
+
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// indexRune returns the index of r in runes at or after start, or -1
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// readANSIString decodes a $'...' string starting after the opening quote
+// and returns the index of the closing quote
+func readANSIString(runes []rune, start int, word *strings.Builder) (int, error) {
+	for i := start; i < len(runes); i++ {
+		r := runes[i]
+		if r == '\'' {
+			return i, nil
+		}
+		if r != '\\' || i+1 >= len(runes) {
+			word.WriteRune(r)
+			continue
+		}
+
+		i++
+		switch runes[i] {
+		case 'n':
+			word.WriteByte('\n')
+		case 't':
+			word.WriteByte('\t')
+		case 'r':
+			word.WriteByte('\r')
+		case 'x', 'u', 'U':
+			digits := map[rune]int{'x': 2, 'u': 4, 'U': 8}[runes[i]]
+			end := i + 1
+			for end < len(runes) && end < i+1+digits && isHexDigit(runes[end]) {
+				end++
+			}
+			code, err := strconv.ParseUint(string(runes[i+1:end]), 16, 32)
+			if err != nil {
+				word.WriteRune(runes[i])
+				continue
+			}
+			if runes[i] == 'x' {
+				word.WriteByte(byte(code))
+			} else {
+				word.WriteRune(rune(code))
+			}
+			i = end - 1
+		default:
+			// \\, \', \" and unknown escapes yield the character itself
+			word.WriteRune(runes[i])
+		}
+	}
+	return 0, errUnterminatedQuote
+}
+
+func isHexDigit(r rune) bool {
+	return strings.ContainsRune("0123456789abcdefABCDEF", r)
+}
+
+// safeShellWord matches words that need no quoting
+var safeShellWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes a word for POSIX shells
+func shellQuote(s string) string {
+	if safeShellWord.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// ToCurl returns the request as a curl command that can be pasted into a
+// POSIX shell. OAuth 2.0 tokens are only obtained when the request is sent,
+// so they are exported as a placeholder.
+func (r *Request) ToCurl() string {
+	args := []string{"curl"}
+
+	switch {
+	case r.Method == "HEAD":
+		args = append(args, "--head")
+	case r.Method != "GET" || r.HasBody():
+		args = append(args, "-X", r.Method)
+	}
+
+	rawURL := r.URL
+	if r.Auth.Type == AuthAPIKey && r.Auth.Key != "" && r.Auth.In == APIKeyInQuery {
+		separator := "?"
+		if strings.Contains(rawURL, "?") {
+			separator = "&"
+		}
+		rawURL += separator + url.QueryEscape(r.Auth.Key) + "=" + url.QueryEscape(r.Auth.Value)
+	}
+	args = append(args, rawURL)
+
+	for _, h := range r.Headers {
+		if !h.Disabled && h.Key != "" {
+			args = append(args, "-H", h.Key+": "+h.Value)
+		}
+	}
+	if r.HasBody() && !r.Headers.Has("Content-Type") && r.BodyType != BodyURLEncoded && r.BodyType != BodyMultipart {
+		// Mirror the default set by the client; curl sets the form ones itself
+		args = append(args, "-H", "Content-Type: "+r.ContentType())
+	}
+
+	switch r.Auth.Type {
+	case AuthBasic:
+		args = append(args, "-u", r.Auth.Username+":"+r.Auth.Password)
+	case AuthDigest:
+		args = append(args, "--digest", "-u", r.Auth.Username+":"+r.Auth.Password)
+	case AuthBearer:
+		args = append(args, "-H", "Authorization: Bearer "+r.Auth.Token)
+	case AuthAPIKey:
+		if r.Auth.Key != "" && r.Auth.In != APIKeyInQuery {
+			args = append(args, "-H", r.Auth.Key+": "+r.Auth.Value)
+		}
+	case AuthOAuth2:
+		args = append(args, "-H", "Authorization: Bearer <access-token>")
+	}
+
+	if r.HasBody() {
+		args = append(args, r.curlBody()...)
+	}
+
+	if r.Settings.Timeout > 0 {
+		args = append(args, "-m", strconv.Itoa(r.Settings.Timeout))
+	}
+	if r.Settings.SSLVerify != nil && !*r.Settings.SSLVerify {
+		args = append(args, "-k")
+	}
+	switch r.Settings.Proxy {
+	case "":
+	case ProxyDirect:
+		args = append(args, "--noproxy", "*")
+	default:
+		args = append(args, "-x", r.Settings.Proxy)
+	}
+
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+	return strings.Join(args, " ")
+}
@@ -0,0 +1,395 @@
+package http
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"spaces and tabs", "curl  -s\thttps://example.com ", []string{"curl", "-s", "https://example.com"}},
+		{"single quotes", `curl -H 'X-A: "b" \n'`, []string{"curl", "-H", `X-A: "b" \n`}},
+		{"double quotes", `curl -d "a \"b\" \$c \\ \n"`, []string{"curl", "-d", `a "b" $c \ \n`}},
+		{"adjacent quotes join", `curl 'a'"b"c`, []string{"curl", "abc"}},
+		{"empty quotes", `curl -d ''`, []string{"curl", "-d", ""}},
+		{"backslash escapes", `curl a\ b\'c`, []string{"curl", "a b'c"}},
+		{"line continuation", "curl \\\n  -X POST \\\r\n  https://example.com", []string{"curl", "-X", "POST", "https://example.com"}},
+		{"continuation in double quotes", "curl \"a\\\nb\"", []string{"curl", "ab"}},
+		{"ANSI-C quoting", `curl $'a\nb\t\x41é\'\\'`, []string{"curl", "a\nb\tAé'\\"}},
+		{"ANSI-C joined to a word", `curl -d$'{"a":1}'`, []string{"curl", `-d{"a":1}`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitShellWords(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitShellWords(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitShellWordsUnterminated(t *testing.T) {
+	for _, input := range []string{`curl 'a`, `curl "a`, `curl $'a`, `curl "a\"`} {
+		if _, err := splitShellWords(input); err != errUnterminatedQuote {
+			t.Errorf("splitShellWords(%q) error = %v, want %v", input, err, errUnterminatedQuote)
+		}
+	}
+}
+
+func TestParseCurl(t *testing.T) {
+	dir := t.TempDir()
+	dataFile := filepath.Join(dir, "data.txt")
+	if err := os.WriteFile(dataFile, []byte("a=1\nb=2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	noVerify := false
+
+	tests := []struct {
+		name    string
+		command string
+		want    *Request
+	}{
+		{
+			name:    "plain GET",
+			command: "curl https://example.com/users",
+			want:    &Request{Method: "GET", URL: "https://example.com/users"},
+		},
+		{
+			name:    "method and headers in order",
+			command: `curl -X delete -H 'Accept: application/json' -H "X-Id: 1" -H 'X-Id: 2' -H 'Empty;' -H 'Dropped:' https://example.com`,
+			want: &Request{
+				Method: "DELETE",
+				URL:    "https://example.com",
+				Headers: Headers{
+					{Key: "Accept", Value: "application/json"},
+					{Key: "X-Id", Value: "1"},
+					{Key: "X-Id", Value: "2"},
+					{Key: "Empty"},
+				},
+			},
+		},
+		{
+			name:    "combined short options with attached values",
+			command: `curl -sSLXPUT -HAccept:text/plain https://example.com`,
+			want: &Request{
+				Method:  "PUT",
+				URL:     "https://example.com",
+				Headers: Headers{{Key: "Accept", Value: "text/plain"}},
+			},
+		},
+		{
+			name:    "JSON data",
+			command: `curl https://example.com -H 'Content-Type: application/json' --data-raw '{"a":1}'`,
+			want: &Request{
+				Method:  "POST",
+				URL:     "https://example.com",
+				Headers: Headers{{Key: "Content-Type", Value: "application/json"}},
+				Body:    `{"a":1}`,
+			},
+		},
+		{
+			name:    "--json",
+			command: `curl --json '{"a":1}' https://example.com`,
+			want: &Request{
+				Method: "POST",
+				URL:    "https://example.com",
+				Headers: Headers{
+					{Key: "Content-Type", Value: "application/json"},
+					{Key: "Accept", Value: "application/json"},
+				},
+				Body: `{"a":1}`,
+			},
+		},
+		{
+			name:    "data joined as a url-encoded form",
+			command: `curl -d a=1 --data 'b=x%20y' --data-urlencode 'c=d&e' https://example.com`,
+			want: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				BodyType: BodyURLEncoded,
+				Form:     []KeyValue{{Key: "a", Value: "1"}, {Key: "b", Value: "x y"}, {Key: "c", Value: "d&e"}},
+			},
+		},
+		{
+			name:    "data from a file without line breaks",
+			command: "curl -d @" + dataFile + " https://example.com",
+			want: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				BodyType: BodyURLEncoded,
+				Form:     []KeyValue{{Key: "a", Value: "1b=2"}},
+			},
+		},
+		{
+			name:    "data that isn't a form",
+			command: `curl -d 'hello world' https://example.com`,
+			want: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				Headers:  Headers{{Key: "Content-Type", Value: "application/x-www-form-urlencoded"}},
+				BodyType: BodyText,
+				Body:     "hello world",
+			},
+		},
+		{
+			name:    "XML data",
+			command: `curl -H 'Content-Type: text/xml' -d '<a/>' https://example.com`,
+			want: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				Headers:  Headers{{Key: "Content-Type", Value: "text/xml"}},
+				BodyType: BodyXML,
+				Body:     "<a/>",
+			},
+		},
+		{
+			name:    "-G moves data to the query",
+			command: `curl -G -d q=go -d page=2 'https://example.com/search?lang=en'`,
+			want:    &Request{Method: "GET", URL: "https://example.com/search?lang=en&q=go&page=2"},
+		},
+		{
+			name:    "multipart form",
+			command: "curl -F name=ada -F 'avatar=@" + dataFile + ";type=text/plain' --form-string 'note=@not a file' https://example.com",
+			want: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				BodyType: BodyMultipart,
+				Form: []KeyValue{
+					{Key: "name", Value: "ada"},
+					{Key: "avatar", Value: dataFile, File: true},
+					{Key: "note", Value: "@not a file"},
+				},
+			},
+		},
+		{
+			name:    "basic auth",
+			command: `curl -u 'ada:pa:ss' https://example.com`,
+			want: &Request{
+				Method: "GET",
+				URL:    "https://example.com",
+				Auth:   Auth{Type: AuthBasic, Username: "ada", Password: "pa:ss"},
+			},
+		},
+		{
+			name:    "digest auth",
+			command: `curl --digest --user ada:secret https://example.com`,
+			want: &Request{
+				Method: "GET",
+				URL:    "https://example.com",
+				Auth:   Auth{Type: AuthDigest, Username: "ada", Password: "secret"},
+			},
+		},
+		{
+			name:    "bearer auth",
+			command: `curl --oauth2-bearer abc https://example.com`,
+			want: &Request{
+				Method: "GET",
+				URL:    "https://example.com",
+				Auth:   Auth{Type: AuthBearer, Token: "abc"},
+			},
+		},
+		{
+			name:    "cookies, agent and referer",
+			command: `curl -b 'a=1' --cookie 'b=2' -A 'agent/1' -e https://ref.example.com https://example.com`,
+			want: &Request{
+				Method: "GET",
+				URL:    "https://example.com",
+				Headers: Headers{
+					{Key: "Cookie", Value: "a=1; b=2"},
+					{Key: "User-Agent", Value: "agent/1"},
+					{Key: "Referer", Value: "https://ref.example.com"},
+				},
+			},
+		},
+		{
+			name:    "settings",
+			command: `curl -k -m 2.5 -x socks5://127.0.0.1:1080 https://example.com`,
+			want: &Request{
+				Method:   "GET",
+				URL:      "https://example.com",
+				Settings: Settings{Timeout: 3, SSLVerify: &noVerify, Proxy: "socks5://127.0.0.1:1080"},
+			},
+		},
+		{
+			name:    "ignored options and their values",
+			command: `curl --retry 3 --connect-timeout 5 -o out.json -w '%{http_code}' --compressed --no-progress-meter -I https://example.com`,
+			want:    &Request{Method: "HEAD", URL: "https://example.com"},
+		},
+		{
+			name:    "browser Copy as cURL",
+			command: "$ curl 'https://example.com/api' \\\n  -H 'accept: */*' \\\n  -H 'content-type: application/json' \\\n  --data-raw $'{\"a\":\"it\\'s\"}' \\\n  --compressed",
+			want: &Request{
+				Method:  "POST",
+				URL:     "https://example.com/api",
+				Headers: Headers{{Key: "accept", Value: "*/*"}, {Key: "content-type", Value: "application/json"}},
+				Body:    `{"a":"it's"}`,
+			},
+		},
+		{
+			name:    "URL after --",
+			command: `curl -s -- -weird.example.com`,
+			want:    &Request{Method: "GET", URL: "-weird.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCurl(tt.command)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCurl(%q)\n got  %+v\n want %+v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCurlErrors(t *testing.T) {
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"wget https://example.com", "not a curl command"},
+		{"curl -s", "has no URL"},
+		{"curl -X", "requires a value"},
+		{"curl https://example.com -H", "requires a value"},
+		{"curl --frobnicate 3 https://example.com", "unsupported curl option --frobnicate"},
+		{"curl -W https://example.com", "unsupported curl option -W"},
+		{"curl --request=POST https://example.com", "unsupported curl option --request=POST"},
+		{"curl -m soon https://example.com", "invalid curl --max-time"},
+		{"curl -F novalue https://example.com", "expected name=value"},
+		{"curl 'https://example.com", "unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseCurl(tt.command)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseCurl(%q) error = %v, want %q", tt.command, err, tt.want)
+		}
+	}
+}
+
+func TestToCurl(t *testing.T) {
+	req := &Request{
+		Method:  "POST",
+		URL:     "https://example.com/a b",
+		Headers: Headers{{Key: "X-Quote", Value: "it's"}, {Key: "X-Off", Value: "1", Disabled: true}},
+		Body:    `{"a":1}`,
+		Auth:    Auth{Type: AuthAPIKey, Key: "api key", Value: "k&v", In: APIKeyInQuery},
+	}
+	want := `curl -X POST 'https://example.com/a b?api+key=k%26v' -H 'X-Quote: it'\''s' -H 'Content-Type: application/json' --data-raw '{"a":1}'`
+	if got := req.ToCurl(); got != want {
+		t.Errorf("ToCurl() =\n %s\nwant\n %s", got, want)
+	}
+}
+
+func TestCurlRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "photo 1.png")
+	noVerify := false
+
+	tests := []struct {
+		name string
+		req  *Request
+	}{
+		{
+			name: "GET with headers",
+			req: &Request{
+				Method:  "GET",
+				URL:     "https://example.com/users?page=2&q=a%20b",
+				Headers: Headers{{Key: "Accept", Value: "application/json"}, {Key: "X-Id", Value: "1"}, {Key: "X-Id", Value: "2"}},
+			},
+		},
+		{
+			name: "HEAD",
+			req:  &Request{Method: "HEAD", URL: "https://example.com"},
+		},
+		{
+			name: "DELETE without a body",
+			req:  &Request{Method: "DELETE", URL: "https://example.com/users/1"},
+		},
+		{
+			name: "JSON body with quotes",
+			req: &Request{
+				Method:  "PATCH",
+				URL:     "https://example.com",
+				Headers: Headers{{Key: "Content-Type", Value: "application/json"}},
+				Body:    `{"name": "O'Brien", "tags": ["a\nb"]}`,
+			},
+		},
+		{
+			name: "XML body",
+			req: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				Headers:  Headers{{Key: "Content-Type", Value: "application/xml"}},
+				BodyType: BodyXML,
+				Body:     "<a>1</a>",
+			},
+		},
+		{
+			name: "url-encoded form",
+			req: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				BodyType: BodyURLEncoded,
+				Form:     []KeyValue{{Key: "a b", Value: "1&2"}, {Key: "c", Value: "=é"}},
+			},
+		},
+		{
+			name: "multipart form",
+			req: &Request{
+				Method:   "POST",
+				URL:      "https://example.com",
+				BodyType: BodyMultipart,
+				Form: []KeyValue{
+					{Key: "name", Value: "ada"},
+					{Key: "note", Value: "@home; really"},
+					{Key: "photo", Value: file, File: true},
+				},
+			},
+		},
+		{
+			name: "basic auth and settings",
+			req: &Request{
+				Method:   "GET",
+				URL:      "https://example.com",
+				Auth:     Auth{Type: AuthBasic, Username: "ada", Password: "p@ss word"},
+				Settings: Settings{Timeout: 10, SSLVerify: &noVerify, Proxy: "http://proxy:8080"},
+			},
+		},
+		{
+			name: "digest auth without a proxy",
+			req: &Request{
+				Method:   "GET",
+				URL:      "https://example.com",
+				Auth:     Auth{Type: AuthDigest, Username: "ada", Password: "secret"},
+				Settings: Settings{Proxy: ProxyDirect},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command := tt.req.ToCurl()
+			got, err := ParseCurl(command)
+			if err != nil {
+				t.Fatalf("ParseCurl(%s): %v", command, err)
+			}
+			if !reflect.DeepEqual(got, tt.req) {
+				t.Errorf("round trip through %s\n got  %+v\n want %+v", command, got, tt.req)
+			}
+		})
+	}
+}
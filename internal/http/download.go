@@ -0,0 +1,134 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DownloadConfig controls when response bodies are streamed to disk instead
+// of being kept in memory
+type DownloadConfig struct {
+	Threshold int64  // bodies larger than this many bytes go to a file, 0 keeps every body in memory
+	Dir       string // where streamed bodies are written, the temp directory when empty
+}
+
+type progressKey struct{}
+
+// WithProgress returns a context that makes Execute call fn as the response
+// body is read, with the bytes read so far and the Content-Length, or -1
+// when it is unknown
+func WithProgress(ctx context.Context, fn func(read, total int64)) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+// progressReader reports the bytes read through it
+type progressReader struct {
+	r     io.Reader
+	read  int64
+	total int64
+	fn    func(read, total int64)
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	n, err := pr.r.Read(p)
+	pr.read += int64(n)
+	pr.fn(pr.read, pr.total)
+	return n, err
+}
+
+// readBody reads a response body into memory, or streams it to a file once
+// it grows past the download threshold. It returns the body or the path of
+// the file, and the size of the body.
+func (c *Client) readBody(ctx context.Context, req *Request, resp *http.Response) ([]byte, string, int64, error) {
+	var r io.Reader = resp.Body
+	if fn, ok := ctx.Value(progressKey{}).(func(read, total int64)); ok {
+		r = &progressReader{r: r, total: resp.ContentLength, fn: fn}
+	}
+
+	threshold := c.download.Threshold
+	if threshold <= 0 {
+		body, err := io.ReadAll(r)
+		return body, "", int64(len(body)), err
+	}
+
+	// Only bodies past the threshold are written to disk
+	var buf bytes.Buffer
+	n, err := io.CopyN(&buf, r, threshold+1)
+	if errors.Is(err, io.EOF) {
+		return buf.Bytes(), "", n, nil
+	}
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	f, err := createDownloadFile(c.download.Dir, DownloadName(resp.Header, req.URL))
+	if err != nil {
+		return nil, "", 0, err
+	}
+	size, err := io.Copy(f, io.MultiReader(&buf, r))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, "", 0, err
+	}
+	return nil, f.Name(), size, nil
+}
+
+// DownloadName returns the file name to save a response body as: the one
+// suggested by Content-Disposition, or the last segment of the URL path
+func DownloadName(headers http.Header, rawURL string) string {
+	name := ""
+	if _, params, err := mime.ParseMediaType(headers.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if name == "" {
+		if u, err := url.Parse(rawURL); err == nil {
+			name = path.Base(u.Path)
+		}
+	}
+
+	// Never let the name leave the download directory
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == ".." {
+		return "response"
+	}
+	return name
+}
+
+// createDownloadFile creates a new file named name in dir, numbering the
+// name if a file of that name exists
+func createDownloadFile(dir, name string) (*os.File, error) {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 0; i < 1000; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+		}
+		f, err := os.OpenFile(filepath.Join(dir, candidate), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644)
+		if !errors.Is(err, fs.ErrExist) {
+			return f, err
+		}
+	}
+	return os.CreateTemp(dir, "*-"+name)
+}
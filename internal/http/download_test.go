@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDownloadThreshold(t *testing.T) {
+	const threshold = 1024
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+		if r.URL.Query().Has("name") {
+			w.Header().Set("Content-Disposition", `attachment; filename="`+r.URL.Query().Get("name")+`"`)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		w.Write([]byte(strings.Repeat("x", size)))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	c, err := NewClient(DefaultTransportConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetDownloadConfig(DownloadConfig{Threshold: threshold, Dir: dir})
+
+	tests := []struct {
+		name     string
+		query    string
+		size     int64
+		wantFile string // "" when the body is kept in memory
+	}{
+		{"empty", "size=0", 0, ""},
+		{"at the threshold", "size=1024", threshold, ""},
+		{"past the threshold", "size=1025", threshold + 1, "download"},
+		{"named by Content-Disposition", "size=5000&name=report.csv", 5000, "report.csv"},
+		{"numbered when the name is taken", "size=5000&name=report.csv", 5000, "report (1).csv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var read, total int64
+			ctx := WithProgress(context.Background(), func(r, t int64) { read, total = r, t })
+			resp := c.Execute(ctx, &Request{Method: "GET", URL: server.URL + "/download?" + tt.query})
+			if resp.Error != nil {
+				t.Fatal(resp.Error)
+			}
+			if resp.Size != tt.size || read != tt.size || total != tt.size {
+				t.Errorf("size = %d, progress %d of %d, want %d", resp.Size, read, total, tt.size)
+			}
+
+			if tt.wantFile == "" {
+				if resp.BodyFile != "" || int64(len(resp.Body)) != tt.size {
+					t.Errorf("body of %d bytes went to %q, want %d bytes in memory", len(resp.Body), resp.BodyFile, tt.size)
+				}
+				return
+			}
+			if want := filepath.Join(dir, tt.wantFile); resp.BodyFile != want || resp.Body != nil {
+				t.Fatalf("body went to %q with %d bytes in memory, want %s", resp.BodyFile, len(resp.Body), want)
+			}
+			info, err := os.Stat(resp.BodyFile)
+			if err != nil || info.Size() != tt.size {
+				t.Errorf("streamed file = %v, %v, want %d bytes", info, err, tt.size)
+			}
+		})
+	}
+}
+
+func TestDownloadName(t *testing.T) {
+	tests := []struct {
+		name        string
+		disposition string
+		url         string
+		want        string
+	}{
+		{"from the URL", "", "https://example.com/files/report.pdf?v=2", "report.pdf"},
+		{"from Content-Disposition", `attachment; filename="data.csv"`, "https://example.com/export", "data.csv"},
+		{"encoded Content-Disposition name", `attachment; filename*=UTF-8''r%C3%A9sum%C3%A9.txt`, "https://example.com/cv", "résumé.txt"},
+		{"invalid Content-Disposition", `attachment; filename="a`, "https://example.com/b.txt", "b.txt"},
+		{"no path", "", "https://example.com", "response"},
+		{"root path", "", "https://example.com/", "response"},
+		{"parent directory", `attachment; filename=".."`, "https://example.com/", "response"},
+		{"Unix path", `attachment; filename="../../etc/passwd"`, "https://example.com/", "passwd"},
+		{"absolute path", `attachment; filename="/tmp/evil.sh"`, "https://example.com/", "evil.sh"},
+		{"Windows path", `attachment; filename="..\\..\\Windows\\evil.dll"`, "https://example.com/", "evil.dll"},
+		{"escaped slashes in the URL", "", "https://example.com/a%2F..%2F..%2Fb.txt", "b.txt"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			headers := http.Header{}
+			if tt.disposition != "" {
+				headers.Set("Content-Disposition", tt.disposition)
+			}
+			if got := DownloadName(headers, tt.url); got != tt.want {
+				t.Errorf("DownloadName(%q, %q) = %q, want %q", tt.disposition, tt.url, got, tt.want)
+			}
+		})
+	}
+}
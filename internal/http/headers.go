@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// KeyValue is an entry of an ordered list of pairs such as request headers.
+// Disabled entries stay with the request without being sent.
+type KeyValue struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	File        bool   `json:"file,omitempty"` // the value is the path of a file to upload, for multipart fields
+}
+
+// Headers is the ordered list of headers of a request. A key may appear more
+// than once, and keys are matched case-insensitively.
+type Headers []KeyValue
+
+// Get returns the value of the first enabled header with the given key
+func (h Headers) Get(key string) string {
+	for _, kv := range h {
+		if !kv.Disabled && strings.EqualFold(kv.Key, key) {
+			return kv.Value
+		}
+	}
+	return ""
+}
+
+// Has reports whether an enabled header has the given key
+func (h Headers) Has(key string) bool {
+	for _, kv := range h {
+		if !kv.Disabled && strings.EqualFold(kv.Key, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends a header, keeping any others with the same key
+func (h *Headers) Add(key, value string) {
+	*h = append(*h, KeyValue{Key: key, Value: value})
+}
+
+// Set replaces the value of the first enabled header with the given key and
+// removes the other enabled ones, or appends the header if there is none
+func (h *Headers) Set(key, value string) {
+	set := false
+	kept := (*h)[:0]
+	for _, kv := range *h {
+		if !kv.Disabled && strings.EqualFold(kv.Key, key) {
+			if set {
+				continue
+			}
+			kv.Value = value
+			set = true
+		}
+		kept = append(kept, kv)
+	}
+	*h = kept
+	if !set {
+		h.Add(key, value)
+	}
+}
+
+// Clone returns a copy of the headers
+func (h Headers) Clone() Headers {
+	if h == nil {
+		return nil
+	}
+	return append(Headers{}, h...)
+}
+
+// UnmarshalJSON reads a list of headers, or the object of names to values
+// that requests were stored as before headers were ordered
+func (h *Headers) UnmarshalJSON(data []byte) error {
+	var list []KeyValue
+	if err := json.Unmarshal(data, &list); err == nil {
+		*h = list
+		return nil
+	}
+
+	var m map[string]string
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	*h = make(Headers, 0, len(keys))
+	for _, key := range keys {
+		h.Add(key, m[key])
+	}
+	return nil
+}
@@ -0,0 +1,381 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth 2.0 grant types
+const (
+	GrantClientCredentials = "client_credentials"
+	GrantPassword          = "password"
+	GrantAuthorizationCode = "authorization_code"
+	grantRefreshToken      = "refresh_token"
+)
+
+const (
+	// authorizationWaitPeriod is how long to wait for the browser redirect
+	authorizationWaitPeriod = 5 * time.Minute
+	// tokenExpiryDelta refreshes tokens slightly early to allow for clock skew and latency
+	tokenExpiryDelta = 30 * time.Second
+)
+
+// OAuth2Token is an access token issued by a token endpoint
+type OAuth2Token struct {
+	AccessToken  string
+	TokenType    string
+	RefreshToken string
+	Expiry       time.Time // zero if the server didn't say
+}
+
+// Valid returns true if the token can still be used
+func (t *OAuth2Token) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(tokenExpiryDelta).Before(t.Expiry)
+}
+
+// oauth2Manager obtains OAuth 2.0 tokens and caches them per environment
+type oauth2Manager struct {
+	mu     sync.Mutex
+	tokens map[string]*OAuth2Token
+	locks  map[string]*sync.Mutex
+
+	// onAuthorize is called with the URL the user must open for the authorization code flow
+	onAuthorize func(authURL string)
+}
+
+func newOAuth2Manager() *oauth2Manager {
+	return &oauth2Manager{
+		tokens: make(map[string]*OAuth2Token),
+		locks:  make(map[string]*sync.Mutex),
+	}
+}
+
+// tokenCacheKey identifies a token by environment and the settings that produced it
+func tokenCacheKey(environment string, auth Auth) string {
+	return strings.Join([]string{
+		environment, auth.GrantType, auth.TokenURL, auth.ClientID, auth.Scope, auth.Username,
+	}, "\x00")
+}
+
+// keyLock returns the mutex serializing token requests for a cache key
+func (m *oauth2Manager) keyLock(key string) *sync.Mutex {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock, ok := m.locks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.locks[key] = lock
+	}
+	return lock
+}
+
+// Token returns a valid access token for the request, using the cached token
+// when possible. With forceRefresh the cached access token is discarded, e.g.
+// after the server rejected it with a 401.
+func (m *oauth2Manager) Token(ctx context.Context, httpClient *http.Client, req *Request, forceRefresh bool) (*OAuth2Token, error) {
+	key := tokenCacheKey(req.Environment, req.Auth)
+	lock := m.keyLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	m.mu.Lock()
+	cached := m.tokens[key]
+	m.mu.Unlock()
+
+	if cached.Valid() && !forceRefresh {
+		return cached, nil
+	}
+
+	var token *OAuth2Token
+	var err error
+	if cached != nil && cached.RefreshToken != "" {
+		token, err = m.refresh(ctx, httpClient, req.Auth, cached.RefreshToken)
+	}
+	if token == nil {
+		// No refresh token, or the refresh was rejected: run the grant again
+		token, err = m.fetch(ctx, httpClient, req.Auth)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.tokens[key] = token
+	m.mu.Unlock()
+	return token, nil
+}
+
+// fetch obtains a new token using the configured grant
+func (m *oauth2Manager) fetch(ctx context.Context, httpClient *http.Client, auth Auth) (*OAuth2Token, error) {
+	form := url.Values{}
+	if auth.Scope != "" {
+		form.Set("scope", auth.Scope)
+	}
+
+	switch auth.GrantType {
+	case GrantClientCredentials, "":
+		form.Set("grant_type", GrantClientCredentials)
+	case GrantPassword:
+		form.Set("grant_type", GrantPassword)
+		form.Set("username", auth.Username)
+		form.Set("password", auth.Password)
+	case GrantAuthorizationCode:
+		return m.authorizationCode(ctx, httpClient, auth)
+	default:
+		return nil, fmt.Errorf("unsupported OAuth 2.0 grant type %q", auth.GrantType)
+	}
+
+	return requestToken(ctx, httpClient, auth, form, "")
+}
+
+// refresh exchanges a refresh token for a new access token
+func (m *oauth2Manager) refresh(ctx context.Context, httpClient *http.Client, auth Auth, refreshToken string) (*OAuth2Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", grantRefreshToken)
+	form.Set("refresh_token", refreshToken)
+	if auth.Scope != "" {
+		form.Set("scope", auth.Scope)
+	}
+	return requestToken(ctx, httpClient, auth, form, refreshToken)
+}
+
+// authorizationCode runs the authorization code flow with PKCE (RFC 7636),
+// capturing the redirect on a loopback listener (RFC 8252)
+func (m *oauth2Manager) authorizationCode(ctx context.Context, httpClient *http.Client, auth Auth) (*OAuth2Token, error) {
+	if auth.AuthURL == "" {
+		return nil, errors.New("authorization URL is required for the authorization code flow")
+	}
+	if m.onAuthorize == nil {
+		return nil, errors.New("authorization code flow is not available")
+	}
+
+	redirectURL, listener, err := listenLoopback(auth.RedirectURL)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+
+	verifier, err := randomBase64URL(32)
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomBase64URL(16)
+	if err != nil {
+		return nil, err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	authURL, err := url.Parse(auth.AuthURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization URL: %w", err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", auth.ClientID)
+	query.Set("redirect_uri", redirectURL.String())
+	query.Set("state", state)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+	if auth.Scope != "" {
+		query.Set("scope", auth.Scope)
+	}
+	authURL.RawQuery = query.Encode()
+
+	// Serve the redirect and hand the code back
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	server := &http.Server{
+		ReadHeaderTimeout: 10 * time.Second,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != redirectURL.Path {
+				http.NotFound(w, r)
+				return
+			}
+			params := r.URL.Query()
+			var res result
+			switch {
+			case params.Get("error") != "":
+				res.err = fmt.Errorf("authorization failed: %s %s", params.Get("error"), params.Get("error_description"))
+			case params.Get("state") != state:
+				res.err = errors.New("authorization failed: state mismatch")
+			case params.Get("code") == "":
+				res.err = errors.New("authorization failed: no code in redirect")
+			default:
+				res.code = params.Get("code")
+			}
+
+			if res.err != nil {
+				http.Error(w, res.err.Error(), http.StatusBadRequest)
+			} else {
+				fmt.Fprintln(w, "Authorization complete. You can close this window and return to TRexT.")
+			}
+			select {
+			case results <- res:
+			default:
+			}
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	m.onAuthorize(authURL.String())
+
+	var res result
+	select {
+	case res = <-results:
+	case <-time.After(authorizationWaitPeriod):
+		return nil, errors.New("timed out waiting for authorization")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", GrantAuthorizationCode)
+	form.Set("code", res.code)
+	form.Set("redirect_uri", redirectURL.String())
+	form.Set("code_verifier", verifier)
+	return requestToken(ctx, httpClient, auth, form, "")
+}
+
+// listenLoopback listens on the loopback address of the redirect URL.
+// An empty redirect URL picks a free port.
+func listenLoopback(raw string) (*url.URL, net.Listener, error) {
+	if raw == "" {
+		raw = "http://127.0.0.1:0/callback"
+	}
+	redirectURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid redirect URL: %w", err)
+	}
+	if redirectURL.Scheme != "http" {
+		return nil, nil, errors.New("redirect URL must use http on a loopback address")
+	}
+	switch redirectURL.Hostname() {
+	case "127.0.0.1", "localhost", "::1":
+	default:
+		return nil, nil, errors.New("redirect URL must use a loopback address such as 127.0.0.1")
+	}
+	if redirectURL.Path == "" {
+		redirectURL.Path = "/"
+	}
+
+	listener, err := net.Listen("tcp", redirectURL.Host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen for redirect: %w", err)
+	}
+
+	// Fill in the port chosen by the system
+	port := strconv.Itoa(listener.Addr().(*net.TCPAddr).Port)
+	redirectURL.Host = net.JoinHostPort(redirectURL.Hostname(), port)
+	return redirectURL, listener, nil
+}
+
+// tokenResponse is the JSON body returned by a token endpoint
+type tokenResponse struct {
+	AccessToken      string      `json:"access_token"`
+	TokenType        string      `json:"token_type"`
+	RefreshToken     string      `json:"refresh_token"`
+	ExpiresIn        json.Number `json:"expires_in"`
+	Error            string      `json:"error"`
+	ErrorDescription string      `json:"error_description"`
+}
+
+// requestToken posts a grant to the token endpoint. The client authenticates
+// with HTTP Basic when it has a secret, otherwise it sends its ID in the form.
+// refreshToken is kept if the server doesn't rotate it.
+func requestToken(ctx context.Context, httpClient *http.Client, auth Auth, form url.Values, refreshToken string) (*OAuth2Token, error) {
+	if auth.TokenURL == "" {
+		return nil, errors.New("token URL is required for OAuth 2.0")
+	}
+	if auth.ClientSecret == "" {
+		form.Set("client_id", auth.ClientID)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, auth.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	httpReq.Header.Set("Accept", "application/json")
+	if auth.ClientSecret != "" {
+		httpReq.SetBasicAuth(url.QueryEscape(auth.ClientID), url.QueryEscape(auth.ClientSecret))
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+
+	var parsed tokenResponse
+	if strings.Contains(resp.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		values, _ := url.ParseQuery(string(body))
+		parsed = tokenResponse{
+			AccessToken:      values.Get("access_token"),
+			TokenType:        values.Get("token_type"),
+			RefreshToken:     values.Get("refresh_token"),
+			ExpiresIn:        json.Number(values.Get("expires_in")),
+			Error:            values.Get("error"),
+			ErrorDescription: values.Get("error_description"),
+		}
+	} else if err := json.Unmarshal(body, &parsed); err != nil && resp.StatusCode < 300 {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+
+	if parsed.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s %s", parsed.Error, parsed.ErrorDescription)
+	}
+	if resp.StatusCode >= 300 || parsed.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed: %s", resp.Status)
+	}
+
+	token := &OAuth2Token{
+		AccessToken:  parsed.AccessToken,
+		TokenType:    parsed.TokenType,
+		RefreshToken: parsed.RefreshToken,
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	if seconds, err := parsed.ExpiresIn.Int64(); err == nil && seconds > 0 {
+		token.Expiry = time.Now().Add(time.Duration(seconds) * time.Second)
+	}
+	return token, nil
+}
+
+// randomBase64URL returns n random bytes encoded as unpadded base64url
+func randomBase64URL(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,343 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// tokenServer is a stand-in token endpoint that hands out numbered tokens
+// and records the grants it was sent
+type tokenServer struct {
+	*httptest.Server
+
+	mu     sync.Mutex
+	grants []url.Values
+	auth   []string // the Authorization header of each grant
+
+	// expiresIn is sent with each token, if set
+	expiresIn int
+	// check may reject a grant with an OAuth 2.0 error code
+	check func(form url.Values) string
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	ts := &tokenServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("token request: %v", err)
+		}
+
+		ts.mu.Lock()
+		ts.grants = append(ts.grants, r.PostForm)
+		ts.auth = append(ts.auth, r.Header.Get("Authorization"))
+		n := len(ts.grants)
+		ts.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if ts.check != nil {
+			if code := ts.check(r.PostForm); code != "" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": code})
+				return
+			}
+		}
+		resp := map[string]any{
+			"access_token":  fmt.Sprintf("token-%d", n),
+			"token_type":    "Bearer",
+			"refresh_token": fmt.Sprintf("refresh-%d", n),
+		}
+		if ts.expiresIn > 0 {
+			resp["expires_in"] = ts.expiresIn
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// grantTypes returns the grant_type of each token request
+func (ts *tokenServer) grantTypes() []string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	types := make([]string, len(ts.grants))
+	for i, form := range ts.grants {
+		types[i] = form.Get("grant_type")
+	}
+	return types
+}
+
+// newResourceServer returns a server that accepts the given bearer tokens and
+// records the Authorization header of each request
+func newResourceServer(t *testing.T, accepted ...string) (*httptest.Server, *[]string) {
+	var mu sync.Mutex
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r.Header.Get("Authorization"))
+		mu.Unlock()
+		for _, token := range accepted {
+			if r.Header.Get("Authorization") == "Bearer "+token {
+				w.Write([]byte("ok"))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &seen
+}
+
+func newTestClient(t *testing.T) *Client {
+	c, err := NewClient(DefaultTransportConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestOAuth2Grants(t *testing.T) {
+	tests := []struct {
+		name      string
+		auth      Auth
+		wantForm  map[string]string
+		wantBasic bool
+	}{
+		{
+			name: "client credentials with a secret",
+			auth: Auth{GrantType: GrantClientCredentials, ClientID: "app", ClientSecret: "s3cret", Scope: "read"},
+			wantForm: map[string]string{
+				"grant_type": "client_credentials",
+				"scope":      "read",
+				"client_id":  "",
+			},
+			wantBasic: true,
+		},
+		{
+			name: "password without a secret",
+			auth: Auth{GrantType: GrantPassword, ClientID: "app", Username: "ada", Password: "pa ss"},
+			wantForm: map[string]string{
+				"grant_type": "password",
+				"username":   "ada",
+				"password":   "pa ss",
+				"client_id":  "app",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := newTokenServer(t)
+			resource, seen := newResourceServer(t, "token-1")
+			client := newTestClient(t)
+
+			tt.auth.Type = AuthOAuth2
+			tt.auth.TokenURL = tokens.URL
+			req := &Request{Method: "GET", URL: resource.URL, Auth: tt.auth}
+
+			// The second request reuses the cached token
+			for range 2 {
+				resp := client.Execute(context.Background(), req)
+				if resp.Error != nil || resp.StatusCode != http.StatusOK {
+					t.Fatalf("Execute = %d, %v", resp.StatusCode, resp.Error)
+				}
+			}
+			if want := []string{"Bearer token-1", "Bearer token-1"}; !slices.Equal(*seen, want) {
+				t.Errorf("resource saw %q, want %q", *seen, want)
+			}
+			if len(tokens.grants) != 1 {
+				t.Fatalf("token endpoint got %d requests, want 1", len(tokens.grants))
+			}
+
+			form := tokens.grants[0]
+			for key, want := range tt.wantForm {
+				if got := form.Get(key); got != want {
+					t.Errorf("form %s = %q, want %q", key, got, want)
+				}
+			}
+			user, pass, ok := (&http.Request{Header: http.Header{"Authorization": {tokens.auth[0]}}}).BasicAuth()
+			if ok != tt.wantBasic || ok && (user != "app" || pass != "s3cret") {
+				t.Errorf("client auth = %q, %q, %v, want basic %v", user, pass, ok, tt.wantBasic)
+			}
+		})
+	}
+}
+
+func TestOAuth2RefreshOnExpiry(t *testing.T) {
+	tokens := newTokenServer(t)
+	// Tokens expiring within tokenExpiryDelta count as expired already
+	tokens.expiresIn = 1
+	resource, seen := newResourceServer(t, "token-1", "token-2")
+	client := newTestClient(t)
+
+	req := &Request{
+		Method: "GET",
+		URL:    resource.URL,
+		Auth:   Auth{Type: AuthOAuth2, GrantType: GrantClientCredentials, TokenURL: tokens.URL, ClientID: "app"},
+	}
+	for range 2 {
+		if resp := client.Execute(context.Background(), req); resp.StatusCode != http.StatusOK {
+			t.Fatalf("Execute = %d, %v", resp.StatusCode, resp.Error)
+		}
+	}
+
+	if got, want := tokens.grantTypes(), []string{"client_credentials", "refresh_token"}; !slices.Equal(got, want) {
+		t.Errorf("grants = %q, want %q", got, want)
+	}
+	if got := tokens.grants[1].Get("refresh_token"); got != "refresh-1" {
+		t.Errorf("refresh_token = %q, want refresh-1", got)
+	}
+	if want := []string{"Bearer token-1", "Bearer token-2"}; !slices.Equal(*seen, want) {
+		t.Errorf("resource saw %q, want %q", *seen, want)
+	}
+}
+
+func TestOAuth2RefreshAfterRejection(t *testing.T) {
+	tests := []struct {
+		name       string
+		check      func(form url.Values) string
+		wantGrants []string
+	}{
+		{
+			name:       "refresh token accepted",
+			wantGrants: []string{"client_credentials", "refresh_token"},
+		},
+		{
+			name: "refresh token rejected",
+			check: func(form url.Values) string {
+				if form.Get("grant_type") == grantRefreshToken {
+					return "invalid_grant"
+				}
+				return ""
+			},
+			wantGrants: []string{"client_credentials", "refresh_token", "client_credentials"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens := newTokenServer(t)
+			tokens.check = tt.check
+			// The first token has been revoked, the one that replaces it works
+			resource, seen := newResourceServer(t, "token-2", "token-3")
+			client := newTestClient(t)
+
+			req := &Request{
+				Method: "GET",
+				URL:    resource.URL,
+				Auth:   Auth{Type: AuthOAuth2, GrantType: GrantClientCredentials, TokenURL: tokens.URL, ClientID: "app"},
+			}
+			resp := client.Execute(context.Background(), req)
+			if resp.Error != nil || resp.StatusCode != http.StatusOK {
+				t.Fatalf("Execute = %d, %v", resp.StatusCode, resp.Error)
+			}
+			if got := tokens.grantTypes(); !slices.Equal(got, tt.wantGrants) {
+				t.Errorf("grants = %q, want %q", got, tt.wantGrants)
+			}
+			if len(*seen) != 2 || (*seen)[0] != "Bearer token-1" {
+				t.Errorf("resource saw %q, want token-1 then its replacement", *seen)
+			}
+		})
+	}
+}
+
+func TestOAuth2AuthorizationCodePKCE(t *testing.T) {
+	var challenge string
+	tokens := newTokenServer(t)
+	tokens.check = func(form url.Values) string {
+		sum := sha256.Sum256([]byte(form.Get("code_verifier")))
+		if form.Get("code") != "the-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			return "invalid_grant"
+		}
+		return ""
+	}
+	resource, _ := newResourceServer(t, "token-1")
+	client := newTestClient(t)
+
+	// Play the browser: approve and follow the redirect back
+	client.SetAuthorizationHandler(func(authURL string) {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		query := u.Query()
+		if query.Get("code_challenge_method") != "S256" || query.Get("response_type") != "code" || query.Get("client_id") != "app" {
+			t.Errorf("authorization URL %s", authURL)
+		}
+		challenge = query.Get("code_challenge")
+
+		redirect, _ := url.Parse(query.Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {"the-code"}, "state": {query.Get("state")}}.Encode()
+		go func() {
+			resp, err := http.Get(redirect.String())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	})
+
+	req := &Request{
+		Method: "GET",
+		URL:    resource.URL,
+		Auth: Auth{
+			Type:      AuthOAuth2,
+			GrantType: GrantAuthorizationCode,
+			AuthURL:   "https://auth.example.com/authorize?audience=api",
+			TokenURL:  tokens.URL,
+			ClientID:  "app",
+		},
+	}
+	resp := client.Execute(context.Background(), req)
+	if resp.Error != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Execute = %d, %v", resp.StatusCode, resp.Error)
+	}
+
+	form := tokens.grants[0]
+	if form.Get("grant_type") != GrantAuthorizationCode || form.Get("redirect_uri") == "" {
+		t.Errorf("token request form = %v", form)
+	}
+	if len(form.Get("code_verifier")) < 43 {
+		t.Errorf("code_verifier %q is shorter than RFC 7636 allows", form.Get("code_verifier"))
+	}
+}
+
+func TestOAuth2AuthorizationStateMismatch(t *testing.T) {
+	tokens := newTokenServer(t)
+	client := newTestClient(t)
+	client.SetAuthorizationHandler(func(authURL string) {
+		u, _ := url.Parse(authURL)
+		redirect, _ := url.Parse(u.Query().Get("redirect_uri"))
+		redirect.RawQuery = url.Values{"code": {"the-code"}, "state": {"forged"}}.Encode()
+		go func() {
+			if resp, err := http.Get(redirect.String()); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	})
+
+	req := &Request{
+		Method: "GET",
+		URL:    tokens.URL,
+		Auth:   Auth{Type: AuthOAuth2, GrantType: GrantAuthorizationCode, AuthURL: "https://auth.example.com/authorize", TokenURL: tokens.URL},
+	}
+	resp := client.Execute(context.Background(), req)
+	if resp.Error == nil || !strings.Contains(resp.Error.Error(), "state mismatch") {
+		t.Errorf("Execute error = %v, want a state mismatch", resp.Error)
+	}
+	if len(tokens.grants) != 0 {
+		t.Errorf("token endpoint got %d requests, want none", len(tokens.grants))
+	}
+}
@@ -0,0 +1,279 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Params are the parameters of a request that are edited as tables besides
+// the URL
+type Params struct {
+	// Query lists every query parameter, including the disabled ones the URL
+	// leaves out. The URL stays the source of the enabled ones.
+	Query []KeyValue `json:"query,omitempty"`
+
+	// Path holds the values of the :name and {name} placeholders in the URL
+	// path, which are substituted when the request is sent
+	Path []KeyValue `json:"path,omitempty"`
+}
+
+// Clone creates a deep copy of the params
+func (p Params) Clone() Params {
+	if p.Query != nil {
+		p.Query = append([]KeyValue{}, p.Query...)
+	}
+	if p.Path != nil {
+		p.Path = append([]KeyValue{}, p.Path...)
+	}
+	return p
+}
+
+// splitQuery splits a URL into the part before the query string, the query
+// string and the fragment, which keeps its '#'. It works on URLs that don't
+// parse yet, such as ones still being typed or starting with a variable.
+func splitQuery(rawURL string) (base, query, fragment string) {
+	if i := strings.IndexByte(rawURL, '#'); i >= 0 {
+		rawURL, fragment = rawURL[:i], rawURL[i:]
+	}
+	if i := strings.IndexByte(rawURL, '?'); i >= 0 {
+		return rawURL[:i], rawURL[i+1:], fragment
+	}
+	return rawURL, "", fragment
+}
+
+// QueryParams returns the parameters of the query string of a URL in order,
+// with their keys and values decoded
+func QueryParams(rawURL string) []KeyValue {
+	_, query, _ := splitQuery(rawURL)
+	var params []KeyValue
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		params = append(params, KeyValue{Key: unescapeQuery(key), Value: unescapeQuery(value)})
+	}
+	return params
+}
+
+// WithQuery returns the URL with its query string built from the enabled
+// params. Pairs already in the URL are kept as they were typed; others have
+// their keys and values encoded, and keys without a value get no '='.
+func WithQuery(rawURL string, params []KeyValue) string {
+	base, query, fragment := splitQuery(rawURL)
+
+	typed := make(map[queryPair][]string)
+	for _, pair := range strings.Split(query, "&") {
+		if pair == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(pair, "=")
+		decoded := queryPair{unescapeQuery(key), unescapeQuery(value)}
+		typed[decoded] = append(typed[decoded], pair)
+	}
+
+	var pairs []string
+	for _, p := range params {
+		if p.Disabled || (p.Key == "" && p.Value == "") {
+			continue
+		}
+		if raw := typed[queryPair{p.Key, p.Value}]; len(raw) > 0 {
+			pairs = append(pairs, raw[0])
+			typed[queryPair{p.Key, p.Value}] = raw[1:]
+			continue
+		}
+		pair := escapeText(p.Key, escapeQuery)
+		if p.Value != "" {
+			pair += "=" + escapeText(p.Value, escapeQuery)
+		}
+		pairs = append(pairs, pair)
+	}
+	if len(pairs) == 0 {
+		return base + fragment
+	}
+	return base + "?" + strings.Join(pairs, "&") + fragment
+}
+
+// queryPair is a decoded query key and value
+type queryPair struct {
+	key, value string
+}
+
+// MergeQuery returns params updated to the query string of a URL. Enabled
+// params take the keys and values of the URL's in order; each keeps its
+// description when the URL still has a param of its key, matched by
+// occurrence, or when it was edited in place. Disabled params stay where
+// they are.
+func MergeQuery(params []KeyValue, rawURL string) []KeyValue {
+	parsed := QueryParams(rawURL)
+
+	// Pair the nth enabled row of a key with the nth URL param of that key
+	rowsByKey := make(map[string][]int)
+	for i, p := range params {
+		if !p.Disabled {
+			rowsByKey[p.Key] = append(rowsByKey[p.Key], i)
+		}
+	}
+	paramOf := make(map[int]int) // row index to URL param index
+	matched := make([]bool, len(parsed))
+	for i, q := range parsed {
+		if rows := rowsByKey[q.Key]; len(rows) > 0 {
+			paramOf[rows[0]] = i
+			matched[i] = true
+			parsed[i].Description = params[rows[0]].Description
+			rowsByKey[q.Key] = rows[1:]
+		}
+	}
+
+	var merged []KeyValue
+	next := 0
+	for row, p := range params {
+		if p.Disabled {
+			merged = append(merged, p)
+			continue
+		}
+		i, ok := paramOf[row]
+		switch {
+		case ok && i >= next:
+			// Params new to the URL go before the row that follows them
+			merged = append(merged, parsed[next:i+1]...)
+			next = i + 1
+		case !ok && next < len(parsed) && !matched[next]:
+			// A row whose key was edited in the URL stays in its place
+			parsed[next].Description = p.Description
+			merged = append(merged, parsed[next])
+			next++
+		}
+	}
+	return append(merged, parsed[next:]...)
+}
+
+// pathParamPattern matches :name and {name} placeholders in a URL path
+var pathParamPattern = regexp.MustCompile(`/:([A-Za-z_][A-Za-z0-9_\-]*)|\{([A-Za-z_][A-Za-z0-9_.\-]*)\}`)
+
+// pathParam is a placeholder found in a URL path
+type pathParam struct {
+	name       string
+	start, end int // byte range of the placeholder in the URL
+}
+
+// pathParams finds the placeholders in the path of a URL. The host is left
+// out so ports aren't taken for placeholders, as are {{name}} variables.
+func pathParams(rawURL string) []pathParam {
+	base, _, _ := splitQuery(rawURL)
+	start := 0
+	if i := strings.Index(base, "://"); i >= 0 {
+		slash := strings.IndexByte(base[i+3:], '/')
+		if slash < 0 {
+			return nil
+		}
+		start = i + 3 + slash
+	}
+
+	var params []pathParam
+	for _, m := range pathParamPattern.FindAllStringSubmatchIndex(base[start:], -1) {
+		from, to := start+m[0], start+m[1]
+		if m[2] >= 0 {
+			// Keep the slash before :name
+			params = append(params, pathParam{name: base[start+m[2] : start+m[3]], start: from + 1, end: to})
+			continue
+		}
+		if (from > 0 && base[from-1] == '{') || (to < len(base) && base[to] == '}') {
+			continue
+		}
+		params = append(params, pathParam{name: base[start+m[4] : start+m[5]], start: from, end: to})
+	}
+	return params
+}
+
+// MergePath returns one param per placeholder in the path of a URL, in
+// order, keeping the values and descriptions params had for them
+func MergePath(params []KeyValue, rawURL string) []KeyValue {
+	var merged []KeyValue
+	seen := make(map[string]bool)
+	for _, p := range pathParams(rawURL) {
+		if seen[p.name] {
+			continue
+		}
+		seen[p.name] = true
+
+		kv := KeyValue{Key: p.name}
+		for _, existing := range params {
+			if existing.Key == p.name {
+				kv = existing
+				break
+			}
+		}
+		merged = append(merged, kv)
+	}
+	return merged
+}
+
+// ExpandPath returns the URL with the placeholders in its path replaced by
+// the encoded values of the enabled params, which should have their
+// {{variables}} substituted already. Placeholders without a value are left
+// as they are.
+func ExpandPath(rawURL string, params []KeyValue) string {
+	values := make(map[string]string)
+	for _, p := range params {
+		if !p.Disabled && p.Value != "" {
+			values[p.Key] = p.Value
+		}
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, p := range pathParams(rawURL) {
+		if value, ok := values[p.name]; ok {
+			b.WriteString(rawURL[last:p.start])
+			b.WriteString(url.PathEscape(value))
+			last = p.end
+		}
+	}
+	b.WriteString(rawURL[last:])
+	return b.String()
+}
+
+// escapeText percent-encodes s with escape, leaving {{name}} placeholders
+// as they are so they can still be substituted
+func escapeText(s string, escape func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range variablePattern.FindAllStringIndex(s, -1) {
+		b.WriteString(escape(s[last:m[0]]))
+		b.WriteString(s[m[0]:m[1]])
+		last = m[1]
+	}
+	b.WriteString(escape(s[last:]))
+	return b.String()
+}
+
+// escapeQuery percent-encodes a query key or value, leaving the characters
+// that are valid in a query unencoded and writing spaces as '+'
+func escapeQuery(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ':
+			b.WriteByte('+')
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			strings.IndexByte("-._~!$'()*,;:@/?", c) >= 0:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// unescapeQuery decodes a query key or value, keeping it as typed when it
+// isn't validly encoded
+func unescapeQuery(s string) string {
+	if decoded, err := url.QueryUnescape(s); err == nil {
+		return decoded
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		params []KeyValue
+		url    string
+		want   []KeyValue
+	}{
+		{
+			name:   "new params",
+			params: nil,
+			url:    "https://example.com/?a=1&b=2",
+			want:   []KeyValue{{Key: "a", Value: "1"}, {Key: "b", Value: "2"}},
+		},
+		{
+			name:   "values changed",
+			params: []KeyValue{{Key: "a", Value: "1", Description: "da"}},
+			url:    "https://example.com/?a=2",
+			want:   []KeyValue{{Key: "a", Value: "2", Description: "da"}},
+		},
+		{
+			name: "param removed",
+			params: []KeyValue{
+				{Key: "a", Value: "1", Description: "da"},
+				{Key: "x", Value: "0", Disabled: true},
+				{Key: "b", Value: "2", Description: "db"},
+			},
+			url: "https://example.com/?b=2",
+			want: []KeyValue{
+				{Key: "x", Value: "0", Disabled: true},
+				{Key: "b", Value: "2", Description: "db"},
+			},
+		},
+		{
+			name: "repeated keys matched by occurrence",
+			params: []KeyValue{
+				{Key: "id", Value: "1", Description: "first"},
+				{Key: "id", Value: "2", Description: "second"},
+			},
+			url: "https://example.com/?id=3&id=4&id=5",
+			want: []KeyValue{
+				{Key: "id", Value: "3", Description: "first"},
+				{Key: "id", Value: "4", Description: "second"},
+				{Key: "id", Value: "5"},
+			},
+		},
+		{
+			name: "key edited in place",
+			params: []KeyValue{
+				{Key: "a", Value: "1", Description: "da"},
+				{Key: "x", Value: "0", Disabled: true},
+				{Key: "b", Value: "2", Description: "db"},
+			},
+			url: "https://example.com/?ab=1&b=2",
+			want: []KeyValue{
+				{Key: "ab", Value: "1", Description: "da"},
+				{Key: "x", Value: "0", Disabled: true},
+				{Key: "b", Value: "2", Description: "db"},
+			},
+		},
+		{
+			name: "param added before an existing one",
+			params: []KeyValue{
+				{Key: "x", Value: "0", Disabled: true},
+				{Key: "b", Value: "2", Description: "db"},
+			},
+			url: "https://example.com/?a=1&b=2",
+			want: []KeyValue{
+				{Key: "x", Value: "0", Disabled: true},
+				{Key: "a", Value: "1"},
+				{Key: "b", Value: "2", Description: "db"},
+			},
+		},
+		{
+			name:   "query cleared",
+			params: []KeyValue{{Key: "a", Value: "1"}, {Key: "x", Disabled: true}},
+			url:    "https://example.com/",
+			want:   []KeyValue{{Key: "x", Disabled: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MergeQuery(tt.params, tt.url); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeQuery() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		params []KeyValue
+		want   string
+	}{
+		{
+			name:   "valueless key kept",
+			url:    "https://example.com/?c&d=",
+			params: []KeyValue{{Key: "c"}, {Key: "d"}},
+			want:   "https://example.com/?c&d=",
+		},
+		{
+			name:   "typed encoding kept",
+			url:    "https://example.com/?ids=1,2&q=a%20b&r=a+b",
+			params: []KeyValue{{Key: "ids", Value: "1,2"}, {Key: "q", Value: "a b"}, {Key: "r", Value: "a b"}},
+			want:   "https://example.com/?ids=1,2&q=a%20b&r=a+b",
+		},
+		{
+			name:   "new params encoded",
+			url:    "https://example.com/path#top",
+			params: []KeyValue{{Key: "ids", Value: "1,2"}, {Key: "q", Value: "a b&c=d"}, {Key: "flag"}},
+			want:   "https://example.com/path?ids=1,2&q=a+b%26c%3Dd&flag#top",
+		},
+		{
+			name:   "disabled and empty rows left out",
+			url:    "https://example.com/?a=1",
+			params: []KeyValue{{Key: "a", Value: "1", Disabled: true}, {}, {Key: "b", Value: "{{b}}"}},
+			want:   "https://example.com/?b={{b}}",
+		},
+		{
+			name:   "edited value",
+			url:    "https://example.com/?a=1&a=1",
+			params: []KeyValue{{Key: "a", Value: "1"}, {Key: "a", Value: "2"}},
+			want:   "https://example.com/?a=1&a=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithQuery(tt.url, tt.params); got != tt.want {
+				t.Errorf("WithQuery(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolvePathParams(t *testing.T) {
+	vars := map[string]string{"host": "api.example.com", "uid": "a/b", "org": "acme"}
+	tests := []struct {
+		name string
+		url  string
+		path []KeyValue
+		want string
+	}{
+		{"typed value", "https://{{host}}/u/:id", []KeyValue{{Key: "id", Value: "a/b"}}, "https://api.example.com/u/a%2Fb"},
+		{"variable value", "https://{{host}}/u/:id", []KeyValue{{Key: "id", Value: "{{uid}}"}}, "https://api.example.com/u/a%2Fb"},
+		{"variable in part of a value", "https://{{host}}/orgs/{org}", []KeyValue{{Key: "org", Value: "{{org}} inc"}}, "https://api.example.com/orgs/acme%20inc"},
+		{"disabled value", "https://{{host}}/u/:id", []KeyValue{{Key: "id", Value: "{{missing}}", Disabled: true}}, "https://api.example.com/u/:id"},
+		{"no value", "https://{{host}}/u/{id}", []KeyValue{{Key: "id"}}, "https://api.example.com/u/{id}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &Request{Method: "GET", URL: tt.url, Params: Params{Path: tt.path}}
+			resolved, err := req.Resolve(vars)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resolved.URL != tt.want {
+				t.Errorf("URL = %q, want %q", resolved.URL, tt.want)
+			}
+			if req.URL != tt.url || !reflect.DeepEqual(req.Params.Path, tt.path) {
+				t.Errorf("Resolve changed the request to %q %+v", req.URL, req.Params.Path)
+			}
+		})
+	}
+
+	req := &Request{URL: "https://example.com/u/:id", Params: Params{Path: []KeyValue{{Key: "id", Value: "{{missing}}"}}}}
+	if _, err := req.Resolve(vars); err == nil {
+		t.Error("Resolve() with an undefined variable in a path value succeeded")
+	}
+}
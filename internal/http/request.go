@@ -2,34 +2,81 @@ package http
 
 // Request represents an HTTP request to be executed
 type Request struct {
-	ID      int64             `json:"id"`
-	Name    string            `json:"name"`
-	Method  string            `json:"method"`
-	URL     string            `json:"url"`
-	Headers map[string]string `json:"headers"`
-	Body    string            `json:"body"`
+	ID       int64    `json:"id"`
+	Name     string   `json:"name"`
+	Method   string   `json:"method"`
+	URL      string   `json:"url"`
+	Headers  Headers  `json:"headers"`
+	Params   Params   `json:"params"`
+	Body     string   `json:"body"`
+	BodyType BodyType `json:"bodyType,omitempty"`
+
+	// Form holds the fields of url-encoded and multipart bodies
+	Form []KeyValue `json:"form,omitempty"`
+
+	// BodyFile is the path of the file sent as a binary body
+	BodyFile string `json:"bodyFile,omitempty"`
+
+	// GraphQL is the operation sent as a GraphQL body
+	GraphQL GraphQL `json:"graphql"`
+
+	Auth     Auth     `json:"auth"`
+	Settings Settings `json:"settings"`
+
+	// Filter is the jq or JSONPath filter last applied to the response
+	Filter string `json:"-"`
+
+	// Environment is the name of the environment the request was resolved
+	// against; OAuth 2.0 tokens are cached per environment
+	Environment string `json:"-"`
+}
+
+// Settings overrides the client's network settings for a single request.
+// Zero values inherit the client defaults.
+type Settings struct {
+	Timeout   int    `json:"timeout,omitempty"` // seconds
+	SSLVerify *bool  `json:"sslVerify,omitempty"`
+	Proxy     string `json:"proxy,omitempty"` // proxy URL or ProxyDirect
+}
+
+// IsZero returns true if no setting is overridden
+func (s Settings) IsZero() bool {
+	return s.Timeout == 0 && s.SSLVerify == nil && s.Proxy == ""
+}
+
+// Clone creates a deep copy of the settings
+func (s Settings) Clone() Settings {
+	if s.SSLVerify != nil {
+		verify := *s.SSLVerify
+		s.SSLVerify = &verify
+	}
+	return s
 }
 
 // NewRequest creates a new Request with default values
 func NewRequest() *Request {
 	return &Request{
-		Method:  "GET",
-		Headers: make(map[string]string),
+		Method: "GET",
 	}
 }
 
 // Clone creates a deep copy of the request
 func (r *Request) Clone() *Request {
-	headers := make(map[string]string)
-	for k, v := range r.Headers {
-		headers[k] = v
-	}
 	return &Request{
-		ID:      r.ID,
-		Name:    r.Name,
-		Method:  r.Method,
-		URL:     r.URL,
-		Headers: headers,
-		Body:    r.Body,
+		ID:       r.ID,
+		Name:     r.Name,
+		Method:   r.Method,
+		URL:      r.URL,
+		Headers:  r.Headers.Clone(),
+		Params:   r.Params.Clone(),
+		Body:     r.Body,
+		BodyType: r.BodyType,
+		Form:     append([]KeyValue(nil), r.Form...),
+		BodyFile: r.BodyFile,
+		GraphQL:  r.GraphQL,
+		Auth:     r.Auth,
+		Settings: r.Settings.Clone(),
+
+		Environment: r.Environment,
 	}
 }
@@ -1,7 +1,9 @@
 package http
 
 import (
+	"io"
 	"net/http"
+	"os"
 	"time"
 )
 
@@ -9,10 +11,13 @@ import (
 type Response struct {
 	StatusCode int
 	Status     string
+	Proto      string // e.g. "HTTP/1.1", empty for responses loaded from history
 	Headers    http.Header
 	Body       []byte
+	BodyFile   string // file the body was streamed to when too large to keep in memory
 	Duration   time.Duration
 	Size       int64
+	Timing     Timing
 	Error      error
 }
 
@@ -26,7 +31,42 @@ func (r *Response) IsError() bool {
 	return r.Error != nil
 }
 
+// Cookies parses the Set-Cookie headers of the response
+func (r *Response) Cookies() []*http.Cookie {
+	return (&http.Response{Header: r.Headers}).Cookies()
+}
+
 // BodyString returns the response body as a string
 func (r *Response) BodyString() string {
 	return string(r.Body)
 }
+
+// SaveBody writes the raw response body to a file
+func (r *Response) SaveBody(path string) error {
+	if r.BodyFile == "" {
+		return os.WriteFile(path, r.Body, 0644)
+	}
+
+	src, err := os.Open(r.BodyFile)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	// Saving over the streamed file would truncate it before it is copied
+	if srcInfo, err := src.Stat(); err == nil {
+		if dstInfo, err := os.Stat(path); err == nil && os.SameFile(srcInfo, dstInfo) {
+			return nil
+		}
+	}
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing breaks down where the time of a request went. Phases that didn't
+// happen, such as DNS, connecting and TLS on a reused connection, are zero.
+type Timing struct {
+	DNS       time.Duration `json:"dns"`
+	Connect   time.Duration `json:"connect"`
+	TLS       time.Duration `json:"tls"`
+	FirstByte time.Duration `json:"first_byte"` // from sending the request to the first response byte
+	Transfer  time.Duration `json:"transfer"`   // reading the response body
+	Reused    bool          `json:"reused"`     // the connection was kept alive from an earlier request
+}
+
+// IsZero reports whether no timing was recorded, e.g. for requests that
+// failed before being sent
+func (t Timing) IsZero() bool {
+	return t == Timing{}
+}
+
+// timingTrace records the phases of the latest round trip of a request, so
+// retries after an auth challenge report their own timing
+type timingTrace struct {
+	mu           sync.Mutex
+	timing       Timing
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+}
+
+// clientTrace returns the httptrace hooks filling in the timing. Hooks may
+// run concurrently, e.g. when dialing IPv4 and IPv6 addresses in parallel.
+func (t *timingTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timing = Timing{}
+			t.firstByte = time.Time{}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timing.Reused = info.Reused
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timing.DNS = time.Since(t.dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if t.timing.Connect == 0 {
+				t.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(network, addr string, err error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			if err == nil && t.timing.Connect == 0 {
+				t.timing.Connect = time.Since(t.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.timing.TLS = time.Since(t.tlsStart)
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.wroteRequest = time.Now()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.firstByte = time.Now()
+			t.timing.FirstByte = t.firstByte.Sub(t.wroteRequest)
+		},
+	}
+}
+
+// result returns the timing so far, counting the body transfer from the
+// first response byte until now
+func (t *timingTrace) result() Timing {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	timing := t.timing
+	if !t.firstByte.IsZero() {
+		timing.Transfer = time.Since(t.firstByte)
+	}
+	return timing
+}
@@ -0,0 +1,171 @@
+package http
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ProxyDirect is the proxy value that bypasses any configured or environment proxy
+const ProxyDirect = "direct"
+
+// TransportConfig holds the network settings used to build a transport
+type TransportConfig struct {
+	Timeout   time.Duration // zero means no timeout
+	SSLVerify bool
+	Proxy     string   // http://, https:// or socks5:// URL, optionally with user:pass@
+	NoProxy   []string // hosts, domains, IPs or CIDRs that bypass the proxy
+}
+
+// DefaultTransportConfig returns the settings used when no config is provided
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		Timeout:   30 * time.Second,
+		SSLVerify: true,
+	}
+}
+
+// WithSettings returns a copy of the config with per-request overrides applied
+func (c TransportConfig) WithSettings(s Settings) TransportConfig {
+	if s.Timeout > 0 {
+		c.Timeout = time.Duration(s.Timeout) * time.Second
+	}
+	if s.SSLVerify != nil {
+		c.SSLVerify = *s.SSLVerify
+	}
+	if s.Proxy != "" {
+		c.Proxy = s.Proxy
+	}
+	return c
+}
+
+// key returns a string identifying the config, used to cache clients
+func (c TransportConfig) key() string {
+	return fmt.Sprintf("%s|%t|%s|%s", c.Timeout, c.SSLVerify, c.Proxy, strings.Join(c.NoProxy, ","))
+}
+
+// NewTransport builds an http.Transport honoring TLS verification, proxy and
+// NO_PROXY settings. An empty proxy falls back to the HTTP_PROXY/HTTPS_PROXY
+// environment variables, ProxyDirect disables proxying entirely.
+func NewTransport(cfg TransportConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		InsecureSkipVerify: !cfg.SSLVerify,
+	}
+
+	switch cfg.Proxy {
+	case ProxyDirect:
+		transport.Proxy = nil
+	case "":
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if bypassProxy(req.URL, cfg.NoProxy) {
+				return nil, nil
+			}
+			return http.ProxyFromEnvironment(req)
+		}
+	default:
+		proxyURL, err := ParseProxyURL(cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			if bypassProxy(req.URL, cfg.NoProxy) {
+				return nil, nil
+			}
+			return proxyURL, nil
+		}
+	}
+
+	return transport, nil
+}
+
+// ParseProxyURL parses a proxy address. Addresses without a scheme are treated as HTTP proxies.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: missing host")
+	}
+	return proxyURL, nil
+}
+
+// ParseNoProxy splits a comma-separated NO_PROXY list
+func ParseNoProxy(input string) []string {
+	var entries []string
+	for _, entry := range strings.Split(input, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// bypassProxy reports whether the target matches one of the NO_PROXY entries.
+// Entries may be "*", a host or domain (matching subdomains too), an IP, a
+// CIDR range, or any of these with a ":port" suffix.
+func bypassProxy(target *url.URL, noProxy []string) bool {
+	host := strings.ToLower(target.Hostname())
+	port := target.Port()
+	if port == "" {
+		switch target.Scheme {
+		case "https":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		if entry == "*" {
+			return true
+		}
+
+		// Split off an optional port
+		entryHost, entryPort := entry, ""
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			entryHost, entryPort = h, p
+		}
+		if entryPort != "" && entryPort != port {
+			continue
+		}
+
+		if _, cidr, err := net.ParseCIDR(entryHost); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		if entryIP := net.ParseIP(entryHost); entryIP != nil {
+			if ip != nil && entryIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		domain := strings.TrimPrefix(entryHost, "*")
+		domain = strings.TrimPrefix(domain, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// variablePattern matches {{name}} placeholders, allowing whitespace inside the braces
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-]+)\s*\}\}`)
+
+// UndefinedVariablesError is returned when a request references variables
+// that are not defined in the active environment
+type UndefinedVariablesError struct {
+	Names []string
+}
+
+func (e *UndefinedVariablesError) Error() string {
+	return fmt.Sprintf("undefined variables: %s", strings.Join(e.Names, ", "))
+}
+
+// Resolve returns a copy of the request with {{name}} placeholders in the
+// URL, path params, headers, body and auth credentials replaced by values
+// from vars, and the path params filled into the URL. Of the body, only the part sent
+// for its type is resolved: the text, the form fields, the file path or the
+// GraphQL variables. The original request is left untouched so the
+// unresolved template can still be saved.
+func (r *Request) Resolve(vars map[string]string) (*Request, error) {
+	resolver := &variableResolver{vars: vars, seen: make(map[string]bool)}
+
+	resolved := r.Clone()
+	// Path values are resolved before they're encoded into the URL, so a
+	// variable holding a '/' is encoded like a typed one
+	for i, p := range resolved.Params.Path {
+		if !p.Disabled {
+			resolved.Params.Path[i].Value = resolver.replace(p.Value)
+		}
+	}
+	resolved.URL = ExpandPath(resolver.replace(r.URL), resolved.Params.Path)
+
+	// Disabled headers aren't sent, so their variables needn't be defined
+	for i, h := range resolved.Headers {
+		if !h.Disabled {
+			resolved.Headers[i].Key = resolver.replace(h.Key)
+			resolved.Headers[i].Value = resolver.replace(h.Value)
+		}
+	}
+
+	// Only the part of the body sent needs its variables defined
+	switch r.BodyType {
+	case BodyNone:
+	case BodyURLEncoded, BodyMultipart:
+		for i, f := range resolved.Form {
+			if !f.Disabled {
+				resolved.Form[i].Key = resolver.replace(f.Key)
+				resolved.Form[i].Value = resolver.replace(f.Value)
+			}
+		}
+	case BodyBinary:
+		resolved.BodyFile = resolver.replace(r.BodyFile)
+	case BodyGraphQL:
+		// GraphQL has its own $variables, so the query is sent as written
+		resolved.GraphQL.Variables = resolver.replace(r.GraphQL.Variables)
+	default:
+		resolved.Body = resolver.replace(r.Body)
+	}
+
+	resolved.Auth.Username = resolver.replace(r.Auth.Username)
+	resolved.Auth.Password = resolver.replace(r.Auth.Password)
+	resolved.Auth.Token = resolver.replace(r.Auth.Token)
+	resolved.Auth.Key = resolver.replace(r.Auth.Key)
+	resolved.Auth.Value = resolver.replace(r.Auth.Value)
+	resolved.Auth.TokenURL = resolver.replace(r.Auth.TokenURL)
+	resolved.Auth.AuthURL = resolver.replace(r.Auth.AuthURL)
+	resolved.Auth.RedirectURL = resolver.replace(r.Auth.RedirectURL)
+	resolved.Auth.ClientID = resolver.replace(r.Auth.ClientID)
+	resolved.Auth.ClientSecret = resolver.replace(r.Auth.ClientSecret)
+	resolved.Auth.Scope = resolver.replace(r.Auth.Scope)
+
+	if len(resolver.missing) > 0 {
+		return nil, &UndefinedVariablesError{Names: resolver.missing}
+	}
+	return resolved, nil
+}
+
+// variableResolver substitutes placeholders and records undefined names
+type variableResolver struct {
+	vars    map[string]string
+	seen    map[string]bool
+	missing []string
+}
+
+func (vr *variableResolver) replace(s string) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if value, ok := vr.vars[name]; ok {
+			return value
+		}
+		if !vr.seen[name] {
+			vr.seen[name] = true
+			vr.missing = append(vr.missing, name)
+		}
+		return match
+	})
+}
@@ -0,0 +1,599 @@
+// Package openapi generates TRexT collections from OpenAPI 3 specifications.
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// BaseURLVariable is the collection variable holding the server URL
+const BaseURLVariable = "baseUrl"
+
+// maxSchemaDepth limits how deep example bodies are generated from schemas
+// and how many $refs are followed in a row
+const maxSchemaDepth = 8
+
+// operationMethods lists the operations of a path item in display order
+var operationMethods = []string{"get", "post", "put", "patch", "delete", "head", "options"}
+
+// pathTemplate matches {param} placeholders in paths and server URLs
+var pathTemplate = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Imported is a collection generated from a spec. Each tag becomes a folder;
+// folder IDs are provisional and only link requests to their folder.
+type Imported struct {
+	Collection *storage.Collection
+	Folders    []*storage.Folder
+	Requests   []*storage.SavedRequest
+}
+
+// spec wraps the decoded document; it is kept generic so $refs can be
+// resolved anywhere in it
+type spec struct {
+	root map[string]any
+}
+
+// IsSpec reports whether data is an OpenAPI 3 document in JSON or YAML
+func IsSpec(data []byte) bool {
+	var probe struct {
+		OpenAPI string `yaml:"openapi"`
+	}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return strings.HasPrefix(probe.OpenAPI, "3.")
+}
+
+// Import generates a collection with one request per operation. The first
+// server becomes the baseUrl variable and path parameters become variables
+// holding their example values. Credentials are left as {{variables}} to be
+// defined in an environment.
+func Import(data []byte) (*Imported, error) {
+	var root map[string]any
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	version, _ := root["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, errors.New("only OpenAPI 3.x documents are supported")
+	}
+	s := &spec{root: root}
+
+	info := s.object(root["info"])
+	name, _ := info["title"].(string)
+	if name == "" {
+		name = "OpenAPI"
+	}
+	desc, _ := info["description"].(string)
+
+	imported := &Imported{
+		Collection: &storage.Collection{
+			Name:        name,
+			Description: desc,
+			Variables:   map[string]string{BaseURLVariable: s.serverURL(root["servers"])},
+		},
+	}
+
+	folders := make(map[string]int64)
+	paths := s.object(root["paths"])
+	for _, path := range sortedKeys(paths) {
+		item := s.object(paths[path])
+		for _, method := range operationMethods {
+			op := s.object(item[method])
+			if op == nil {
+				continue
+			}
+			req := s.request(path, method, item, op, imported.Collection.Variables)
+			saved := storage.FromHTTPRequest(req, 0)
+			if tag := operationTag(op); tag != "" {
+				if _, ok := folders[tag]; !ok {
+					folders[tag] = int64(len(imported.Folders) + 1)
+					imported.Folders = append(imported.Folders, &storage.Folder{ID: folders[tag], Name: tag})
+				}
+				saved.FolderID = folders[tag]
+			}
+			imported.Requests = append(imported.Requests, saved)
+		}
+	}
+	if len(imported.Requests) == 0 {
+		return nil, errors.New("the OpenAPI document has no operations")
+	}
+	return imported, nil
+}
+
+// serverURL returns the first server URL with its variables set to their defaults
+func (s *spec) serverURL(servers any) string {
+	list, _ := servers.([]any)
+	if len(list) == 0 {
+		return ""
+	}
+	server := s.object(list[0])
+	serverURL, _ := server["url"].(string)
+	variables := s.object(server["variables"])
+	serverURL = pathTemplate.ReplaceAllStringFunc(serverURL, func(match string) string {
+		variable := s.object(variables[match[1:len(match)-1]])
+		if value, ok := variable["default"]; ok {
+			return scalarString(value)
+		}
+		return match
+	})
+	return strings.TrimSuffix(serverURL, "/")
+}
+
+// request builds the request for one operation
+func (s *spec) request(path, method string, item, op map[string]any, variables map[string]string) *http.Request {
+	req := http.NewRequest()
+	req.Method = strings.ToUpper(method)
+	req.Name = operationName(path, method, op)
+
+	// Path params become {{variables}}; the rest are filled with examples
+	var query []string
+	for _, param := range s.parameters(item, op) {
+		name, _ := param["name"].(string)
+		in, _ := param["in"].(string)
+		required, _ := param["required"].(bool)
+		value := s.parameterExample(param)
+
+		switch in {
+		case "path":
+			if _, ok := variables[name]; !ok || variables[name] == "" {
+				variables[name] = value
+			}
+		case "query":
+			if required {
+				query = append(query, url.QueryEscape(name)+"="+url.QueryEscape(value))
+			}
+		case "header":
+			if required {
+				req.Headers.Set(name, value)
+			}
+		}
+	}
+
+	req.URL = "{{" + BaseURLVariable + "}}" + pathTemplate.ReplaceAllString(path, "{{$1}}")
+	if len(query) > 0 {
+		req.URL += "?" + strings.Join(query, "&")
+	}
+
+	if body := s.object(op["requestBody"]); body != nil {
+		s.setBody(req, body)
+	}
+	req.Auth = s.auth(op)
+	return req
+}
+
+// operationName names a request after its summary or operationId
+func operationName(path, method string, op map[string]any) string {
+	name, _ := op["summary"].(string)
+	if name == "" {
+		name, _ = op["operationId"].(string)
+	}
+	if name == "" {
+		name = strings.ToUpper(method) + " " + path
+	}
+	return name
+}
+
+// operationTag returns the first tag of an operation, which names its folder
+func operationTag(op map[string]any) string {
+	if tags, _ := op["tags"].([]any); len(tags) > 0 {
+		tag, _ := tags[0].(string)
+		return tag
+	}
+	return ""
+}
+
+// parameters merges path-level and operation-level parameters; the
+// operation's win when both define the same name and location
+func (s *spec) parameters(item, op map[string]any) []map[string]any {
+	var params []map[string]any
+	index := make(map[string]int)
+	for _, source := range []any{item["parameters"], op["parameters"]} {
+		list, _ := source.([]any)
+		for _, p := range list {
+			param := s.object(p)
+			if param == nil {
+				continue
+			}
+			key := fmt.Sprint(param["in"], ":", param["name"])
+			if i, ok := index[key]; ok {
+				params[i] = param
+				continue
+			}
+			index[key] = len(params)
+			params = append(params, param)
+		}
+	}
+	return params
+}
+
+// parameterExample returns the example value of a parameter as text
+func (s *spec) parameterExample(param map[string]any) string {
+	if example, ok := param["example"]; ok {
+		return scalarString(example)
+	}
+	if example, ok := s.firstExample(param["examples"]); ok {
+		return scalarString(example)
+	}
+	if schema := s.object(param["schema"]); schema != nil {
+		return scalarString(s.example(param["schema"], nil, 0))
+	}
+	return ""
+}
+
+// bodyMediaTypes lists the preferred request body media types
+var bodyMediaTypes = []string{"application/json", "application/x-www-form-urlencoded", "multipart/form-data", "text/plain", "application/xml"}
+
+// setBody fills in the request body from the operation's examples or schema
+func (s *spec) setBody(req *http.Request, requestBody map[string]any) {
+	content := s.object(requestBody["content"])
+	if len(content) == 0 {
+		return
+	}
+
+	mediaType := ""
+	for _, preferred := range bodyMediaTypes {
+		if _, ok := content[preferred]; ok {
+			mediaType = preferred
+			break
+		}
+	}
+	if mediaType == "" {
+		// Any JSON flavour, e.g. application/vnd.api+json
+		for _, candidate := range sortedKeys(content) {
+			if strings.HasSuffix(candidate, "json") {
+				mediaType = candidate
+				break
+			}
+		}
+	}
+	if mediaType == "" {
+		mediaType = sortedKeys(content)[0]
+	}
+
+	// Media types with a body type of their own get their Content-Type from it
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		req.BodyType = http.BodyURLEncoded
+	case mediaType == "multipart/form-data":
+		req.BodyType = http.BodyMultipart
+	case mediaType == "application/json":
+		req.BodyType = http.BodyJSON
+	case mediaType == "application/xml":
+		req.BodyType = http.BodyXML
+	case mediaType == "text/plain":
+		req.BodyType = http.BodyText
+	case strings.HasSuffix(mediaType, "json"):
+		req.Headers.Set("Content-Type", mediaType)
+	default:
+		req.BodyType = http.BodyText
+		req.Headers.Set("Content-Type", mediaType)
+	}
+
+	media := s.object(content[mediaType])
+	value, ok := media["example"]
+	if !ok {
+		value, ok = s.firstExample(media["examples"])
+	}
+	if !ok {
+		if s.object(media["schema"]) == nil {
+			return
+		}
+		value = s.example(media["schema"], nil, 0)
+	}
+
+	switch {
+	case req.BodyType == http.BodyURLEncoded || req.BodyType == http.BodyMultipart:
+		// Binary properties of multipart bodies are files to pick
+		properties := s.object(s.object(media["schema"])["properties"])
+		fields, _ := value.(map[string]any)
+		for _, key := range sortedKeys(fields) {
+			field := http.KeyValue{Key: key, Value: scalarString(fields[key])}
+			if format, _ := s.object(properties[key])["format"].(string); format == "binary" && req.BodyType == http.BodyMultipart {
+				field.Value, field.File = "", true
+			}
+			req.Form = append(req.Form, field)
+		}
+	case strings.HasSuffix(mediaType, "json"):
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err == nil {
+			req.Body = string(data)
+		}
+	default:
+		if text, ok := value.(string); ok {
+			req.Body = text
+		}
+	}
+}
+
+// firstExample returns the value of the first entry of an examples map
+func (s *spec) firstExample(examples any) (any, bool) {
+	m := s.object(examples)
+	for _, name := range sortedKeys(m) {
+		if example := s.object(m[name]); example != nil {
+			if value, ok := example["value"]; ok {
+				return value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// example builds an example value from a schema. Schemas referring back to
+// themselves are cut off when a $ref already being expanded comes up again.
+func (s *spec) example(value any, seen map[string]bool, depth int) any {
+	if ref, ok := refOf(value); ok {
+		if seen[ref] {
+			return nil
+		}
+		if seen == nil {
+			seen = make(map[string]bool)
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+	}
+	schema := s.object(value)
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+	for _, key := range []string{"example", "default", "const"} {
+		if value, ok := schema[key]; ok {
+			return value
+		}
+	}
+	if examples, _ := schema["examples"].([]any); len(examples) > 0 {
+		return examples[0]
+	}
+	if enum, _ := schema["enum"].([]any); len(enum) > 0 {
+		return enum[0]
+	}
+
+	// Compositions: merge allOf, take the first alternative of oneOf/anyOf
+	if allOf, _ := schema["allOf"].([]any); len(allOf) > 0 {
+		merged := make(map[string]any)
+		for _, part := range allOf {
+			value := s.example(part, seen, depth+1)
+			if fields, ok := value.(map[string]any); ok {
+				for key, field := range fields {
+					merged[key] = field
+				}
+			} else if value != nil {
+				return value
+			}
+		}
+		return merged
+	}
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives, _ := schema[key].([]any); len(alternatives) > 0 {
+			return s.example(alternatives[0], seen, depth+1)
+		}
+	}
+
+	schemaType := schema["type"]
+	if types, ok := schemaType.([]any); ok && len(types) > 0 {
+		// OpenAPI 3.1 allows a list of types, e.g. ["string", "null"]
+		schemaType = types[0]
+	}
+	switch schemaType {
+	case "object":
+		return s.objectExample(schema, seen, depth)
+	case "array":
+		item := s.example(schema["items"], seen, depth+1)
+		if item == nil {
+			return []any{}
+		}
+		return []any{item}
+	case "string":
+		return stringExample(schema)
+	case "integer":
+		return 0
+	case "number":
+		return 0.0
+	case "boolean":
+		return true
+	}
+	if _, ok := schema["properties"]; ok {
+		return s.objectExample(schema, seen, depth)
+	}
+	return nil
+}
+
+// objectExample builds an example object from the schema's properties,
+// leaving out read-only and recursive ones
+func (s *spec) objectExample(schema map[string]any, seen map[string]bool, depth int) any {
+	result := make(map[string]any)
+	properties := s.object(schema["properties"])
+	for _, name := range sortedKeys(properties) {
+		if readOnly, _ := s.object(properties[name])["readOnly"].(bool); readOnly {
+			continue
+		}
+		if value := s.example(properties[name], seen, depth+1); value != nil {
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// stringExample returns a placeholder string matching the schema's format
+func stringExample(schema map[string]any) string {
+	format, _ := schema["format"].(string)
+	switch format {
+	case "date":
+		return "2024-01-01"
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "ipv4":
+		return "127.0.0.1"
+	case "ipv6":
+		return "::1"
+	case "byte":
+		return "c3RyaW5n"
+	}
+	return "string"
+}
+
+// auth converts the operation's security requirement, falling back to the
+// document's. Only the first scheme of the first requirement is used.
+func (s *spec) auth(op map[string]any) http.Auth {
+	security, ok := op["security"].([]any)
+	if !ok {
+		security, _ = s.root["security"].([]any)
+	}
+	if len(security) == 0 {
+		return http.Auth{}
+	}
+	requirement := s.object(security[0])
+	names := sortedKeys(requirement)
+	if len(names) == 0 {
+		return http.Auth{}
+	}
+
+	schemes := s.object(s.object(s.root["components"])["securitySchemes"])
+	scheme := s.object(schemes[names[0]])
+	schemeType, _ := scheme["type"].(string)
+	switch schemeType {
+	case "http":
+		switch strings.ToLower(fmt.Sprint(scheme["scheme"])) {
+		case "basic":
+			return http.Auth{Type: http.AuthBasic, Username: "{{username}}", Password: "{{password}}"}
+		case "digest":
+			return http.Auth{Type: http.AuthDigest, Username: "{{username}}", Password: "{{password}}"}
+		case "bearer":
+			return http.Auth{Type: http.AuthBearer, Token: "{{token}}"}
+		}
+	case "apiKey":
+		name, _ := scheme["name"].(string)
+		switch scheme["in"] {
+		case "header":
+			return http.Auth{Type: http.AuthAPIKey, Key: name, Value: "{{apiKey}}", In: http.APIKeyInHeader}
+		case "query":
+			return http.Auth{Type: http.AuthAPIKey, Key: name, Value: "{{apiKey}}", In: http.APIKeyInQuery}
+		}
+	case "oauth2":
+		return s.oauth2Auth(scheme, requirement[names[0]])
+	}
+	return http.Auth{}
+}
+
+// oauth2Auth converts an OAuth 2.0 scheme, preferring flows that need no browser
+func (s *spec) oauth2Auth(scheme map[string]any, scopes any) http.Auth {
+	var scope []string
+	if list, ok := scopes.([]any); ok {
+		for _, item := range list {
+			scope = append(scope, scalarString(item))
+		}
+	}
+
+	flows := s.object(scheme["flows"])
+	auth := http.Auth{
+		Type:         http.AuthOAuth2,
+		ClientID:     "{{clientId}}",
+		ClientSecret: "{{clientSecret}}",
+		Scope:        strings.Join(scope, " "),
+	}
+	switch {
+	case flows["clientCredentials"] != nil:
+		flow := s.object(flows["clientCredentials"])
+		auth.GrantType = http.GrantClientCredentials
+		auth.TokenURL, _ = flow["tokenUrl"].(string)
+	case flows["password"] != nil:
+		flow := s.object(flows["password"])
+		auth.GrantType = http.GrantPassword
+		auth.TokenURL, _ = flow["tokenUrl"].(string)
+		auth.Username = "{{username}}"
+		auth.Password = "{{password}}"
+	case flows["authorizationCode"] != nil:
+		flow := s.object(flows["authorizationCode"])
+		auth.GrantType = http.GrantAuthorizationCode
+		auth.TokenURL, _ = flow["tokenUrl"].(string)
+		auth.AuthURL, _ = flow["authorizationUrl"].(string)
+	default:
+		return http.Auth{}
+	}
+	return auth
+}
+
+// object returns value as a map, following a local $ref if present
+func (s *spec) object(value any) map[string]any {
+	for range maxSchemaDepth {
+		ref, ok := refOf(value)
+		if !ok {
+			m, _ := value.(map[string]any)
+			return m
+		}
+		value = s.resolve(ref)
+	}
+	return nil
+}
+
+// refOf returns the $ref of a reference object
+func refOf(value any) (string, bool) {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return "", false
+	}
+	ref, ok := m["$ref"].(string)
+	return ref, ok
+}
+
+// resolve looks up a local reference such as #/components/schemas/User
+func (s *spec) resolve(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		// References to other files aren't supported
+		return nil
+	}
+	var current any = s.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil
+		}
+		current = m[token]
+	}
+	return current
+}
+
+// scalarString formats a scalar example as text
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(data)
+	}
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
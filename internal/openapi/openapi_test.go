@@ -0,0 +1,212 @@
+package openapi
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/postman"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+)
+
+func importFixture(t *testing.T) *Imported {
+	t.Helper()
+	data, err := os.ReadFile("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSpec(data) {
+		t.Fatal("IsSpec = false for an OpenAPI document")
+	}
+	imported, err := Import(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return imported
+}
+
+func TestImport(t *testing.T) {
+	imported := importFixture(t)
+
+	wantCollection := &storage.Collection{
+		Name:        "Petstore",
+		Description: "A small pet store",
+		Variables: map[string]string{
+			BaseURLVariable: "https://eu.petstore.example.com/v2",
+			"petId":         "00000000-0000-0000-0000-000000000000",
+		},
+	}
+	if !reflect.DeepEqual(imported.Collection, wantCollection) {
+		t.Errorf("collection = %+v, want %+v", imported.Collection, wantCollection)
+	}
+
+	wantFolders := []*storage.Folder{{ID: 1, Name: "pets"}, {ID: 2, Name: "photos"}}
+	if !reflect.DeepEqual(imported.Folders, wantFolders) {
+		t.Errorf("folders = %+v, want %+v", imported.Folders, wantFolders)
+	}
+
+	bearer := http.Auth{Type: http.AuthBearer, Token: "{{token}}"}
+	wantRequests := []*http.Request{
+		{
+			Name:     "Log in",
+			Method:   "POST",
+			URL:      "{{baseUrl}}/login",
+			BodyType: http.BodyURLEncoded,
+			Form:     []http.KeyValue{{Key: "remember", Value: "true"}, {Key: "username", Value: "user@example.com"}},
+		},
+		{
+			Name:    "List pets",
+			Method:  "GET",
+			URL:     "{{baseUrl}}/pets?limit=10",
+			Headers: http.Headers{{Key: "X-Trace-Id", Value: "trace-1"}},
+			Auth:    bearer,
+		},
+		{
+			// Read-only and recursive properties are left out
+			Name:   "createPet",
+			Method: "POST",
+			URL:    "{{baseUrl}}/pets",
+			Body: `{
+  "born": "2024-01-01",
+  "friends": [],
+  "name": "Rex",
+  "owner": {
+    "email": "user@example.com",
+    "vip": true
+  }
+}`,
+			Auth: bearer,
+		},
+		{
+			Name:   "Get a pet",
+			Method: "GET",
+			URL:    "{{baseUrl}}/pets/{{petId}}",
+			Auth:   http.Auth{Type: http.AuthAPIKey, Key: "api_key", Value: "{{apiKey}}", In: http.APIKeyInQuery},
+		},
+		{
+			// The first example by name
+			Name:   "Replace a pet",
+			Method: "PUT",
+			URL:    "{{baseUrl}}/pets/{{petId}}",
+			Body:   "{\n  \"name\": \"Rex\",\n  \"tag\": \"dino\"\n}",
+			Auth:   bearer,
+		},
+		{
+			Name:     "Upload a photo",
+			Method:   "POST",
+			URL:      "{{baseUrl}}/pets/{{petId}}/photo",
+			BodyType: http.BodyMultipart,
+			Form:     []http.KeyValue{{Key: "caption", Value: "At the beach"}, {Key: "file", File: true}},
+			Auth: http.Auth{
+				Type:         http.AuthOAuth2,
+				GrantType:    http.GrantClientCredentials,
+				TokenURL:     "https://auth.example.com/client-token",
+				ClientID:     "{{clientId}}",
+				ClientSecret: "{{clientSecret}}",
+				Scope:        "photos:write pets:read",
+			},
+		},
+	}
+	wantFolderIDs := []int64{0, 1, 1, 1, 1, 2}
+
+	if len(imported.Requests) != len(wantRequests) {
+		t.Fatalf("imported %d requests, want %d", len(imported.Requests), len(wantRequests))
+	}
+	for i, want := range wantRequests {
+		saved := imported.Requests[i]
+		if got := saved.ToHTTPRequest(); !reflect.DeepEqual(got, want) {
+			t.Errorf("request %d\n got  %+v\n want %+v", i, got, want)
+		}
+		if saved.FolderID != wantFolderIDs[i] {
+			t.Errorf("request %q is in folder %d, want %d", saved.Name, saved.FolderID, wantFolderIDs[i])
+		}
+	}
+}
+
+// TestPostmanRoundTrip exports the generated collection to Postman and
+// imports it back
+func TestPostmanRoundTrip(t *testing.T) {
+	imported := importFixture(t)
+
+	data, err := postman.Export(imported.Collection, imported.Folders, imported.Requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := postman.Import(data)
+	if err != nil {
+		t.Fatalf("importing the export: %v", err)
+	}
+
+	if !reflect.DeepEqual(again.Collection, imported.Collection) {
+		t.Errorf("collection = %+v, want %+v", again.Collection, imported.Collection)
+	}
+	if !reflect.DeepEqual(again.Folders, imported.Folders) {
+		t.Errorf("folders = %+v, want %+v", again.Folders, imported.Folders)
+	}
+
+	byName := make(map[string]*storage.SavedRequest)
+	for _, saved := range again.Requests {
+		byName[saved.Name] = saved
+	}
+	if len(byName) != len(imported.Requests) {
+		t.Fatalf("round trip has %d requests, want %d", len(byName), len(imported.Requests))
+	}
+	for _, want := range imported.Requests {
+		got, ok := byName[want.Name]
+		if !ok {
+			t.Errorf("request %q is missing", want.Name)
+			continue
+		}
+		if got.FolderID != want.FolderID {
+			t.Errorf("request %q is in folder %d, want %d", want.Name, got.FolderID, want.FolderID)
+		}
+		if g, w := withQuery(got), withQuery(want); !reflect.DeepEqual(g, w) {
+			t.Errorf("request %q\n got  %+v\n want %+v", want.Name, g, w)
+		}
+	}
+}
+
+// withQuery returns the request with its query params table filled from the
+// URL, as it is when the request is opened; Postman keeps the table and
+// generated requests leave it to the URL
+func withQuery(saved *storage.SavedRequest) *http.Request {
+	req := saved.ToHTTPRequest()
+	req.Params.Query = http.MergeQuery(req.Params.Query, req.URL)
+	return req
+}
+
+func TestImportErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not YAML", "openapi: [3"},
+		{"Swagger 2", "swagger: '2.0'\npaths: {}"},
+		{"no operations", "openapi: 3.1.0\ninfo: {title: Empty}\npaths: {}"},
+	}
+	for _, tt := range tests {
+		if _, err := Import([]byte(tt.data)); err == nil {
+			t.Errorf("%s: Import succeeded", tt.name)
+		}
+	}
+}
+
+func TestExampleCutsOffCycles(t *testing.T) {
+	s := &spec{root: map[string]any{
+		"components": map[string]any{"schemas": map[string]any{
+			"Node": map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"value": map[string]any{"type": "integer"},
+					"next":  map[string]any{"$ref": "#/components/schemas/Node"},
+				},
+			},
+		}},
+	}}
+	got := s.example(map[string]any{"$ref": "#/components/schemas/Node"}, nil, 0)
+	want := map[string]any{"value": 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("example = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,253 @@
+package postman
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+	"github.com/YashIIT0909/TRexT/internal/utils"
+)
+
+// Export writes a collection with its folders and requests as a Postman
+// Collection v2.1 file. Per-request network settings have no Postman
+// equivalent and are dropped.
+func Export(c *storage.Collection, folders []*storage.Folder, requests []*storage.SavedRequest) ([]byte, error) {
+	out := collection{
+		Info: info{
+			PostmanID:   newUUID(),
+			Name:        c.Name,
+			Description: description(c.Description),
+			Schema:      SchemaURL,
+		},
+	}
+
+	for _, key := range sortedKeys(c.Variables) {
+		out.Variable = append(out.Variable, keyValue{Key: key, Value: c.Variables[key], Type: "string"})
+	}
+
+	out.Item = exportItems(0, folders, requests)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(out); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// exportItems returns the folders and requests inside a folder, or at the top
+// of the collection for folder 0
+func exportItems(folderID int64, folders []*storage.Folder, requests []*storage.SavedRequest) []item {
+	items := make([]item, 0)
+	for _, f := range folders {
+		if f.ParentID == folderID {
+			items = append(items, item{
+				Name: f.Name,
+				Item: exportItems(f.ID, folders, requests),
+			})
+		}
+	}
+	for _, saved := range requests {
+		if saved.FolderID == folderID {
+			req := saved.ToHTTPRequest()
+			items = append(items, item{
+				Name:    req.Name,
+				Request: exportRequest(req),
+			})
+		}
+	}
+	return items
+}
+
+// exportRequest converts a TRexT request into a Postman request
+func exportRequest(req *http.Request) *request {
+	r := &request{
+		Method: req.Method,
+		Header: make([]keyValue, 0, len(req.Headers)),
+		URL:    exportURL(req.URL, req.Params),
+	}
+
+	for _, h := range req.Headers {
+		r.Header = append(r.Header, keyValue{
+			Key:         h.Key,
+			Value:       h.Value,
+			Type:        "text",
+			Disabled:    h.Disabled,
+			Description: description(h.Description),
+		})
+	}
+
+	r.Body = exportBody(req)
+
+	r.Auth = exportAuth(req.Auth)
+	return r
+}
+
+// exportBody converts the body of a TRexT request into a Postman body
+func exportBody(req *http.Request) *body {
+	switch req.BodyType {
+	case http.BodyNone:
+		return nil
+	case http.BodyURLEncoded, http.BodyMultipart:
+		if len(req.Form) == 0 {
+			return nil
+		}
+		b := &body{Mode: "urlencoded"}
+		if req.BodyType == http.BodyMultipart {
+			b.Mode = "formdata"
+		}
+		for _, f := range req.Form {
+			field := keyValue{Key: f.Key, Value: f.Value, Type: "text", Disabled: f.Disabled, Description: description(f.Description)}
+			if f.File {
+				field.Type, field.Value, field.Src = "file", nil, f.Value
+			}
+			if b.Mode == "formdata" {
+				b.FormData = append(b.FormData, field)
+			} else {
+				field.Type = ""
+				b.URLEncoded = append(b.URLEncoded, field)
+			}
+		}
+		return b
+	case http.BodyBinary:
+		if req.BodyFile == "" {
+			return nil
+		}
+		return &body{Mode: "file", File: &bodyFile{Src: req.BodyFile}}
+	case http.BodyGraphQL:
+		if req.GraphQL.Query == "" {
+			return nil
+		}
+		return &body{Mode: "graphql", GraphQL: &graphQL{Query: req.GraphQL.Query, Variables: req.GraphQL.Variables}}
+	}
+
+	if req.Body == "" {
+		return nil
+	}
+	b := &body{Mode: "raw", Raw: req.Body, Options: &bodyOptions{}}
+	switch {
+	case req.BodyType == http.BodyXML:
+		b.Options.Raw.Language = "xml"
+	case req.BodyType == http.BodyText:
+		b.Options.Raw.Language = "text"
+	case utils.IsValidJSON(req.Body):
+		b.Options.Raw.Language = "json"
+	default:
+		b.Options = nil
+	}
+	return b
+}
+
+// exportURL splits a URL into the parts Postman stores alongside the raw form,
+// listing the disabled query parameters among those of the URL and the
+// values of its path params
+func exportURL(rawURL string, params http.Params) requestURL {
+	u := requestURL{Raw: rawURL}
+
+	rest := rawURL
+	if protocol, after, ok := strings.Cut(rest, "://"); ok {
+		u.Protocol = protocol
+		rest = after
+	}
+	rest, _, _ = strings.Cut(rest, "?")
+	host, path, _ := strings.Cut(rest, "/")
+	if host != "" {
+		u.Host = strings.Split(host, ".")
+	}
+	if path != "" {
+		u.Path = strings.Split(path, "/")
+	}
+
+	// Enabled parameters keep the encoding they have in the URL
+	var pairs []string
+	if _, rawQuery, ok := strings.Cut(strings.SplitN(rawURL, "#", 2)[0], "?"); ok {
+		for _, pair := range strings.Split(rawQuery, "&") {
+			if pair != "" {
+				pairs = append(pairs, pair)
+			}
+		}
+	}
+	for _, q := range http.MergeQuery(params.Query, rawURL) {
+		if q.Disabled {
+			u.Query = append(u.Query, keyValue{Key: q.Key, Value: q.Value, Disabled: true, Description: description(q.Description)})
+			continue
+		}
+		key, value, _ := strings.Cut(pairs[0], "=")
+		pairs = pairs[1:]
+		u.Query = append(u.Query, keyValue{Key: key, Value: value, Description: description(q.Description)})
+	}
+
+	for _, p := range http.MergePath(params.Path, rawURL) {
+		u.Variable = append(u.Variable, keyValue{Key: p.Key, Value: p.Value, Disabled: p.Disabled, Description: description(p.Description)})
+	}
+	return u
+}
+
+// exportAuth converts TRexT auth into Postman auth
+func exportAuth(a http.Auth) *auth {
+	switch a.Type {
+	case http.AuthBasic:
+		return &auth{Type: "basic", Params: map[string]string{"username": a.Username, "password": a.Password}}
+	case http.AuthDigest:
+		return &auth{Type: "digest", Params: map[string]string{"username": a.Username, "password": a.Password}}
+	case http.AuthBearer:
+		return &auth{Type: "bearer", Params: map[string]string{"token": a.Token}}
+	case http.AuthAPIKey:
+		in := "header"
+		if a.In == http.APIKeyInQuery {
+			in = "query"
+		}
+		return &auth{Type: "apikey", Params: map[string]string{"key": a.Key, "value": a.Value, "in": in}}
+	case http.AuthOAuth2:
+		grant := "client_credentials"
+		switch a.GrantType {
+		case http.GrantPassword:
+			grant = "password_credentials"
+		case http.GrantAuthorizationCode:
+			grant = "authorization_code_with_pkce"
+		}
+		params := map[string]string{
+			"grant_type":     grant,
+			"accessTokenUrl": a.TokenURL,
+			"clientId":       a.ClientID,
+			"clientSecret":   a.ClientSecret,
+			"scope":          a.Scope,
+		}
+		switch a.GrantType {
+		case http.GrantAuthorizationCode:
+			params["authUrl"] = a.AuthURL
+			params["redirect_uri"] = a.RedirectURL
+		case http.GrantPassword:
+			params["username"] = a.Username
+			params["password"] = a.Password
+		}
+		return &auth{Type: "oauth2", Params: params}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,506 @@
+// Package postman converts between TRexT collections and the Postman
+// Collection v2.1 format.
+package postman
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+)
+
+// SchemaURL identifies the Postman Collection v2.1 format
+const SchemaURL = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// collection is a Postman collection file
+type collection struct {
+	Info     info       `json:"info"`
+	Item     []item     `json:"item"`
+	Auth     *auth      `json:"auth,omitempty"`
+	Variable []keyValue `json:"variable,omitempty"`
+}
+
+type info struct {
+	PostmanID   string      `json:"_postman_id,omitempty"`
+	Name        string      `json:"name"`
+	Description description `json:"description,omitempty"`
+	Schema      string      `json:"schema"`
+}
+
+// item is either a request or a folder holding more items
+type item struct {
+	Name        string      `json:"name"`
+	Description description `json:"description,omitempty"`
+	Item        []item      `json:"item,omitempty"`
+	Request     *request    `json:"request,omitempty"`
+	Auth        *auth       `json:"auth,omitempty"`
+}
+
+type request struct {
+	Method      string      `json:"method"`
+	Header      []keyValue  `json:"header"`
+	Body        *body       `json:"body,omitempty"`
+	URL         requestURL  `json:"url"`
+	Auth        *auth       `json:"auth,omitempty"`
+	Description description `json:"description,omitempty"`
+}
+
+type body struct {
+	Mode       string       `json:"mode"`
+	Raw        string       `json:"raw,omitempty"`
+	URLEncoded []keyValue   `json:"urlencoded,omitempty"`
+	FormData   []keyValue   `json:"formdata,omitempty"`
+	GraphQL    *graphQL     `json:"graphql,omitempty"`
+	File       *bodyFile    `json:"file,omitempty"`
+	Options    *bodyOptions `json:"options,omitempty"`
+}
+
+// bodyFile is the file sent by a binary body
+type bodyFile struct {
+	Src string `json:"src"`
+}
+
+type bodyOptions struct {
+	Raw struct {
+		Language string `json:"language,omitempty"`
+	} `json:"raw"`
+}
+
+type graphQL struct {
+	Query     string `json:"query"`
+	Variables string `json:"variables,omitempty"`
+}
+
+// keyValue is a header, query parameter, form field or variable
+type keyValue struct {
+	Key         string      `json:"key"`
+	Value       any         `json:"value"`
+	Type        string      `json:"type,omitempty"`
+	Src         any         `json:"src,omitempty"`
+	Disabled    bool        `json:"disabled,omitempty"`
+	Description description `json:"description,omitempty"`
+}
+
+// text returns the value as a string; variables may hold numbers or booleans
+func (kv keyValue) text() string {
+	switch v := kv.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+// requestURL is either a plain string or a structured URL
+type requestURL struct {
+	Raw      string     `json:"raw"`
+	Protocol string     `json:"protocol,omitempty"`
+	Host     []string   `json:"host,omitempty"`
+	Path     []string   `json:"path,omitempty"`
+	Query    []keyValue `json:"query,omitempty"`
+	Variable []keyValue `json:"variable,omitempty"` // values of :name path params
+}
+
+func (u *requestURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+
+	// Host and path may also be plain strings
+	var structured struct {
+		Raw      string          `json:"raw"`
+		Protocol string          `json:"protocol"`
+		Host     json.RawMessage `json:"host"`
+		Path     json.RawMessage `json:"path"`
+		Query    []keyValue      `json:"query"`
+		Variable []keyValue      `json:"variable"`
+	}
+	if err := json.Unmarshal(data, &structured); err != nil {
+		return err
+	}
+	u.Raw = structured.Raw
+	u.Protocol = structured.Protocol
+	u.Host = stringOrList(structured.Host, ".")
+	u.Path = stringOrList(structured.Path, "/")
+	u.Query = structured.Query
+	u.Variable = structured.Variable
+	return nil
+}
+
+// stringOrList decodes a JSON string split on sep, or a list of strings
+func stringOrList(data json.RawMessage, sep string) []string {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		return list
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil && s != "" {
+		return strings.Split(strings.Trim(s, sep), sep)
+	}
+	return nil
+}
+
+// String rebuilds the URL when the raw form is missing
+func (u requestURL) String() string {
+	if u.Raw != "" {
+		return u.Raw
+	}
+
+	var sb strings.Builder
+	if u.Protocol != "" {
+		sb.WriteString(u.Protocol + "://")
+	}
+	sb.WriteString(strings.Join(u.Host, "."))
+	if len(u.Path) > 0 {
+		sb.WriteString("/" + strings.Join(u.Path, "/"))
+	}
+	var params []string
+	for _, q := range u.Query {
+		if !q.Disabled {
+			params = append(params, q.Key+"="+q.text())
+		}
+	}
+	if len(params) > 0 {
+		sb.WriteString("?" + strings.Join(params, "&"))
+	}
+	return sb.String()
+}
+
+// description is either a plain string or an object with content
+type description string
+
+func (d *description) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*d = description(s)
+		return nil
+	}
+	var obj struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*d = description(obj.Content)
+	return nil
+}
+
+// auth holds the parameters of one auth type, e.g. {"type": "basic", "basic": [...]}
+type auth struct {
+	Type   string
+	Params map[string]string
+}
+
+func (a *auth) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(fields["type"], &a.Type); err != nil {
+		return fmt.Errorf("invalid auth type: %w", err)
+	}
+	a.Params = make(map[string]string)
+
+	// v2.1 uses a list of key/value pairs, v2.0 an object
+	params := fields[a.Type]
+	var list []keyValue
+	if err := json.Unmarshal(params, &list); err == nil {
+		for _, kv := range list {
+			a.Params[kv.Key] = kv.text()
+		}
+		return nil
+	}
+	var obj map[string]any
+	if err := json.Unmarshal(params, &obj); err == nil {
+		for key, value := range obj {
+			a.Params[key] = keyValue{Value: value}.text()
+		}
+	}
+	return nil
+}
+
+func (a auth) MarshalJSON() ([]byte, error) {
+	fields := map[string]any{"type": a.Type}
+	if a.Type != "noauth" {
+		params := make([]keyValue, 0, len(a.Params))
+		for _, key := range sortedKeys(a.Params) {
+			params = append(params, keyValue{Key: key, Value: a.Params[key], Type: "string"})
+		}
+		fields[a.Type] = params
+	}
+	return json.Marshal(fields)
+}
+
+// Imported is a collection read from a Postman file. Folder IDs are
+// provisional and only link folders and requests to their parent; parents
+// come before their children.
+type Imported struct {
+	Collection *storage.Collection
+	Folders    []*storage.Folder
+	Requests   []*storage.SavedRequest
+}
+
+// IsCollection reports whether data looks like a Postman collection
+func IsCollection(data []byte) bool {
+	var probe struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return strings.Contains(probe.Info.Schema, "schema.getpostman.com")
+}
+
+// Import reads a Postman Collection v2.1 file, keeping its folders
+func Import(data []byte) (*Imported, error) {
+	var c collection
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid Postman collection: %w", err)
+	}
+	if c.Info.Name == "" {
+		return nil, errors.New("invalid Postman collection: missing name")
+	}
+
+	imported := &Imported{
+		Collection: &storage.Collection{
+			Name:        c.Info.Name,
+			Description: string(c.Info.Description),
+			Variables:   make(map[string]string),
+		},
+	}
+	for _, v := range c.Variable {
+		if !v.Disabled && v.Key != "" {
+			imported.Collection.Variables[v.Key] = v.text()
+		}
+	}
+
+	if err := importItems(imported, c.Item, 0, c.Auth); err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+// importItems converts items recursively; requests without auth inherit the
+// auth of the closest folder or of the collection
+func importItems(imported *Imported, items []item, folderID int64, inherited *auth) error {
+	for _, it := range items {
+		if it.Request == nil {
+			folder := &storage.Folder{
+				ID:       int64(len(imported.Folders) + 1),
+				ParentID: folderID,
+				Name:     it.Name,
+			}
+			imported.Folders = append(imported.Folders, folder)
+
+			folderAuth := inherited
+			if it.Auth != nil {
+				folderAuth = it.Auth
+			}
+			if err := importItems(imported, it.Item, folder.ID, folderAuth); err != nil {
+				return err
+			}
+			continue
+		}
+
+		req, err := convertRequest(it.Request, inherited)
+		if err != nil {
+			return fmt.Errorf("request %q: %w", it.Name, err)
+		}
+		req.Name = it.Name
+		saved := storage.FromHTTPRequest(req, 0)
+		saved.FolderID = folderID
+		imported.Requests = append(imported.Requests, saved)
+	}
+	return nil
+}
+
+// convertRequest converts a Postman request into a TRexT request
+func convertRequest(r *request, inherited *auth) (*http.Request, error) {
+	req := http.NewRequest()
+	if r.Method != "" {
+		req.Method = strings.ToUpper(r.Method)
+	}
+	req.URL = r.URL.String()
+
+	// The query list also keeps the disabled parameters the URL leaves out
+	for _, q := range r.URL.Query {
+		req.Params.Query = append(req.Params.Query, http.KeyValue{
+			Key:         q.Key,
+			Value:       q.text(),
+			Description: string(q.Description),
+			Disabled:    q.Disabled,
+		})
+	}
+
+	for _, v := range r.URL.Variable {
+		req.Params.Path = append(req.Params.Path, http.KeyValue{
+			Key:         v.Key,
+			Value:       v.text(),
+			Description: string(v.Description),
+			Disabled:    v.Disabled,
+		})
+	}
+
+	for _, h := range r.Header {
+		if h.Key != "" {
+			req.Headers = append(req.Headers, http.KeyValue{
+				Key:         h.Key,
+				Value:       h.text(),
+				Description: string(h.Description),
+				Disabled:    h.Disabled,
+			})
+		}
+	}
+
+	if r.Body != nil {
+		convertBody(req, r.Body)
+	}
+
+	a := inherited
+	if r.Auth != nil {
+		a = r.Auth
+	}
+	if a != nil {
+		req.Auth = convertAuth(*a)
+	}
+	return req, nil
+}
+
+// rawContentTypes maps the raw body languages to the Content-Type Postman sends
+var rawContentTypes = map[string]string{
+	"json":       "application/json",
+	"xml":        "application/xml",
+	"html":       "text/html",
+	"javascript": "application/javascript",
+	"text":       "text/plain",
+}
+
+// convertBody sets the request body and its type. Raw bodies also get the
+// Content-Type of their language, as Postman sends it.
+func convertBody(req *http.Request, b *body) {
+	switch b.Mode {
+	case "raw":
+		req.Body = b.Raw
+		language := ""
+		if b.Options != nil {
+			language = b.Options.Raw.Language
+		}
+		switch language {
+		case "json":
+			req.BodyType = http.BodyJSON
+		case "xml":
+			req.BodyType = http.BodyXML
+		default:
+			req.BodyType = http.BodyText
+			if contentType := rawContentTypes[language]; contentType != "" && !req.Headers.Has("Content-Type") {
+				req.Headers.Add("Content-Type", contentType)
+			}
+		}
+	case "urlencoded":
+		req.BodyType = http.BodyURLEncoded
+		for _, field := range b.URLEncoded {
+			req.Form = append(req.Form, convertField(field))
+		}
+	case "formdata":
+		// File fields refer to paths on the exporting machine, which may
+		// need fixing before the request is sent
+		req.BodyType = http.BodyMultipart
+		for _, field := range b.FormData {
+			req.Form = append(req.Form, convertField(field))
+		}
+	case "file":
+		req.BodyType = http.BodyBinary
+		if b.File != nil {
+			req.BodyFile = b.File.Src
+		}
+	case "graphql":
+		req.BodyType = http.BodyGraphQL
+		if b.GraphQL != nil {
+			req.GraphQL = http.GraphQL{Query: b.GraphQL.Query, Variables: b.GraphQL.Variables}
+		}
+	case "", "none":
+		req.BodyType = http.BodyNone
+	}
+}
+
+// convertField converts a url-encoded or form-data field
+func convertField(field keyValue) http.KeyValue {
+	kv := http.KeyValue{
+		Key:         field.Key,
+		Value:       field.text(),
+		Description: string(field.Description),
+		Disabled:    field.Disabled,
+	}
+	if field.Type == "file" {
+		kv.File = true
+		// Postman allows several files per field; only the first is kept
+		switch src := field.Src.(type) {
+		case string:
+			kv.Value = src
+		case []any:
+			kv.Value = ""
+			if len(src) > 0 {
+				kv.Value, _ = src[0].(string)
+			}
+		default:
+			kv.Value = ""
+		}
+	}
+	return kv
+}
+
+// oauth2Grants maps Postman grant types to TRexT's
+var oauth2Grants = map[string]string{
+	"client_credentials":           http.GrantClientCredentials,
+	"password_credentials":         http.GrantPassword,
+	"authorization_code":           http.GrantAuthorizationCode,
+	"authorization_code_with_pkce": http.GrantAuthorizationCode,
+}
+
+// convertAuth converts Postman auth; unsupported types are dropped
+func convertAuth(a auth) http.Auth {
+	p := a.Params
+	switch a.Type {
+	case "basic":
+		return http.Auth{Type: http.AuthBasic, Username: p["username"], Password: p["password"]}
+	case "digest":
+		return http.Auth{Type: http.AuthDigest, Username: p["username"], Password: p["password"]}
+	case "bearer":
+		return http.Auth{Type: http.AuthBearer, Token: p["token"]}
+	case "apikey":
+		in := http.APIKeyInHeader
+		if p["in"] == "query" {
+			in = http.APIKeyInQuery
+		}
+		return http.Auth{Type: http.AuthAPIKey, Key: p["key"], Value: p["value"], In: in}
+	case "oauth2":
+		// The implicit grant isn't supported; the code flow is the closest match
+		grant, ok := oauth2Grants[p["grant_type"]]
+		if !ok {
+			grant = http.GrantAuthorizationCode
+		}
+		return http.Auth{
+			Type:         http.AuthOAuth2,
+			GrantType:    grant,
+			TokenURL:     p["accessTokenUrl"],
+			AuthURL:      p["authUrl"],
+			RedirectURL:  p["redirect_uri"],
+			ClientID:     p["clientId"],
+			ClientSecret: p["clientSecret"],
+			Scope:        p["scope"],
+			Username:     p["username"],
+			Password:     p["password"],
+		}
+	}
+	return http.Auth{}
+}
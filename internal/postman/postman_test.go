@@ -0,0 +1,248 @@
+package postman
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/storage"
+)
+
+func readFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/shop.postman_collection.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// requests returns the imported requests with the folder each is in
+func requests(imported *Imported) ([]*http.Request, []int64) {
+	var reqs []*http.Request
+	var folders []int64
+	for _, saved := range imported.Requests {
+		reqs = append(reqs, saved.ToHTTPRequest())
+		folders = append(folders, saved.FolderID)
+	}
+	return reqs, folders
+}
+
+func TestImport(t *testing.T) {
+	data := readFixture(t)
+	if !IsCollection(data) {
+		t.Fatal("IsCollection = false for a Postman collection")
+	}
+	imported, err := Import(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantCollection := &storage.Collection{
+		Name:        "Shop API",
+		Description: "Orders and users of the shop",
+		Variables:   map[string]string{"baseUrl": "https://shop.example.com", "pageSize": "20"},
+	}
+	if !reflect.DeepEqual(imported.Collection, wantCollection) {
+		t.Errorf("collection = %+v, want %+v", imported.Collection, wantCollection)
+	}
+
+	wantFolders := []*storage.Folder{
+		{ID: 1, Name: "Users"},
+		{ID: 2, ParentID: 1, Name: "Admin"},
+		{ID: 3, Name: "Orders"},
+	}
+	if !reflect.DeepEqual(imported.Folders, wantFolders) {
+		t.Errorf("folders = %+v, want %+v", imported.Folders, wantFolders)
+	}
+
+	bearer := http.Auth{Type: http.AuthBearer, Token: "{{token}}"}
+	basic := http.Auth{Type: http.AuthBasic, Username: "admin", Password: "{{adminPassword}}"}
+	wantRequests := []*http.Request{
+		{Name: "Ping", Method: "GET", URL: "{{baseUrl}}/ping", Auth: bearer},
+		{
+			Name:   "Get user",
+			Method: "GET",
+			URL:    "{{baseUrl}}/users/:id?expand=orders",
+			Headers: http.Headers{
+				{Key: "Accept", Value: "application/json"},
+				{Key: "X-Debug", Value: "1", Disabled: true, Description: "Verbose errors"},
+			},
+			Params: http.Params{
+				Query: []http.KeyValue{{Key: "expand", Value: "orders"}, {Key: "page", Value: "2", Disabled: true}},
+				Path:  []http.KeyValue{{Key: "id", Value: "42", Description: "User ID"}},
+			},
+			Auth: basic,
+		},
+		{
+			Name:     "Upload avatar",
+			Method:   "POST",
+			URL:      "{{baseUrl}}/users/avatar",
+			BodyType: http.BodyMultipart,
+			Form: []http.KeyValue{
+				{Key: "name", Value: "ada"},
+				{Key: "avatar", Value: "/home/ada/avatar.png", File: true},
+				{Key: "note", Value: "skip me", Disabled: true},
+			},
+			Auth: basic,
+		},
+		{
+			Name:   "Rotate key",
+			Method: "POST",
+			URL:    "{{baseUrl}}/admin/keys",
+			Auth:   http.Auth{Type: http.AuthAPIKey, Key: "X-Admin-Key", Value: "{{adminKey}}", In: http.APIKeyInHeader},
+		},
+		{
+			Name:   "Create order",
+			Method: "POST",
+			URL:    "{{baseUrl}}/orders",
+			Body:   "{\n  \"sku\": \"T-REX\",\n  \"quantity\": 1\n}",
+			Auth:   bearer,
+		},
+		{
+			Name:     "Search orders",
+			Method:   "POST",
+			URL:      "{{baseUrl}}/orders/search",
+			BodyType: http.BodyURLEncoded,
+			Form:     []http.KeyValue{{Key: "q", Value: "dino & co"}, {Key: "limit", Value: "{{pageSize}}"}},
+			Auth:     bearer,
+		},
+		{
+			Name:     "Order graph",
+			Method:   "POST",
+			URL:      "{{baseUrl}}/graphql",
+			BodyType: http.BodyGraphQL,
+			GraphQL:  http.GraphQL{Query: "query Order($id: ID!) { order(id: $id) { id total } }", Variables: `{"id": "7"}`},
+			Auth:     bearer,
+		},
+		{
+			Name:     "Import orders",
+			Method:   "PUT",
+			URL:      "{{baseUrl}}/orders/import",
+			BodyType: http.BodyBinary,
+			BodyFile: "/home/ada/orders.csv",
+			Auth: http.Auth{
+				Type:         http.AuthOAuth2,
+				GrantType:    http.GrantClientCredentials,
+				TokenURL:     "https://auth.example.com/token",
+				ClientID:     "{{clientId}}",
+				ClientSecret: "{{clientSecret}}",
+				Scope:        "orders:write",
+			},
+		},
+		{
+			Name:     "Export as XML",
+			Method:   "POST",
+			URL:      "{{baseUrl}}/orders/export",
+			BodyType: http.BodyXML,
+			Body:     `<export format="xml"/>`,
+			Auth:     bearer,
+		},
+	}
+	wantFolderIDs := []int64{0, 1, 2, 2, 3, 3, 3, 3, 3}
+
+	got, folderIDs := requests(imported)
+	if len(got) != len(wantRequests) {
+		t.Fatalf("imported %d requests, want %d", len(got), len(wantRequests))
+	}
+	for i, want := range wantRequests {
+		if !reflect.DeepEqual(got[i], want) {
+			t.Errorf("request %d\n got  %+v\n want %+v", i, got[i], want)
+		}
+	}
+	if !reflect.DeepEqual(folderIDs, wantFolderIDs) {
+		t.Errorf("folder IDs = %v, want %v", folderIDs, wantFolderIDs)
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	imported, err := Import(readFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := Export(imported.Collection, imported.Folders, imported.Requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsCollection(data) {
+		t.Fatal("IsCollection = false for an exported collection")
+	}
+	again, err := Import(data)
+	if err != nil {
+		t.Fatalf("importing the export: %v", err)
+	}
+
+	if !reflect.DeepEqual(again.Collection, imported.Collection) {
+		t.Errorf("collection = %+v, want %+v", again.Collection, imported.Collection)
+	}
+	if !reflect.DeepEqual(again.Folders, imported.Folders) {
+		t.Errorf("folders = %+v, want %+v", again.Folders, imported.Folders)
+	}
+	// Folders are exported before the requests next to them, so requests
+	// are matched by name
+	byName := func(imported *Imported) map[string]*storage.SavedRequest {
+		m := make(map[string]*storage.SavedRequest)
+		for _, saved := range imported.Requests {
+			m[saved.Name] = saved
+		}
+		return m
+	}
+	got, want := byName(again), byName(imported)
+	if len(got) != len(want) {
+		t.Fatalf("round trip has %d requests, want %d", len(got), len(want))
+	}
+	for name, w := range want {
+		g, ok := got[name]
+		if !ok {
+			t.Errorf("request %q is missing", name)
+			continue
+		}
+		if g.FolderID != w.FolderID {
+			t.Errorf("request %q is in folder %d, want %d", name, g.FolderID, w.FolderID)
+		}
+		if !reflect.DeepEqual(g.ToHTTPRequest(), w.ToHTTPRequest()) {
+			t.Errorf("request %q\n got  %+v\n want %+v", name, g.ToHTTPRequest(), w.ToHTTPRequest())
+		}
+	}
+}
+
+func TestExportRequest(t *testing.T) {
+	noVerify := false
+	req := &http.Request{
+		Name:     "Text",
+		Method:   "POST",
+		URL:      "https://example.com/a?x=1%202&y=3",
+		Params:   http.Params{Query: []http.KeyValue{{Key: "x", Value: "1 2"}, {Key: "off", Value: "0", Disabled: true}, {Key: "y", Value: "3"}}},
+		BodyType: http.BodyText,
+		Body:     "hello",
+		Settings: http.Settings{SSLVerify: &noVerify},
+	}
+	r := exportRequest(req)
+
+	wantQuery := []keyValue{{Key: "x", Value: "1%202"}, {Key: "off", Value: "0", Disabled: true}, {Key: "y", Value: "3"}}
+	if !reflect.DeepEqual(r.URL.Query, wantQuery) {
+		t.Errorf("query = %+v, want %+v", r.URL.Query, wantQuery)
+	}
+	if r.URL.Protocol != "https" || !reflect.DeepEqual(r.URL.Host, []string{"example", "com"}) || !reflect.DeepEqual(r.URL.Path, []string{"a"}) {
+		t.Errorf("url = %+v", r.URL)
+	}
+	if r.Body == nil || r.Body.Mode != "raw" || r.Body.Options == nil || r.Body.Options.Raw.Language != "text" {
+		t.Errorf("body = %+v, want raw text", r.Body)
+	}
+	if r.Auth != nil {
+		t.Errorf("auth = %+v, want none", r.Auth)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	for _, data := range []string{`{`, `{"info": {}}`, `[]`} {
+		if _, err := Import([]byte(data)); err == nil {
+			t.Errorf("Import(%s) succeeded", data)
+		}
+	}
+	if IsCollection([]byte(`{"openapi": "3.0.0"}`)) {
+		t.Error("IsCollection = true for an OpenAPI document")
+	}
+}
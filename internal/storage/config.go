@@ -3,20 +3,35 @@ package storage
 import (
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/YashIIT0909/TRexT/internal/http"
+	"github.com/YashIIT0909/TRexT/internal/utils"
 	"gopkg.in/yaml.v3"
 )
 
 // Config holds application configuration
 type Config struct {
-	Theme          string `yaml:"theme"`
-	DefaultTimeout int    `yaml:"defaultTimeout"` // seconds
-	SSLVerify      bool   `yaml:"sslVerify"`
-	Proxy          string `yaml:"proxy"`
-	History        struct {
-		MaxItems int  `yaml:"maxItems"`
-		Enabled  bool `yaml:"enabled"`
+	Theme             string `yaml:"theme"`
+	DefaultTimeout    int    `yaml:"defaultTimeout"` // seconds
+	SSLVerify         bool   `yaml:"sslVerify"`
+	Proxy             string `yaml:"proxy"`             // http://, https:// or socks5:// URL, may include user:pass@
+	NoProxy           string `yaml:"noProxy"`           // comma-separated hosts, domains or CIDRs that bypass the proxy
+	ActiveEnvironment string `yaml:"activeEnvironment"` // name of the environment used for {{variable}} substitution
+	Storage           struct {
+		Backend string `yaml:"backend"` // postgres or sqlite, empty picks postgres when DATABASE_URL is set
+		Path    string `yaml:"path"`    // SQLite database file, defaults to ~/.config/trext/trext.db
+	} `yaml:"storage"`
+	History struct {
+		MaxItems    int  `yaml:"maxItems"`    // 0 keeps any number of entries
+		MaxAgeDays  int  `yaml:"maxAgeDays"`  // 0 keeps entries forever
+		MaxBodySize int  `yaml:"maxBodySize"` // bytes of each response body kept
+		Enabled     bool `yaml:"enabled"`
 	} `yaml:"history"`
+	Download struct {
+		Threshold int64  `yaml:"threshold"` // bodies larger than this many bytes are streamed to a file, 0 never
+		Dir       string `yaml:"dir"`       // where streamed bodies are written, defaults to the temp directory
+	} `yaml:"download"`
 	Keybindings struct {
 		SendRequest string `yaml:"sendRequest"`
 		NewRequest  string `yaml:"newRequest"`
@@ -33,7 +48,9 @@ func DefaultConfig() *Config {
 		SSLVerify:      true,
 	}
 	cfg.History.MaxItems = 100
+	cfg.History.MaxBodySize = 256 * 1024
 	cfg.History.Enabled = true
+	cfg.Download.Threshold = 10 * 1024 * 1024
 	cfg.Keybindings.SendRequest = "Ctrl+Enter"
 	cfg.Keybindings.NewRequest = "Ctrl+N"
 	cfg.Keybindings.SaveRequest = "Ctrl+S"
@@ -41,6 +58,24 @@ func DefaultConfig() *Config {
 	return cfg
 }
 
+// TransportConfig returns the HTTP client network settings
+func (c *Config) TransportConfig() http.TransportConfig {
+	return http.TransportConfig{
+		Timeout:   time.Duration(c.DefaultTimeout) * time.Second,
+		SSLVerify: c.SSLVerify,
+		Proxy:     c.Proxy,
+		NoProxy:   http.ParseNoProxy(c.NoProxy),
+	}
+}
+
+// DownloadConfig returns when response bodies are streamed to disk
+func (c *Config) DownloadConfig() http.DownloadConfig {
+	return http.DownloadConfig{
+		Threshold: c.Download.Threshold,
+		Dir:       utils.ExpandPath(c.Download.Dir),
+	}
+}
+
 // getConfigPath returns the path to the config file
 func getConfigPath() (string, error) {
 	home, err := os.UserHomeDir()
@@ -50,6 +85,15 @@ func getConfigPath() (string, error) {
 	return filepath.Join(home, ".config", "trext", "config.yaml"), nil
 }
 
+// getDefaultDatabasePath returns the path to the embedded SQLite database
+func getDefaultDatabasePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "trext", "trext.db"), nil
+}
+
 // LoadConfig loads configuration from file or returns default
 func LoadConfig() (*Config, error) {
 	configPath, err := getConfigPath()
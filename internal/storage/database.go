@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/YashIIT0909/TRexT/internal/storage/db"
 	"github.com/YashIIT0909/TRexT/sql/schemas"
@@ -106,6 +108,12 @@ func (d *DB) SaveRequest(req *SavedRequest) error {
 			Headers:      pgtype.Text{String: req.Headers, Valid: true},
 			Body:         pgtype.Text{String: req.Body, Valid: true},
 			CollectionID: collectionID,
+			FolderID:     pgtype.Int4{Int32: int32(req.FolderID), Valid: req.FolderID > 0},
+			Settings:     req.Settings,
+			Auth:         req.Auth,
+			Filter:       req.Filter,
+			Params:       req.Params,
+			BodyOptions:  req.BodyOptions,
 		})
 		if err != nil {
 			return err
@@ -120,6 +128,12 @@ func (d *DB) SaveRequest(req *SavedRequest) error {
 			Headers:      pgtype.Text{String: req.Headers, Valid: true},
 			Body:         pgtype.Text{String: req.Body, Valid: true},
 			CollectionID: collectionID,
+			FolderID:     pgtype.Int4{Int32: int32(req.FolderID), Valid: req.FolderID > 0},
+			Settings:     req.Settings,
+			Auth:         req.Auth,
+			Filter:       req.Filter,
+			Params:       req.Params,
+			BodyOptions:  req.BodyOptions,
 			ID:           int32(req.ID),
 		})
 		if err != nil {
@@ -147,11 +161,54 @@ func (d *DB) GetAllRequests() ([]*SavedRequest, error) {
 			Headers:      row.Headers.String,
 			Body:         row.Body.String,
 			CollectionID: int64(row.CollectionID),
+			FolderID:     int64(row.FolderID.Int32),
+			Settings:     row.Settings,
+			Auth:         row.Auth,
+			Filter:       row.Filter,
+			Params:       row.Params,
+			BodyOptions:  row.BodyOptions,
 		}
 	}
 	return requests, nil
 }
 
+// GetRequestsByCollection returns the saved requests of a collection
+func (d *DB) GetRequestsByCollection(collectionID int64) ([]*SavedRequest, error) {
+	ctx := context.Background()
+	rows, err := d.queries.GetRequestsByCollectionID(ctx, pgtype.Int4{Int32: int32(collectionID), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]*SavedRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = &SavedRequest{
+			ID:           int64(row.ID),
+			Name:         row.Name,
+			URL:          row.Url,
+			Method:       row.Method,
+			Headers:      row.Headers.String,
+			Body:         row.Body.String,
+			CollectionID: int64(row.CollectionID),
+			FolderID:     int64(row.FolderID.Int32),
+			Settings:     row.Settings,
+			Auth:         row.Auth,
+			Filter:       row.Filter,
+			Params:       row.Params,
+			BodyOptions:  row.BodyOptions,
+		}
+	}
+	return requests, nil
+}
+
+// SaveRequestFilter stores the response filter of a saved request
+func (d *DB) SaveRequestFilter(id int64, filter string) error {
+	return d.queries.UpdateRequestFilter(context.Background(), db.UpdateRequestFilterParams{
+		Filter: filter,
+		ID:     int32(id),
+	})
+}
+
 // DeleteRequest deletes a request by ID
 func (d *DB) DeleteRequest(id int64) error {
 	return d.queries.DeleteRequest(context.Background(), int32(id))
@@ -167,32 +224,52 @@ func (d *DB) GetCollections() ([]*Collection, error) {
 
 	collections := make([]*Collection, len(rows))
 	for i, row := range rows {
-		collections[i] = &Collection{
-			ID:          int64(row.ID),
-			Name:        row.Name,
-			Description: row.Description.String,
-		}
+		collections[i] = toCollection(row)
 	}
 	return collections, nil
 }
 
+// toCollection converts a collection row, decoding its variables
+func toCollection(row *db.Collection) *Collection {
+	variables := make(map[string]string)
+	_ = json.Unmarshal([]byte(row.Variables), &variables)
+	return &Collection{
+		ID:          int64(row.ID),
+		Name:        row.Name,
+		Description: row.Description.String,
+		Variables:   variables,
+		SkipHistory: row.SkipHistory,
+	}
+}
+
 // SaveCollection saves or updates a collection
 func (d *DB) SaveCollection(c *Collection) error {
-	ctx := context.Background()
+	return saveCollection(context.Background(), d.queries, c)
+}
+
+func saveCollection(ctx context.Context, queries *db.Queries, c *Collection) error {
+	variablesJSON, err := json.Marshal(c.Variables)
+	if err != nil {
+		return err
+	}
 
 	if c.ID == 0 {
-		result, err := d.queries.CreateCollection(ctx, db.CreateCollectionParams{
+		result, err := queries.CreateCollection(ctx, db.CreateCollectionParams{
 			Name:        c.Name,
 			Description: pgtype.Text{String: c.Description, Valid: true},
+			Variables:   string(variablesJSON),
+			SkipHistory: c.SkipHistory,
 		})
 		if err != nil {
 			return err
 		}
 		c.ID = int64(result.ID)
 	} else {
-		err := d.queries.UpdateCollection(ctx, db.UpdateCollectionParams{
+		err := queries.UpdateCollection(ctx, db.UpdateCollectionParams{
 			Name:        c.Name,
 			Description: pgtype.Text{String: c.Description, Valid: true},
+			Variables:   string(variablesJSON),
+			SkipHistory: c.SkipHistory,
 			ID:          int32(c.ID),
 		})
 		if err != nil {
@@ -202,20 +279,239 @@ func (d *DB) SaveCollection(c *Collection) error {
 	return nil
 }
 
+// ImportCollection creates a collection with its folders and requests in a
+// single transaction. Folder IDs, parent IDs and the requests' folder IDs
+// refer to the given folders, parents first, and are replaced by stored IDs.
+func (d *DB) ImportCollection(c *Collection, folders []*Folder, requests []*SavedRequest) error {
+	ctx := context.Background()
+
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := d.queries.WithTx(tx)
+	c.ID = 0
+	if err := saveCollection(ctx, queries, c); err != nil {
+		return err
+	}
+
+	folderIDs := make(map[int64]int64, len(folders))
+	for _, f := range folders {
+		result, err := queries.CreateFolder(ctx, db.CreateFolderParams{
+			CollectionID: int32(c.ID),
+			ParentID:     pgtype.Int4{Int32: int32(folderIDs[f.ParentID]), Valid: folderIDs[f.ParentID] > 0},
+			Name:         f.Name,
+		})
+		if err != nil {
+			return err
+		}
+		folderIDs[f.ID] = int64(result.ID)
+	}
+	for _, f := range folders {
+		f.ID, f.ParentID, f.CollectionID = folderIDs[f.ID], folderIDs[f.ParentID], c.ID
+	}
+
+	for _, req := range requests {
+		req.FolderID = folderIDs[req.FolderID]
+		result, err := queries.CreateRequest(ctx, db.CreateRequestParams{
+			Name:         req.Name,
+			Url:          req.URL,
+			Method:       req.Method,
+			Headers:      pgtype.Text{String: req.Headers, Valid: true},
+			Body:         pgtype.Text{String: req.Body, Valid: true},
+			CollectionID: pgtype.Int4{Int32: int32(c.ID), Valid: true},
+			FolderID:     pgtype.Int4{Int32: int32(req.FolderID), Valid: req.FolderID > 0},
+			Settings:     req.Settings,
+			Auth:         req.Auth,
+			Filter:       req.Filter,
+			Params:       req.Params,
+			BodyOptions:  req.BodyOptions,
+		})
+		if err != nil {
+			return err
+		}
+		req.ID = int64(result.ID)
+		req.CollectionID = c.ID
+	}
+
+	return tx.Commit(ctx)
+}
+
+// DeleteCollection deletes a collection and its requests in a single transaction
+func (d *DB) DeleteCollection(id int64) error {
+	ctx := context.Background()
+
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := d.queries.WithTx(tx)
+	if err := queries.DeleteRequestsByCollectionID(ctx, pgtype.Int4{Int32: int32(id), Valid: true}); err != nil {
+		return err
+	}
+	if err := queries.DeleteCollection(ctx, int32(id)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetFolders returns the folders of all collections
+func (d *DB) GetFolders() ([]*Folder, error) {
+	rows, err := d.queries.GetFolders(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]*Folder, len(rows))
+	for i, row := range rows {
+		folders[i] = &Folder{
+			ID:           int64(row.ID),
+			CollectionID: int64(row.CollectionID),
+			ParentID:     int64(row.ParentID.Int32),
+			Name:         row.Name,
+		}
+	}
+	return folders, nil
+}
+
+// SaveFolder saves or renames a folder
+func (d *DB) SaveFolder(f *Folder) error {
+	ctx := context.Background()
+	parentID := pgtype.Int4{Int32: int32(f.ParentID), Valid: f.ParentID > 0}
+
+	if f.ID == 0 {
+		result, err := d.queries.CreateFolder(ctx, db.CreateFolderParams{
+			CollectionID: int32(f.CollectionID),
+			ParentID:     parentID,
+			Name:         f.Name,
+		})
+		if err != nil {
+			return err
+		}
+		f.ID = int64(result.ID)
+		return nil
+	}
+
+	return d.queries.UpdateFolder(ctx, db.UpdateFolderParams{
+		ParentID: parentID,
+		Name:     f.Name,
+		ID:       int32(f.ID),
+	})
+}
+
+// DeleteFolder deletes a folder with its subfolders and all their requests
+// in a single transaction
+func (d *DB) DeleteFolder(id int64) error {
+	ctx := context.Background()
+
+	tx, err := d.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	queries := d.queries.WithTx(tx)
+	if err := queries.DeleteFolderRequests(ctx, int32(id)); err != nil {
+		return err
+	}
+	// Subfolders are removed by the parent_id cascade
+	if err := queries.DeleteFolder(ctx, int32(id)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetEnvironments returns all environments
+func (d *DB) GetEnvironments() ([]*Environment, error) {
+	ctx := context.Background()
+	rows, err := d.queries.GetEnvironments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	environments := make([]*Environment, len(rows))
+	for i, row := range rows {
+		variables := make(map[string]string)
+		_ = json.Unmarshal([]byte(row.Variables), &variables)
+		environments[i] = &Environment{
+			ID:        int64(row.ID),
+			Name:      row.Name,
+			Variables: variables,
+		}
+	}
+	return environments, nil
+}
+
+// SaveEnvironment saves or updates an environment
+func (d *DB) SaveEnvironment(env *Environment) error {
+	ctx := context.Background()
+
+	variablesJSON, err := json.Marshal(env.Variables)
+	if err != nil {
+		return err
+	}
+
+	if env.ID == 0 {
+		result, err := d.queries.CreateEnvironment(ctx, db.CreateEnvironmentParams{
+			Name:      env.Name,
+			Variables: string(variablesJSON),
+		})
+		if err != nil {
+			return err
+		}
+		env.ID = int64(result.ID)
+	} else {
+		err := d.queries.UpdateEnvironment(ctx, db.UpdateEnvironmentParams{
+			Name:      env.Name,
+			Variables: string(variablesJSON),
+			ID:        int32(env.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteEnvironment deletes an environment by ID
+func (d *DB) DeleteEnvironment(id int64) error {
+	return d.queries.DeleteEnvironment(context.Background(), int32(id))
+}
+
 // AddToHistory adds a request to history
 func (d *DB) AddToHistory(entry *HistoryEntry) error {
 	ctx := context.Background()
-	result, err := d.queries.AddToHistory(ctx, db.AddToHistoryParams{
-		Url:        entry.URL,
-		Method:     entry.Method,
-		StatusCode: pgtype.Int4{Int32: int32(entry.StatusCode), Valid: true},
-		DurationMs: pgtype.Int8{Int64: entry.Duration, Valid: true},
-		Timestamp:  entry.Timestamp,
+	id, err := d.queries.AddToHistory(ctx, db.AddToHistoryParams{
+		Url:                entry.URL,
+		Method:             entry.Method,
+		StatusCode:         pgtype.Int4{Int32: int32(entry.StatusCode), Valid: entry.StatusCode != 0},
+		DurationMs:         pgtype.Int8{Int64: entry.Duration, Valid: true},
+		Timestamp:          entry.Timestamp,
+		Name:               entry.Name,
+		RequestHeaders:     entry.RequestHeaders,
+		RequestBody:        entry.RequestBody,
+		RequestSettings:    entry.RequestSettings,
+		RequestAuth:        entry.RequestAuth,
+		ResponseHeaders:    entry.ResponseHeaders,
+		ResponseBody:       entry.ResponseBody,
+		ResponseSize:       entry.ResponseSize,
+		Error:              entry.Error,
+		Timing:             entry.Timing,
+		RequestBodyOptions: entry.RequestBodyOptions,
+		RequestParams:      entry.RequestParams,
+		Environment:        entry.Environment,
+		RequestTemplate:    entry.RequestTemplate,
 	})
 	if err != nil {
 		return err
 	}
-	entry.ID = int64(result.ID)
+	entry.ID = int64(id)
 	return nil
 }
 
@@ -231,15 +527,97 @@ func (d *DB) GetHistory(limit int) ([]*HistoryEntry, error) {
 	for i, row := range rows {
 		history[i] = &HistoryEntry{
 			ID:         int64(row.ID),
+			Name:       row.Name,
 			URL:        row.Url,
 			Method:     row.Method,
 			StatusCode: int(row.StatusCode.Int32),
 			Duration:   row.DurationMs.Int64,
 			Timestamp:  row.Timestamp,
+			Error:      row.Error,
 		}
 	}
 	return history, nil
 }
 
+// SearchHistory returns recent history entries whose URL, name or error
+// contains query, or whose method or status code equals it
+func (d *DB) SearchHistory(query string, limit int) ([]*HistoryEntry, error) {
+	ctx := context.Background()
+	rows, err := d.queries.SearchHistory(ctx, db.SearchHistoryParams{
+		Query:    query,
+		MaxItems: int32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*HistoryEntry, len(rows))
+	for i, row := range rows {
+		history[i] = &HistoryEntry{
+			ID:         int64(row.ID),
+			Name:       row.Name,
+			URL:        row.Url,
+			Method:     row.Method,
+			StatusCode: int(row.StatusCode.Int32),
+			Duration:   row.DurationMs.Int64,
+			Timestamp:  row.Timestamp,
+			Error:      row.Error,
+		}
+	}
+	return history, nil
+}
+
+// GetHistoryEntry returns a history entry with the full request and response
+func (d *DB) GetHistoryEntry(id int64) (*HistoryEntry, error) {
+	ctx := context.Background()
+	row, err := d.queries.GetHistoryByID(ctx, int32(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryEntry{
+		ID:                 int64(row.ID),
+		Name:               row.Name,
+		URL:                row.Url,
+		Method:             row.Method,
+		StatusCode:         int(row.StatusCode.Int32),
+		Duration:           row.DurationMs.Int64,
+		Timestamp:          row.Timestamp,
+		RequestHeaders:     row.RequestHeaders,
+		RequestBody:        row.RequestBody,
+		RequestSettings:    row.RequestSettings,
+		RequestAuth:        row.RequestAuth,
+		ResponseHeaders:    row.ResponseHeaders,
+		ResponseBody:       row.ResponseBody,
+		ResponseSize:       row.ResponseSize,
+		Error:              row.Error,
+		Timing:             row.Timing,
+		RequestBodyOptions: row.RequestBodyOptions,
+		RequestParams:      row.RequestParams,
+		Environment:        row.Environment,
+		RequestTemplate:    row.RequestTemplate,
+	}, nil
+}
+
+// PruneHistory deletes all but the newest maxItems entries and those older
+// than maxAge. Zero disables either limit.
+func (d *DB) PruneHistory(maxItems int, maxAge time.Duration) error {
+	ctx := context.Background()
+	if maxAge > 0 {
+		if err := d.queries.DeleteHistoryBefore(ctx, time.Now().Add(-maxAge).Unix()); err != nil {
+			return err
+		}
+	}
+	if maxItems > 0 {
+		return d.queries.TrimHistory(ctx, int32(maxItems))
+	}
+	return nil
+}
+
+// ClearHistory deletes all history entries
+func (d *DB) ClearHistory() error {
+	return d.queries.ClearHistory(context.Background())
+}
+
 // Ensure stdlib driver is registered
 var _ = stdlib.GetDefaultDriver()
@@ -12,20 +12,33 @@ import (
 )
 
 const createCollection = `-- name: CreateCollection :one
-INSERT INTO collections (name, description) 
-VALUES ($1, $2)
-RETURNING id, name, description
+INSERT INTO collections (name, description, variables, skip_history) 
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, description, variables, skip_history
 `
 
 type CreateCollectionParams struct {
 	Name        string      `json:"name"`
 	Description pgtype.Text `json:"description"`
+	Variables   string      `json:"variables"`
+	SkipHistory bool        `json:"skip_history"`
 }
 
 func (q *Queries) CreateCollection(ctx context.Context, arg CreateCollectionParams) (*Collection, error) {
-	row := q.db.QueryRow(ctx, createCollection, arg.Name, arg.Description)
+	row := q.db.QueryRow(ctx, createCollection,
+		arg.Name,
+		arg.Description,
+		arg.Variables,
+		arg.SkipHistory,
+	)
 	var i Collection
-	err := row.Scan(&i.ID, &i.Name, &i.Description)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Variables,
+		&i.SkipHistory,
+	)
 	return &i, err
 }
 
@@ -40,7 +53,7 @@ func (q *Queries) DeleteCollection(ctx context.Context, id int32) error {
 }
 
 const getCollectionByID = `-- name: GetCollectionByID :one
-SELECT id, name, description 
+SELECT id, name, description, variables, skip_history 
 FROM collections 
 WHERE id = $1
 `
@@ -48,12 +61,18 @@ WHERE id = $1
 func (q *Queries) GetCollectionByID(ctx context.Context, id int32) (*Collection, error) {
 	row := q.db.QueryRow(ctx, getCollectionByID, id)
 	var i Collection
-	err := row.Scan(&i.ID, &i.Name, &i.Description)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Description,
+		&i.Variables,
+		&i.SkipHistory,
+	)
 	return &i, err
 }
 
 const getCollections = `-- name: GetCollections :many
-SELECT id, name, description 
+SELECT id, name, description, variables, skip_history 
 FROM collections 
 ORDER BY name
 `
@@ -67,7 +86,13 @@ func (q *Queries) GetCollections(ctx context.Context) ([]*Collection, error) {
 	items := []*Collection{}
 	for rows.Next() {
 		var i Collection
-		if err := rows.Scan(&i.ID, &i.Name, &i.Description); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Description,
+			&i.Variables,
+			&i.SkipHistory,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, &i)
@@ -80,17 +105,25 @@ func (q *Queries) GetCollections(ctx context.Context) ([]*Collection, error) {
 
 const updateCollection = `-- name: UpdateCollection :exec
 UPDATE collections 
-SET name = $1, description = $2 
-WHERE id = $3
+SET name = $1, description = $2, variables = $3, skip_history = $4 
+WHERE id = $5
 `
 
 type UpdateCollectionParams struct {
 	Name        string      `json:"name"`
 	Description pgtype.Text `json:"description"`
+	Variables   string      `json:"variables"`
+	SkipHistory bool        `json:"skip_history"`
 	ID          int32       `json:"id"`
 }
 
 func (q *Queries) UpdateCollection(ctx context.Context, arg UpdateCollectionParams) error {
-	_, err := q.db.Exec(ctx, updateCollection, arg.Name, arg.Description, arg.ID)
+	_, err := q.db.Exec(ctx, updateCollection,
+		arg.Name,
+		arg.Description,
+		arg.Variables,
+		arg.SkipHistory,
+		arg.ID,
+	)
 	return err
 }
@@ -12,37 +12,63 @@ import (
 )
 
 const addToHistory = `-- name: AddToHistory :one
-INSERT INTO history (url, method, status_code, duration_ms, timestamp) 
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, url, method, status_code, duration_ms, timestamp
+INSERT INTO history (
+    url, method, status_code, duration_ms, timestamp, name,
+    request_headers, request_body, request_settings, request_auth,
+    response_headers, response_body, response_size, error, timing, request_body_options,
+    request_params, environment, request_template
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+RETURNING id
 `
 
 type AddToHistoryParams struct {
-	Url        string      `json:"url"`
-	Method     string      `json:"method"`
-	StatusCode pgtype.Int4 `json:"status_code"`
-	DurationMs pgtype.Int8 `json:"duration_ms"`
-	Timestamp  int64       `json:"timestamp"`
+	Url                string      `json:"url"`
+	Method             string      `json:"method"`
+	StatusCode         pgtype.Int4 `json:"status_code"`
+	DurationMs         pgtype.Int8 `json:"duration_ms"`
+	Timestamp          int64       `json:"timestamp"`
+	Name               string      `json:"name"`
+	RequestHeaders     string      `json:"request_headers"`
+	RequestBody        string      `json:"request_body"`
+	RequestSettings    string      `json:"request_settings"`
+	RequestAuth        string      `json:"request_auth"`
+	ResponseHeaders    string      `json:"response_headers"`
+	ResponseBody       string      `json:"response_body"`
+	ResponseSize       int64       `json:"response_size"`
+	Error              string      `json:"error"`
+	Timing             string      `json:"timing"`
+	RequestBodyOptions string      `json:"request_body_options"`
+	RequestParams      string      `json:"request_params"`
+	Environment        string      `json:"environment"`
+	RequestTemplate    string      `json:"request_template"`
 }
 
-func (q *Queries) AddToHistory(ctx context.Context, arg AddToHistoryParams) (*History, error) {
+func (q *Queries) AddToHistory(ctx context.Context, arg AddToHistoryParams) (int32, error) {
 	row := q.db.QueryRow(ctx, addToHistory,
 		arg.Url,
 		arg.Method,
 		arg.StatusCode,
 		arg.DurationMs,
 		arg.Timestamp,
+		arg.Name,
+		arg.RequestHeaders,
+		arg.RequestBody,
+		arg.RequestSettings,
+		arg.RequestAuth,
+		arg.ResponseHeaders,
+		arg.ResponseBody,
+		arg.ResponseSize,
+		arg.Error,
+		arg.Timing,
+		arg.RequestBodyOptions,
+		arg.RequestParams,
+		arg.Environment,
+		arg.RequestTemplate,
 	)
-	var i History
-	err := row.Scan(
-		&i.ID,
-		&i.Url,
-		&i.Method,
-		&i.StatusCode,
-		&i.DurationMs,
-		&i.Timestamp,
-	)
-	return &i, err
+	var id int32
+	err := row.Scan(&id)
+	return id, err
 }
 
 const clearHistory = `-- name: ClearHistory :exec
@@ -54,32 +80,43 @@ func (q *Queries) ClearHistory(ctx context.Context) error {
 	return err
 }
 
-const deleteHistoryEntry = `-- name: DeleteHistoryEntry :exec
-DELETE FROM history 
-WHERE id = $1
+const deleteHistoryBefore = `-- name: DeleteHistoryBefore :exec
+DELETE FROM history
+WHERE timestamp < $1
 `
 
-func (q *Queries) DeleteHistoryEntry(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteHistoryEntry, id)
+func (q *Queries) DeleteHistoryBefore(ctx context.Context, timestamp int64) error {
+	_, err := q.db.Exec(ctx, deleteHistoryBefore, timestamp)
 	return err
 }
 
 const getHistory = `-- name: GetHistory :many
-SELECT id, url, method, status_code, duration_ms, timestamp 
+SELECT id, url, method, status_code, duration_ms, timestamp, name, error
 FROM history 
 ORDER BY timestamp DESC 
 LIMIT $1
 `
 
-func (q *Queries) GetHistory(ctx context.Context, limit int32) ([]*History, error) {
+type GetHistoryRow struct {
+	ID         int32       `json:"id"`
+	Url        string      `json:"url"`
+	Method     string      `json:"method"`
+	StatusCode pgtype.Int4 `json:"status_code"`
+	DurationMs pgtype.Int8 `json:"duration_ms"`
+	Timestamp  int64       `json:"timestamp"`
+	Name       string      `json:"name"`
+	Error      string      `json:"error"`
+}
+
+func (q *Queries) GetHistory(ctx context.Context, limit int32) ([]*GetHistoryRow, error) {
 	rows, err := q.db.Query(ctx, getHistory, limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []*History{}
+	items := []*GetHistoryRow{}
 	for rows.Next() {
-		var i History
+		var i GetHistoryRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Url,
@@ -87,6 +124,8 @@ func (q *Queries) GetHistory(ctx context.Context, limit int32) ([]*History, erro
 			&i.StatusCode,
 			&i.DurationMs,
 			&i.Timestamp,
+			&i.Name,
+			&i.Error,
 		); err != nil {
 			return nil, err
 		}
@@ -99,7 +138,10 @@ func (q *Queries) GetHistory(ctx context.Context, limit int32) ([]*History, erro
 }
 
 const getHistoryByID = `-- name: GetHistoryByID :one
-SELECT id, url, method, status_code, duration_ms, timestamp 
+SELECT id, url, method, status_code, duration_ms, timestamp, name,
+       request_headers, request_body, request_settings, request_auth,
+       response_headers, response_body, response_size, error, timing, request_body_options,
+       request_params, environment, request_template
 FROM history 
 WHERE id = $1
 `
@@ -114,6 +156,91 @@ func (q *Queries) GetHistoryByID(ctx context.Context, id int32) (*History, error
 		&i.StatusCode,
 		&i.DurationMs,
 		&i.Timestamp,
+		&i.Name,
+		&i.RequestHeaders,
+		&i.RequestBody,
+		&i.RequestSettings,
+		&i.RequestAuth,
+		&i.ResponseHeaders,
+		&i.ResponseBody,
+		&i.ResponseSize,
+		&i.Error,
+		&i.Timing,
+		&i.RequestBodyOptions,
+		&i.RequestParams,
+		&i.Environment,
+		&i.RequestTemplate,
 	)
 	return &i, err
 }
+
+const searchHistory = `-- name: SearchHistory :many
+SELECT id, url, method, status_code, duration_ms, timestamp, name, error
+FROM history
+WHERE url ILIKE '%' || $1::text || '%'
+   OR name ILIKE '%' || $1::text || '%'
+   OR error ILIKE '%' || $1::text || '%'
+   OR method = UPPER($1::text)
+   OR CAST(status_code AS TEXT) = $1::text
+ORDER BY timestamp DESC
+LIMIT $2
+`
+
+type SearchHistoryParams struct {
+	Query    string `json:"query"`
+	MaxItems int32  `json:"max_items"`
+}
+
+type SearchHistoryRow struct {
+	ID         int32       `json:"id"`
+	Url        string      `json:"url"`
+	Method     string      `json:"method"`
+	StatusCode pgtype.Int4 `json:"status_code"`
+	DurationMs pgtype.Int8 `json:"duration_ms"`
+	Timestamp  int64       `json:"timestamp"`
+	Name       string      `json:"name"`
+	Error      string      `json:"error"`
+}
+
+func (q *Queries) SearchHistory(ctx context.Context, arg SearchHistoryParams) ([]*SearchHistoryRow, error) {
+	rows, err := q.db.Query(ctx, searchHistory, arg.Query, arg.MaxItems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []*SearchHistoryRow{}
+	for rows.Next() {
+		var i SearchHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.Method,
+			&i.StatusCode,
+			&i.DurationMs,
+			&i.Timestamp,
+			&i.Name,
+			&i.Error,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const trimHistory = `-- name: TrimHistory :exec
+DELETE FROM history
+WHERE id NOT IN (
+    SELECT id FROM history
+    ORDER BY timestamp DESC, id DESC
+    LIMIT $1
+)
+`
+
+func (q *Queries) TrimHistory(ctx context.Context, limit int32) error {
+	_, err := q.db.Exec(ctx, trimHistory, limit)
+	return err
+}
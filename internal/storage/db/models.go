@@ -12,15 +12,44 @@ type Collection struct {
 	ID          int32       `json:"id"`
 	Name        string      `json:"name"`
 	Description pgtype.Text `json:"description"`
+	Variables   string      `json:"variables"`
+	SkipHistory bool        `json:"skip_history"`
+}
+
+type Environment struct {
+	ID        int32  `json:"id"`
+	Name      string `json:"name"`
+	Variables string `json:"variables"`
+}
+
+type Folder struct {
+	ID           int32       `json:"id"`
+	CollectionID int32       `json:"collection_id"`
+	ParentID     pgtype.Int4 `json:"parent_id"`
+	Name         string      `json:"name"`
 }
 
 type History struct {
-	ID         int32       `json:"id"`
-	Url        string      `json:"url"`
-	Method     string      `json:"method"`
-	StatusCode pgtype.Int4 `json:"status_code"`
-	DurationMs pgtype.Int8 `json:"duration_ms"`
-	Timestamp  int64       `json:"timestamp"`
+	ID                 int32       `json:"id"`
+	Url                string      `json:"url"`
+	Method             string      `json:"method"`
+	StatusCode         pgtype.Int4 `json:"status_code"`
+	DurationMs         pgtype.Int8 `json:"duration_ms"`
+	Timestamp          int64       `json:"timestamp"`
+	Name               string      `json:"name"`
+	RequestHeaders     string      `json:"request_headers"`
+	RequestBody        string      `json:"request_body"`
+	RequestSettings    string      `json:"request_settings"`
+	RequestAuth        string      `json:"request_auth"`
+	ResponseHeaders    string      `json:"response_headers"`
+	ResponseBody       string      `json:"response_body"`
+	ResponseSize       int64       `json:"response_size"`
+	Error              string      `json:"error"`
+	Timing             string      `json:"timing"`
+	RequestBodyOptions string      `json:"request_body_options"`
+	RequestParams      string      `json:"request_params"`
+	Environment        string      `json:"environment"`
+	RequestTemplate    string      `json:"request_template"`
 }
 
 type Request struct {
@@ -31,4 +60,10 @@ type Request struct {
 	Headers      pgtype.Text `json:"headers"`
 	Body         pgtype.Text `json:"body"`
 	CollectionID pgtype.Int4 `json:"collection_id"`
+	Settings     string      `json:"settings"`
+	Auth         string      `json:"auth"`
+	FolderID     pgtype.Int4 `json:"folder_id"`
+	Filter       string      `json:"filter"`
+	Params       string      `json:"params"`
+	BodyOptions  string      `json:"body_options"`
 }
@@ -12,9 +12,9 @@ import (
 )
 
 const createRequest = `-- name: CreateRequest :one
-INSERT INTO requests (name, url, method, headers, body, collection_id) 
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, name, url, method, headers, body, collection_id
+INSERT INTO requests (name, url, method, headers, body, collection_id, folder_id, settings, auth, filter, params, body_options) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, name, url, method, headers, body, collection_id, settings, auth, folder_id, filter, params, body_options
 `
 
 type CreateRequestParams struct {
@@ -24,6 +24,12 @@ type CreateRequestParams struct {
 	Headers      pgtype.Text `json:"headers"`
 	Body         pgtype.Text `json:"body"`
 	CollectionID pgtype.Int4 `json:"collection_id"`
+	FolderID     pgtype.Int4 `json:"folder_id"`
+	Settings     string      `json:"settings"`
+	Auth         string      `json:"auth"`
+	Filter       string      `json:"filter"`
+	Params       string      `json:"params"`
+	BodyOptions  string      `json:"body_options"`
 }
 
 func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (*Request, error) {
@@ -34,6 +40,12 @@ func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (*
 		arg.Headers,
 		arg.Body,
 		arg.CollectionID,
+		arg.FolderID,
+		arg.Settings,
+		arg.Auth,
+		arg.Filter,
+		arg.Params,
+		arg.BodyOptions,
 	)
 	var i Request
 	err := row.Scan(
@@ -44,6 +56,12 @@ func (q *Queries) CreateRequest(ctx context.Context, arg CreateRequestParams) (*
 		&i.Headers,
 		&i.Body,
 		&i.CollectionID,
+		&i.Settings,
+		&i.Auth,
+		&i.FolderID,
+		&i.Filter,
+		&i.Params,
+		&i.BodyOptions,
 	)
 	return &i, err
 }
@@ -58,8 +76,18 @@ func (q *Queries) DeleteRequest(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteRequestsByCollectionID = `-- name: DeleteRequestsByCollectionID :exec
+DELETE FROM requests 
+WHERE collection_id = $1
+`
+
+func (q *Queries) DeleteRequestsByCollectionID(ctx context.Context, collectionID pgtype.Int4) error {
+	_, err := q.db.Exec(ctx, deleteRequestsByCollectionID, collectionID)
+	return err
+}
+
 const getAllRequests = `-- name: GetAllRequests :many
-SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
 FROM requests 
 ORDER BY name
 `
@@ -72,6 +100,12 @@ type GetAllRequestsRow struct {
 	Headers      pgtype.Text `json:"headers"`
 	Body         pgtype.Text `json:"body"`
 	CollectionID int32       `json:"collection_id"`
+	FolderID     pgtype.Int4 `json:"folder_id"`
+	Settings     string      `json:"settings"`
+	Auth         string      `json:"auth"`
+	Filter       string      `json:"filter"`
+	Params       string      `json:"params"`
+	BodyOptions  string      `json:"body_options"`
 }
 
 func (q *Queries) GetAllRequests(ctx context.Context) ([]*GetAllRequestsRow, error) {
@@ -91,6 +125,12 @@ func (q *Queries) GetAllRequests(ctx context.Context) ([]*GetAllRequestsRow, err
 			&i.Headers,
 			&i.Body,
 			&i.CollectionID,
+			&i.FolderID,
+			&i.Settings,
+			&i.Auth,
+			&i.Filter,
+			&i.Params,
+			&i.BodyOptions,
 		); err != nil {
 			return nil, err
 		}
@@ -103,7 +143,7 @@ func (q *Queries) GetAllRequests(ctx context.Context) ([]*GetAllRequestsRow, err
 }
 
 const getRequestByID = `-- name: GetRequestByID :one
-SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
 FROM requests 
 WHERE id = $1
 `
@@ -116,6 +156,12 @@ type GetRequestByIDRow struct {
 	Headers      pgtype.Text `json:"headers"`
 	Body         pgtype.Text `json:"body"`
 	CollectionID int32       `json:"collection_id"`
+	FolderID     pgtype.Int4 `json:"folder_id"`
+	Settings     string      `json:"settings"`
+	Auth         string      `json:"auth"`
+	Filter       string      `json:"filter"`
+	Params       string      `json:"params"`
+	BodyOptions  string      `json:"body_options"`
 }
 
 func (q *Queries) GetRequestByID(ctx context.Context, id int32) (*GetRequestByIDRow, error) {
@@ -129,12 +175,18 @@ func (q *Queries) GetRequestByID(ctx context.Context, id int32) (*GetRequestByID
 		&i.Headers,
 		&i.Body,
 		&i.CollectionID,
+		&i.FolderID,
+		&i.Settings,
+		&i.Auth,
+		&i.Filter,
+		&i.Params,
+		&i.BodyOptions,
 	)
 	return &i, err
 }
 
 const getRequestsByCollectionID = `-- name: GetRequestsByCollectionID :many
-SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
 FROM requests 
 WHERE collection_id = $1
 ORDER BY name
@@ -148,6 +200,12 @@ type GetRequestsByCollectionIDRow struct {
 	Headers      pgtype.Text `json:"headers"`
 	Body         pgtype.Text `json:"body"`
 	CollectionID int32       `json:"collection_id"`
+	FolderID     pgtype.Int4 `json:"folder_id"`
+	Settings     string      `json:"settings"`
+	Auth         string      `json:"auth"`
+	Filter       string      `json:"filter"`
+	Params       string      `json:"params"`
+	BodyOptions  string      `json:"body_options"`
 }
 
 func (q *Queries) GetRequestsByCollectionID(ctx context.Context, collectionID pgtype.Int4) ([]*GetRequestsByCollectionIDRow, error) {
@@ -167,6 +225,12 @@ func (q *Queries) GetRequestsByCollectionID(ctx context.Context, collectionID pg
 			&i.Headers,
 			&i.Body,
 			&i.CollectionID,
+			&i.FolderID,
+			&i.Settings,
+			&i.Auth,
+			&i.Filter,
+			&i.Params,
+			&i.BodyOptions,
 		); err != nil {
 			return nil, err
 		}
@@ -180,8 +244,8 @@ func (q *Queries) GetRequestsByCollectionID(ctx context.Context, collectionID pg
 
 const updateRequest = `-- name: UpdateRequest :exec
 UPDATE requests 
-SET name = $1, url = $2, method = $3, headers = $4, body = $5, collection_id = $6 
-WHERE id = $7
+SET name = $1, url = $2, method = $3, headers = $4, body = $5, collection_id = $6, folder_id = $7, settings = $8, auth = $9, filter = $10, params = $11, body_options = $12 
+WHERE id = $13
 `
 
 type UpdateRequestParams struct {
@@ -191,6 +255,12 @@ type UpdateRequestParams struct {
 	Headers      pgtype.Text `json:"headers"`
 	Body         pgtype.Text `json:"body"`
 	CollectionID pgtype.Int4 `json:"collection_id"`
+	FolderID     pgtype.Int4 `json:"folder_id"`
+	Settings     string      `json:"settings"`
+	Auth         string      `json:"auth"`
+	Filter       string      `json:"filter"`
+	Params       string      `json:"params"`
+	BodyOptions  string      `json:"body_options"`
 	ID           int32       `json:"id"`
 }
 
@@ -202,7 +272,29 @@ func (q *Queries) UpdateRequest(ctx context.Context, arg UpdateRequestParams) er
 		arg.Headers,
 		arg.Body,
 		arg.CollectionID,
+		arg.FolderID,
+		arg.Settings,
+		arg.Auth,
+		arg.Filter,
+		arg.Params,
+		arg.BodyOptions,
 		arg.ID,
 	)
 	return err
 }
+
+const updateRequestFilter = `-- name: UpdateRequestFilter :exec
+UPDATE requests 
+SET filter = $1 
+WHERE id = $2
+`
+
+type UpdateRequestFilterParams struct {
+	Filter string `json:"filter"`
+	ID     int32  `json:"id"`
+}
+
+func (q *Queries) UpdateRequestFilter(ctx context.Context, arg UpdateRequestFilterParams) error {
+	_, err := q.db.Exec(ctx, updateRequestFilter, arg.Filter, arg.ID)
+	return err
+}
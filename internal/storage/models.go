@@ -1,16 +1,35 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"time"
+	"unicode/utf8"
 
 	"github.com/YashIIT0909/TRexT/internal/http"
 )
 
+// DefaultCollectionID is the collection created by the initial migration
+const DefaultCollectionID = 1
+
 // Collection represents a group of requests
 type Collection struct {
-	ID          int64  `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
+	ID          int64             `json:"id"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Variables   map[string]string `json:"variables"`    // overridden by the active environment
+	SkipHistory bool              `json:"skip_history"` // requests in the collection are never recorded in history
+}
+
+// Folder groups requests inside a collection, possibly inside another folder
+type Folder struct {
+	ID           int64  `json:"id"`
+	CollectionID int64  `json:"collection_id"`
+	ParentID     int64  `json:"parent_id"` // 0 for folders at the top of the collection
+	Name         string `json:"name"`
 }
 
 // SavedRequest represents a request stored in the database
@@ -19,31 +38,90 @@ type SavedRequest struct {
 	Name         string `json:"name"`
 	URL          string `json:"url"`
 	Method       string `json:"method"`
-	Headers      string `json:"headers"` // JSON-encoded headers
+	Headers      string `json:"headers"` // JSON-encoded list of headers
 	Body         string `json:"body"`
 	CollectionID int64  `json:"collection_id"`
+	FolderID     int64  `json:"folder_id"`    // 0 for requests at the top of the collection
+	Settings     string `json:"settings"`     // JSON-encoded network overrides
+	Auth         string `json:"auth"`         // JSON-encoded credentials
+	Filter       string `json:"filter"`       // jq or JSONPath filter last applied to the response
+	Params       string `json:"params"`       // JSON-encoded query and path parameters
+	BodyOptions  string `json:"body_options"` // JSON-encoded body type, form fields, body file and GraphQL operation
+}
+
+// bodyOptions are the parts of a request body stored apart from its text
+type bodyOptions struct {
+	Type    http.BodyType   `json:"type,omitempty"`
+	Form    []http.KeyValue `json:"form,omitempty"`
+	File    string          `json:"file,omitempty"`
+	GraphQL *http.GraphQL   `json:"graphql,omitempty"`
+}
+
+// encodeBodyOptions returns the JSON-encoded body options of a request
+func encodeBodyOptions(req *http.Request) string {
+	options := bodyOptions{Type: req.BodyType, Form: req.Form, File: req.BodyFile}
+	if req.GraphQL != (http.GraphQL{}) {
+		options.GraphQL = &req.GraphQL
+	}
+	data, _ := json.Marshal(options)
+	return string(data)
 }
 
 // ToHTTPRequest converts a SavedRequest to an http.Request
 func (sr *SavedRequest) ToHTTPRequest() *http.Request {
-	headers := make(map[string]string)
+	var headers http.Headers
 	if sr.Headers != "" {
 		_ = json.Unmarshal([]byte(sr.Headers), &headers)
 	}
 
+	var settings http.Settings
+	if sr.Settings != "" {
+		_ = json.Unmarshal([]byte(sr.Settings), &settings)
+	}
+
+	var auth http.Auth
+	if sr.Auth != "" {
+		_ = json.Unmarshal([]byte(sr.Auth), &auth)
+	}
+
+	var params http.Params
+	if sr.Params != "" {
+		_ = json.Unmarshal([]byte(sr.Params), &params)
+	}
+
+	var body bodyOptions
+	if sr.BodyOptions != "" {
+		_ = json.Unmarshal([]byte(sr.BodyOptions), &body)
+	}
+	var graphQL http.GraphQL
+	if body.GraphQL != nil {
+		graphQL = *body.GraphQL
+	}
+
 	return &http.Request{
-		ID:      sr.ID,
-		Name:    sr.Name,
-		Method:  sr.Method,
-		URL:     sr.URL,
-		Headers: headers,
-		Body:    sr.Body,
+		ID:       sr.ID,
+		Name:     sr.Name,
+		Method:   sr.Method,
+		URL:      sr.URL,
+		Headers:  headers,
+		Params:   params,
+		Body:     sr.Body,
+		BodyType: body.Type,
+		Form:     body.Form,
+		BodyFile: body.File,
+		GraphQL:  graphQL,
+		Auth:     auth,
+		Settings: settings,
+		Filter:   sr.Filter,
 	}
 }
 
 // FromHTTPRequest creates a SavedRequest from an http.Request
 func FromHTTPRequest(req *http.Request, collectionID int64) *SavedRequest {
 	headersJSON, _ := json.Marshal(req.Headers)
+	settingsJSON, _ := json.Marshal(req.Settings)
+	authJSON, _ := json.Marshal(req.Auth)
+	paramsJSON, _ := json.Marshal(req.Params)
 
 	return &SavedRequest{
 		ID:           req.ID,
@@ -53,15 +131,177 @@ func FromHTTPRequest(req *http.Request, collectionID int64) *SavedRequest {
 		Headers:      string(headersJSON),
 		Body:         req.Body,
 		CollectionID: collectionID,
+		Settings:     string(settingsJSON),
+		Auth:         string(authJSON),
+		Filter:       req.Filter,
+		Params:       string(paramsJSON),
+		BodyOptions:  encodeBodyOptions(req),
 	}
 }
 
-// HistoryEntry represents a request in history
+// Environment represents a named set of variables (e.g. dev, staging, prod)
+type Environment struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Variables map[string]string `json:"variables"`
+}
+
+// HistoryEntry represents a request in history, as it was sent after
+// {{variable}} substitution, together with the response it got. The request
+// as written is kept too, for saving it back to a collection. Entries listed by
+// GetHistory and SearchHistory only carry the summary fields.
 type HistoryEntry struct {
-	ID         int64  `json:"id"`
-	URL        string `json:"url"`
-	Method     string `json:"method"`
-	StatusCode int    `json:"status_code"`
-	Duration   int64  `json:"duration_ms"`
-	Timestamp  int64  `json:"timestamp"`
+	ID                 int64  `json:"id"`
+	Name               string `json:"name"`
+	URL                string `json:"url"`
+	Method             string `json:"method"`
+	StatusCode         int    `json:"status_code"`
+	Duration           int64  `json:"duration_ms"`
+	Timestamp          int64  `json:"timestamp"`
+	RequestHeaders     string `json:"request_headers"` // JSON-encoded list of headers
+	RequestBody        string `json:"request_body"`
+	RequestSettings    string `json:"request_settings"`     // JSON-encoded network overrides
+	RequestAuth        string `json:"request_auth"`         // JSON-encoded credentials
+	RequestBodyOptions string `json:"request_body_options"` // JSON-encoded body type, form fields, body file and GraphQL operation
+	RequestParams      string `json:"request_params"`       // JSON-encoded query and path parameters
+	Environment        string `json:"environment"`          // environment the request was resolved against
+	RequestTemplate    string `json:"request_template"`     // JSON-encoded request as written, with its {{variables}}
+	ResponseHeaders    string `json:"response_headers"`     // JSON-encoded headers
+	ResponseBody       string `json:"response_body"`        // empty for binary bodies, capped at History.MaxBodySize
+	ResponseSize       int64  `json:"response_size"`        // size of the whole response body
+	Error              string `json:"error"`
+	Timing             string `json:"timing"` // JSON-encoded timing breakdown
+}
+
+// NewHistoryEntry records a request as sent, the template it was resolved
+// from and its response. Auth secrets are left out of both, keeping the auth
+// type and any {{variables}} the template takes them from. Response bodies
+// are cut to maxBodySize bytes; binary ones are not kept.
+func NewHistoryEntry(req, template *http.Request, resp *http.Response, maxBodySize int) *HistoryEntry {
+	headersJSON, _ := json.Marshal(req.Headers)
+	settingsJSON, _ := json.Marshal(req.Settings)
+	authJSON, _ := json.Marshal(req.Auth.Redacted())
+	paramsJSON, _ := json.Marshal(req.Params)
+	redacted := *template
+	redacted.Auth = template.Auth.Redacted()
+	templateJSON, _ := json.Marshal(&redacted)
+	responseHeadersJSON, _ := json.Marshal(resp.Headers)
+	timingJSON, _ := json.Marshal(resp.Timing)
+
+	entry := &HistoryEntry{
+		Name:               req.Name,
+		URL:                req.URL,
+		Method:             req.Method,
+		StatusCode:         resp.StatusCode,
+		Duration:           resp.Duration.Milliseconds(),
+		Timestamp:          time.Now().Unix(),
+		RequestHeaders:     string(headersJSON),
+		RequestBody:        req.Body,
+		RequestSettings:    string(settingsJSON),
+		RequestAuth:        string(authJSON),
+		RequestBodyOptions: encodeBodyOptions(req),
+		RequestParams:      string(paramsJSON),
+		Environment:        req.Environment,
+		RequestTemplate:    string(templateJSON),
+		ResponseHeaders:    string(responseHeadersJSON),
+		ResponseBody:       textBody(resp.Body, maxBodySize),
+		ResponseSize:       resp.Size,
+		Timing:             string(timingJSON),
+	}
+	if resp.Error != nil {
+		entry.Error = resp.Error.Error()
+	}
+	return entry
+}
+
+// textBody returns up to maxSize bytes of body as text, or "" if it is binary
+func textBody(body []byte, maxSize int) string {
+	if len(body) > maxSize {
+		body = body[:maxSize]
+		// Drop a character cut in half
+		for i := 0; i < utf8.UTFMax && len(body) > 0 && !utf8.Valid(body); i++ {
+			body = body[:len(body)-1]
+		}
+	}
+	if !utf8.Valid(body) || bytes.IndexByte(body, 0) >= 0 {
+		return ""
+	}
+	return string(body)
+}
+
+// ToHTTPRequest converts a HistoryEntry to an http.Request
+func (e *HistoryEntry) ToHTTPRequest() *http.Request {
+	sr := &SavedRequest{
+		Name:        e.Name,
+		URL:         e.URL,
+		Method:      e.Method,
+		Headers:     e.RequestHeaders,
+		Body:        e.RequestBody,
+		Settings:    e.RequestSettings,
+		Auth:        e.RequestAuth,
+		BodyOptions: e.RequestBodyOptions,
+		Params:      e.RequestParams,
+	}
+	req := sr.ToHTTPRequest()
+	req.Environment = e.Environment
+	// Secrets aren't recorded, so the credentials as written stand in and
+	// their {{variables}} are resolved again when the request is sent
+	if template, ok := e.template(); ok {
+		req.Auth = template.Auth
+	}
+	return req
+}
+
+// Template returns the request as it was written, with its {{variables}}.
+// Entries recorded before templates were kept give the request as sent.
+func (e *HistoryEntry) Template() *http.Request {
+	if template, ok := e.template(); ok {
+		return template
+	}
+	return e.ToHTTPRequest()
+}
+
+// template decodes the request as written, if the entry has it
+func (e *HistoryEntry) template() (*http.Request, bool) {
+	if e.RequestTemplate == "" {
+		return nil, false
+	}
+	var template http.Request
+	if err := json.Unmarshal([]byte(e.RequestTemplate), &template); err != nil {
+		return nil, false
+	}
+	return &template, true
+}
+
+// ToHTTPResponse converts a HistoryEntry to the http.Response it recorded
+func (e *HistoryEntry) ToHTTPResponse() *http.Response {
+	var headers map[string][]string
+	if e.ResponseHeaders != "" {
+		_ = json.Unmarshal([]byte(e.ResponseHeaders), &headers)
+	}
+	var timing http.Timing
+	if e.Timing != "" {
+		_ = json.Unmarshal([]byte(e.Timing), &timing)
+	}
+
+	resp := &http.Response{
+		StatusCode: e.StatusCode,
+		Headers:    headers,
+		Body:       []byte(e.ResponseBody),
+		Duration:   time.Duration(e.Duration) * time.Millisecond,
+		Size:       e.ResponseSize,
+		Timing:     timing,
+	}
+	if e.StatusCode != 0 {
+		resp.Status = fmt.Sprintf("%d %s", e.StatusCode, nethttp.StatusText(e.StatusCode))
+	}
+	if e.Error != "" {
+		resp.Error = errors.New(e.Error)
+	}
+	return resp
+}
+
+// IsTruncated returns true if only part of the response body was recorded
+func (e *HistoryEntry) IsTruncated() bool {
+	return int64(len(e.ResponseBody)) < e.ResponseSize
 }
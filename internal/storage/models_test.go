@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/YashIIT0909/TRexT/internal/http"
+)
+
+func TestHistoryEntryRequest(t *testing.T) {
+	template := &http.Request{
+		Method: "GET",
+		URL:    "https://{{host}}/users/:id?page=2",
+		Headers: http.Headers{
+			{Key: "Authorization", Value: "Bearer {{token}}"},
+		},
+		Params: http.Params{
+			Query: []http.KeyValue{
+				{Key: "page", Value: "2"},
+				{Key: "debug", Value: "1", Disabled: true},
+			},
+			Path: []http.KeyValue{{Key: "id", Value: "{{uid}}", Description: "user"}},
+		},
+	}
+	req, err := template.Resolve(map[string]string{"host": "api.example.com", "token": "secret", "uid": "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Environment = "staging"
+
+	entry := NewHistoryEntry(req, template, &http.Response{StatusCode: 200}, 1024)
+	got := entry.ToHTTPRequest()
+
+	if got.URL != "https://api.example.com/users/42?page=2" {
+		t.Errorf("URL = %q, want the URL that was sent", got.URL)
+	}
+	if got.Headers.Get("Authorization") != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the header that was sent", got.Headers.Get("Authorization"))
+	}
+	if !reflect.DeepEqual(got.Params, req.Params) {
+		t.Errorf("Params = %+v, want %+v", got.Params, req.Params)
+	}
+	if got.Environment != "staging" {
+		t.Errorf("Environment = %q, want %q", got.Environment, "staging")
+	}
+}
+
+func TestHistoryEntryTemplate(t *testing.T) {
+	template := &http.Request{
+		Name:    "user",
+		Method:  "GET",
+		URL:     "https://{{host}}/users/:id",
+		Headers: http.Headers{{Key: "X-Trace", Value: "{{trace}}"}},
+		Params:  http.Params{Path: []http.KeyValue{{Key: "id", Value: "{{uid}}"}}},
+		Auth:    http.Auth{Type: http.AuthBearer, Token: "{{token}}"},
+	}
+	req, err := template.Resolve(map[string]string{"host": "api.example.com", "trace": "1", "uid": "42", "token": "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := NewHistoryEntry(req, template, &http.Response{StatusCode: 200}, 1024)
+	if got := entry.Template(); !reflect.DeepEqual(got, template) {
+		t.Errorf("Template() = %+v, want %+v", got, template)
+	}
+
+	// Entries recorded without a template give the request as sent
+	entry.RequestTemplate = ""
+	if got := entry.Template(); got.URL != req.URL {
+		t.Errorf("Template() of an entry without one has URL %q, want %q", got.URL, req.URL)
+	}
+}
+
+func TestHistoryEntryRedactsCredentials(t *testing.T) {
+	tests := []struct {
+		name string
+		auth http.Auth
+	}{
+		{"basic", http.Auth{Type: http.AuthBasic, Username: "user", Password: "hunter2"}},
+		{"bearer", http.Auth{Type: http.AuthBearer, Token: "literal-token"}},
+		{"bearer variable", http.Auth{Type: http.AuthBearer, Token: "{{token}}"}},
+		{"api key", http.Auth{Type: http.AuthAPIKey, Key: "X-Key", Value: "key-{{token}}", In: http.APIKeyInHeader}},
+		{"oauth2", http.Auth{Type: http.AuthOAuth2, GrantType: "client_credentials", ClientID: "app", ClientSecret: "{{token}}"}},
+	}
+	secrets := []string{"hunter2", "literal-token", "s3cr3t"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := &http.Request{Method: "GET", URL: "https://example.com", Auth: tt.auth}
+			req, err := template.Resolve(map[string]string{"token": "s3cr3t"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			entry := NewHistoryEntry(req, template, &http.Response{StatusCode: 200}, 1024)
+			data, err := json.Marshal(entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, secret := range secrets {
+				if strings.Contains(string(data), secret) {
+					t.Errorf("history entry persists %q: %s", secret, data)
+				}
+			}
+
+			if got := entry.ToHTTPRequest().Auth; got.Type != tt.auth.Type {
+				t.Errorf("auth type = %q, want %q", got.Type, tt.auth.Type)
+			}
+			// Secrets taken from variables can still be resolved on replay
+			if got, want := entry.ToHTTPRequest().Auth, tt.auth.Redacted(); got != want {
+				t.Errorf("replayed auth = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,573 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/YashIIT0909/TRexT/internal/storage/sqlitedb"
+	sqliteschemas "github.com/YashIIT0909/TRexT/sql/sqlite/schemas"
+	"github.com/pressly/goose/v3"
+	_ "modernc.org/sqlite" // registers the pure-Go "sqlite" driver
+)
+
+// SQLiteStore wraps an embedded SQLite database and its sqlc queries
+type SQLiteStore struct {
+	db      *sql.DB
+	queries *sqlitedb.Queries
+}
+
+// NewSQLiteStore opens or creates the database file at path and runs migrations
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "busy_timeout(5000)")
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+
+	sqlDB, err := sql.Open("sqlite", "file:"+path+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	// A single connection serialises writes, which SQLite requires anyway
+	sqlDB.SetMaxOpenConns(1)
+
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+
+	goose.SetBaseFS(sqliteschemas.EmbedMigrations)
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+	if err := goose.Up(sqlDB, "."); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	return &SQLiteStore{
+		db:      sqlDB,
+		queries: sqlitedb.New(sqlDB),
+	}, nil
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() {
+	_ = s.db.Close()
+}
+
+// SaveRequest saves or updates a request
+func (s *SQLiteStore) SaveRequest(req *SavedRequest) error {
+	ctx := context.Background()
+
+	collectionID := sql.NullInt64{Int64: req.CollectionID, Valid: req.CollectionID > 0}
+	if req.ID == 0 {
+		result, err := s.queries.CreateRequest(ctx, sqlitedb.CreateRequestParams{
+			Name:         req.Name,
+			Url:          req.URL,
+			Method:       req.Method,
+			Headers:      sql.NullString{String: req.Headers, Valid: true},
+			Body:         sql.NullString{String: req.Body, Valid: true},
+			CollectionID: collectionID,
+			FolderID:     sql.NullInt64{Int64: req.FolderID, Valid: req.FolderID > 0},
+			Settings:     req.Settings,
+			Auth:         req.Auth,
+			Filter:       req.Filter,
+			Params:       req.Params,
+			BodyOptions:  req.BodyOptions,
+		})
+		if err != nil {
+			return err
+		}
+		req.ID = result.ID
+		return nil
+	}
+
+	return s.queries.UpdateRequest(ctx, sqlitedb.UpdateRequestParams{
+		Name:         req.Name,
+		Url:          req.URL,
+		Method:       req.Method,
+		Headers:      sql.NullString{String: req.Headers, Valid: true},
+		Body:         sql.NullString{String: req.Body, Valid: true},
+		CollectionID: collectionID,
+		FolderID:     sql.NullInt64{Int64: req.FolderID, Valid: req.FolderID > 0},
+		Settings:     req.Settings,
+		Auth:         req.Auth,
+		Filter:       req.Filter,
+		Params:       req.Params,
+		BodyOptions:  req.BodyOptions,
+		ID:           req.ID,
+	})
+}
+
+// GetAllRequests returns all saved requests
+func (s *SQLiteStore) GetAllRequests() ([]*SavedRequest, error) {
+	rows, err := s.queries.GetAllRequests(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]*SavedRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = &SavedRequest{
+			ID:           row.ID,
+			Name:         row.Name,
+			URL:          row.Url,
+			Method:       row.Method,
+			Headers:      row.Headers.String,
+			Body:         row.Body.String,
+			CollectionID: row.CollectionID,
+			FolderID:     row.FolderID.Int64,
+			Settings:     row.Settings,
+			Auth:         row.Auth,
+			Filter:       row.Filter,
+			Params:       row.Params,
+			BodyOptions:  row.BodyOptions,
+		}
+	}
+	return requests, nil
+}
+
+// GetRequestsByCollection returns the saved requests of a collection
+func (s *SQLiteStore) GetRequestsByCollection(collectionID int64) ([]*SavedRequest, error) {
+	rows, err := s.queries.GetRequestsByCollectionID(context.Background(), sql.NullInt64{Int64: collectionID, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]*SavedRequest, len(rows))
+	for i, row := range rows {
+		requests[i] = &SavedRequest{
+			ID:           row.ID,
+			Name:         row.Name,
+			URL:          row.Url,
+			Method:       row.Method,
+			Headers:      row.Headers.String,
+			Body:         row.Body.String,
+			CollectionID: row.CollectionID,
+			FolderID:     row.FolderID.Int64,
+			Settings:     row.Settings,
+			Auth:         row.Auth,
+			Filter:       row.Filter,
+			Params:       row.Params,
+			BodyOptions:  row.BodyOptions,
+		}
+	}
+	return requests, nil
+}
+
+// SaveRequestFilter stores the response filter of a saved request
+func (s *SQLiteStore) SaveRequestFilter(id int64, filter string) error {
+	return s.queries.UpdateRequestFilter(context.Background(), sqlitedb.UpdateRequestFilterParams{
+		Filter: filter,
+		ID:     id,
+	})
+}
+
+// DeleteRequest deletes a request by ID
+func (s *SQLiteStore) DeleteRequest(id int64) error {
+	return s.queries.DeleteRequest(context.Background(), id)
+}
+
+// GetCollections returns all collections
+func (s *SQLiteStore) GetCollections() ([]*Collection, error) {
+	rows, err := s.queries.GetCollections(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	collections := make([]*Collection, len(rows))
+	for i, row := range rows {
+		collections[i] = sqliteCollection(row)
+	}
+	return collections, nil
+}
+
+// sqliteCollection converts a collection row, decoding its variables
+func sqliteCollection(row *sqlitedb.Collection) *Collection {
+	variables := make(map[string]string)
+	_ = json.Unmarshal([]byte(row.Variables), &variables)
+	return &Collection{
+		ID:          row.ID,
+		Name:        row.Name,
+		Description: row.Description.String,
+		Variables:   variables,
+		SkipHistory: row.SkipHistory,
+	}
+}
+
+// SaveCollection saves or updates a collection
+func (s *SQLiteStore) SaveCollection(c *Collection) error {
+	return saveSQLiteCollection(context.Background(), s.queries, c)
+}
+
+func saveSQLiteCollection(ctx context.Context, queries *sqlitedb.Queries, c *Collection) error {
+	variablesJSON, err := json.Marshal(c.Variables)
+	if err != nil {
+		return err
+	}
+
+	if c.ID == 0 {
+		result, err := queries.CreateCollection(ctx, sqlitedb.CreateCollectionParams{
+			Name:        c.Name,
+			Description: sql.NullString{String: c.Description, Valid: true},
+			Variables:   string(variablesJSON),
+			SkipHistory: c.SkipHistory,
+		})
+		if err != nil {
+			return err
+		}
+		c.ID = result.ID
+		return nil
+	}
+
+	return queries.UpdateCollection(ctx, sqlitedb.UpdateCollectionParams{
+		Name:        c.Name,
+		Description: sql.NullString{String: c.Description, Valid: true},
+		Variables:   string(variablesJSON),
+		SkipHistory: c.SkipHistory,
+		ID:          c.ID,
+	})
+}
+
+// ImportCollection creates a collection with its folders and requests in a
+// single transaction. Folder IDs, parent IDs and the requests' folder IDs
+// refer to the given folders, parents first, and are replaced by stored IDs.
+func (s *SQLiteStore) ImportCollection(c *Collection, folders []*Folder, requests []*SavedRequest) error {
+	ctx := context.Background()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	c.ID = 0
+	if err := saveSQLiteCollection(ctx, queries, c); err != nil {
+		return err
+	}
+
+	folderIDs := make(map[int64]int64, len(folders))
+	for _, f := range folders {
+		result, err := queries.CreateFolder(ctx, sqlitedb.CreateFolderParams{
+			CollectionID: c.ID,
+			ParentID:     sql.NullInt64{Int64: folderIDs[f.ParentID], Valid: folderIDs[f.ParentID] > 0},
+			Name:         f.Name,
+		})
+		if err != nil {
+			return err
+		}
+		folderIDs[f.ID] = result.ID
+	}
+	for _, f := range folders {
+		f.ID, f.ParentID, f.CollectionID = folderIDs[f.ID], folderIDs[f.ParentID], c.ID
+	}
+
+	for _, req := range requests {
+		req.FolderID = folderIDs[req.FolderID]
+		result, err := queries.CreateRequest(ctx, sqlitedb.CreateRequestParams{
+			Name:         req.Name,
+			Url:          req.URL,
+			Method:       req.Method,
+			Headers:      sql.NullString{String: req.Headers, Valid: true},
+			Body:         sql.NullString{String: req.Body, Valid: true},
+			CollectionID: sql.NullInt64{Int64: c.ID, Valid: true},
+			FolderID:     sql.NullInt64{Int64: req.FolderID, Valid: req.FolderID > 0},
+			Settings:     req.Settings,
+			Auth:         req.Auth,
+			Filter:       req.Filter,
+			Params:       req.Params,
+			BodyOptions:  req.BodyOptions,
+		})
+		if err != nil {
+			return err
+		}
+		req.ID = result.ID
+		req.CollectionID = c.ID
+	}
+
+	return tx.Commit()
+}
+
+// DeleteCollection deletes a collection and its requests in a single transaction
+func (s *SQLiteStore) DeleteCollection(id int64) error {
+	ctx := context.Background()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	if err := queries.DeleteRequestsByCollectionID(ctx, sql.NullInt64{Int64: id, Valid: true}); err != nil {
+		return err
+	}
+	if err := queries.DeleteCollection(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetFolders returns the folders of all collections
+func (s *SQLiteStore) GetFolders() ([]*Folder, error) {
+	rows, err := s.queries.GetFolders(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]*Folder, len(rows))
+	for i, row := range rows {
+		folders[i] = &Folder{
+			ID:           row.ID,
+			CollectionID: row.CollectionID,
+			ParentID:     row.ParentID.Int64,
+			Name:         row.Name,
+		}
+	}
+	return folders, nil
+}
+
+// SaveFolder saves or renames a folder
+func (s *SQLiteStore) SaveFolder(f *Folder) error {
+	ctx := context.Background()
+	parentID := sql.NullInt64{Int64: f.ParentID, Valid: f.ParentID > 0}
+
+	if f.ID == 0 {
+		result, err := s.queries.CreateFolder(ctx, sqlitedb.CreateFolderParams{
+			CollectionID: f.CollectionID,
+			ParentID:     parentID,
+			Name:         f.Name,
+		})
+		if err != nil {
+			return err
+		}
+		f.ID = result.ID
+		return nil
+	}
+
+	return s.queries.UpdateFolder(ctx, sqlitedb.UpdateFolderParams{
+		ParentID: parentID,
+		Name:     f.Name,
+		ID:       f.ID,
+	})
+}
+
+// DeleteFolder deletes a folder with its subfolders and all their requests
+// in a single transaction
+func (s *SQLiteStore) DeleteFolder(id int64) error {
+	ctx := context.Background()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	queries := s.queries.WithTx(tx)
+	if err := queries.DeleteFolderRequests(ctx, id); err != nil {
+		return err
+	}
+	// Subfolders are removed by the parent_id cascade
+	if err := queries.DeleteFolder(ctx, id); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetEnvironments returns all environments
+func (s *SQLiteStore) GetEnvironments() ([]*Environment, error) {
+	rows, err := s.queries.GetEnvironments(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	environments := make([]*Environment, len(rows))
+	for i, row := range rows {
+		variables := make(map[string]string)
+		_ = json.Unmarshal([]byte(row.Variables), &variables)
+		environments[i] = &Environment{
+			ID:        row.ID,
+			Name:      row.Name,
+			Variables: variables,
+		}
+	}
+	return environments, nil
+}
+
+// SaveEnvironment saves or updates an environment
+func (s *SQLiteStore) SaveEnvironment(env *Environment) error {
+	ctx := context.Background()
+
+	variablesJSON, err := json.Marshal(env.Variables)
+	if err != nil {
+		return err
+	}
+
+	if env.ID == 0 {
+		result, err := s.queries.CreateEnvironment(ctx, sqlitedb.CreateEnvironmentParams{
+			Name:      env.Name,
+			Variables: string(variablesJSON),
+		})
+		if err != nil {
+			return err
+		}
+		env.ID = result.ID
+		return nil
+	}
+
+	return s.queries.UpdateEnvironment(ctx, sqlitedb.UpdateEnvironmentParams{
+		Name:      env.Name,
+		Variables: string(variablesJSON),
+		ID:        env.ID,
+	})
+}
+
+// DeleteEnvironment deletes an environment by ID
+func (s *SQLiteStore) DeleteEnvironment(id int64) error {
+	return s.queries.DeleteEnvironment(context.Background(), id)
+}
+
+// AddToHistory adds a request to history
+func (s *SQLiteStore) AddToHistory(entry *HistoryEntry) error {
+	id, err := s.queries.AddToHistory(context.Background(), sqlitedb.AddToHistoryParams{
+		Url:                entry.URL,
+		Method:             entry.Method,
+		StatusCode:         sql.NullInt64{Int64: int64(entry.StatusCode), Valid: entry.StatusCode != 0},
+		DurationMs:         sql.NullInt64{Int64: entry.Duration, Valid: true},
+		Timestamp:          entry.Timestamp,
+		Name:               entry.Name,
+		RequestHeaders:     entry.RequestHeaders,
+		RequestBody:        entry.RequestBody,
+		RequestSettings:    entry.RequestSettings,
+		RequestAuth:        entry.RequestAuth,
+		ResponseHeaders:    entry.ResponseHeaders,
+		ResponseBody:       entry.ResponseBody,
+		ResponseSize:       entry.ResponseSize,
+		Error:              entry.Error,
+		Timing:             entry.Timing,
+		RequestBodyOptions: entry.RequestBodyOptions,
+		RequestParams:      entry.RequestParams,
+		Environment:        entry.Environment,
+		RequestTemplate:    entry.RequestTemplate,
+	})
+	if err != nil {
+		return err
+	}
+	entry.ID = id
+	return nil
+}
+
+// GetHistory returns recent history entries
+func (s *SQLiteStore) GetHistory(limit int) ([]*HistoryEntry, error) {
+	rows, err := s.queries.GetHistory(context.Background(), int64(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*HistoryEntry, len(rows))
+	for i, row := range rows {
+		history[i] = &HistoryEntry{
+			ID:         row.ID,
+			Name:       row.Name,
+			URL:        row.Url,
+			Method:     row.Method,
+			StatusCode: int(row.StatusCode.Int64),
+			Duration:   row.DurationMs.Int64,
+			Timestamp:  row.Timestamp,
+			Error:      row.Error,
+		}
+	}
+	return history, nil
+}
+
+// SearchHistory returns recent history entries whose URL, name or error
+// contains query, or whose method or status code equals it
+func (s *SQLiteStore) SearchHistory(query string, limit int) ([]*HistoryEntry, error) {
+	rows, err := s.queries.SearchHistory(context.Background(), sqlitedb.SearchHistoryParams{
+		Query:    query,
+		MaxItems: int64(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*HistoryEntry, len(rows))
+	for i, row := range rows {
+		history[i] = &HistoryEntry{
+			ID:         row.ID,
+			Name:       row.Name,
+			URL:        row.Url,
+			Method:     row.Method,
+			StatusCode: int(row.StatusCode.Int64),
+			Duration:   row.DurationMs.Int64,
+			Timestamp:  row.Timestamp,
+			Error:      row.Error,
+		}
+	}
+	return history, nil
+}
+
+// GetHistoryEntry returns a history entry with the full request and response
+func (s *SQLiteStore) GetHistoryEntry(id int64) (*HistoryEntry, error) {
+	row, err := s.queries.GetHistoryByID(context.Background(), id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryEntry{
+		ID:                 row.ID,
+		Name:               row.Name,
+		URL:                row.Url,
+		Method:             row.Method,
+		StatusCode:         int(row.StatusCode.Int64),
+		Duration:           row.DurationMs.Int64,
+		Timestamp:          row.Timestamp,
+		RequestHeaders:     row.RequestHeaders,
+		RequestBody:        row.RequestBody,
+		RequestSettings:    row.RequestSettings,
+		RequestAuth:        row.RequestAuth,
+		ResponseHeaders:    row.ResponseHeaders,
+		ResponseBody:       row.ResponseBody,
+		ResponseSize:       row.ResponseSize,
+		Error:              row.Error,
+		Timing:             row.Timing,
+		RequestBodyOptions: row.RequestBodyOptions,
+		RequestParams:      row.RequestParams,
+		Environment:        row.Environment,
+		RequestTemplate:    row.RequestTemplate,
+	}, nil
+}
+
+// PruneHistory deletes all but the newest maxItems entries and those older
+// than maxAge. Zero disables either limit.
+func (s *SQLiteStore) PruneHistory(maxItems int, maxAge time.Duration) error {
+	ctx := context.Background()
+	if maxAge > 0 {
+		if err := s.queries.DeleteHistoryBefore(ctx, time.Now().Add(-maxAge).Unix()); err != nil {
+			return err
+		}
+	}
+	if maxItems > 0 {
+		return s.queries.TrimHistory(ctx, int64(maxItems))
+	}
+	return nil
+}
+
+// ClearHistory deletes all history entries
+func (s *SQLiteStore) ClearHistory() error {
+	return s.queries.ClearHistory(context.Background())
+}
@@ -0,0 +1,285 @@
+package storage
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pressly/goose/v3"
+)
+
+// newTestSQLiteStore opens a migrated store in a temporary file
+func newTestSQLiteStore(t *testing.T) (*SQLiteStore, string) {
+	path := filepath.Join(t.TempDir(), "trext.db")
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(s.Close)
+	return s, path
+}
+
+// requestNames returns the names of the saved requests of a collection
+func requestNames(t *testing.T, s Store, collectionID int64) []string {
+	requests, err := s.GetRequestsByCollection(collectionID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, req := range requests {
+		names = append(names, req.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// folderNames returns the names of the folders of a collection
+func folderNames(t *testing.T, s Store, collectionID int64) []string {
+	folders, err := s.GetFolders()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range folders {
+		if f.CollectionID == collectionID {
+			names = append(names, f.Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func TestSQLiteStoreMigrations(t *testing.T) {
+	s, path := newTestSQLiteStore(t)
+
+	folder := &Folder{CollectionID: DefaultCollectionID, Name: "users"}
+	if err := s.SaveFolder(folder); err != nil {
+		t.Fatal(err)
+	}
+	req := &SavedRequest{Name: "list", URL: "https://example.com", Method: "GET", CollectionID: DefaultCollectionID, FolderID: folder.ID}
+	if err := s.SaveRequest(req); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every down migration runs, and requests survive dropping folders
+	if err := goose.DownTo(s.db, ".", 1); err != nil {
+		t.Fatalf("migrating down: %v", err)
+	}
+	var name string
+	if err := s.db.QueryRow("SELECT name FROM requests WHERE id = ?", req.ID).Scan(&name); err != nil || name != "list" {
+		t.Errorf("request after migrating down = %q, %v", name, err)
+	}
+	if err := goose.DownTo(s.db, ".", 0); err != nil {
+		t.Fatalf("migrating down: %v", err)
+	}
+	s.Close()
+
+	// Reopening migrates up again
+	s, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got := requestNames(t, s, DefaultCollectionID); got != nil {
+		t.Errorf("requests after migrating up = %q, want none", got)
+	}
+}
+
+func TestSQLiteStoreCRUD(t *testing.T) {
+	s, _ := newTestSQLiteStore(t)
+
+	collections, err := s.GetCollections()
+	if err != nil || len(collections) != 1 || collections[0].ID != DefaultCollectionID {
+		t.Fatalf("GetCollections() = %v, %v, want the default collection", collections, err)
+	}
+
+	c := &Collection{Name: "API", Variables: map[string]string{"host": "example.com"}, SkipHistory: true}
+	if err := s.SaveCollection(c); err != nil {
+		t.Fatal(err)
+	}
+	c.Name = "Renamed"
+	if err := s.SaveCollection(c); err != nil {
+		t.Fatal(err)
+	}
+	collections, err = s.GetCollections()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i := slices.IndexFunc(collections, func(got *Collection) bool { return got.ID == c.ID }); i < 0 || !reflect.DeepEqual(collections[i], c) {
+		t.Errorf("GetCollections() = %v, want %+v among them", collections, c)
+	}
+
+	req := &SavedRequest{
+		Name: "get", URL: "https://{{host}}/", Method: "GET", CollectionID: c.ID,
+		Headers: `[{"key":"A","value":"1"}]`, Settings: "{}", Auth: "{}", Params: `{"path":[{"key":"id","value":"1"}]}`, BodyOptions: "{}",
+	}
+	if err := s.SaveRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	req.Method = "POST"
+	req.Body = "body"
+	if err := s.SaveRequest(req); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveRequestFilter(req.ID, ".id"); err != nil {
+		t.Fatal(err)
+	}
+	req.Filter = ".id"
+	requests, err := s.GetAllRequests()
+	if err != nil || len(requests) != 1 || !reflect.DeepEqual(requests[0], req) {
+		t.Fatalf("GetAllRequests() = %+v, %v, want %+v", requests, err, req)
+	}
+	if err := s.DeleteRequest(req.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := requestNames(t, s, c.ID); got != nil {
+		t.Errorf("requests after delete = %q", got)
+	}
+
+	env := &Environment{Name: "dev", Variables: map[string]string{"host": "localhost"}}
+	if err := s.SaveEnvironment(env); err != nil {
+		t.Fatal(err)
+	}
+	env.Variables["token"] = "t"
+	if err := s.SaveEnvironment(env); err != nil {
+		t.Fatal(err)
+	}
+	environments, err := s.GetEnvironments()
+	if err != nil || len(environments) != 1 || !reflect.DeepEqual(environments[0], env) {
+		t.Errorf("GetEnvironments() = %v, %v, want %+v", environments, err, env)
+	}
+	if err := s.SaveEnvironment(&Environment{Name: "dev"}); err == nil {
+		t.Error("saving a second environment named dev succeeded")
+	}
+	if err := s.DeleteEnvironment(env.ID); err != nil {
+		t.Fatal(err)
+	}
+	if environments, _ := s.GetEnvironments(); len(environments) != 0 {
+		t.Errorf("environments after delete = %v", environments)
+	}
+}
+
+func TestSQLiteStoreImportAndDelete(t *testing.T) {
+	s, _ := newTestSQLiteStore(t)
+
+	// Folder and parent IDs refer to the given folders
+	c := &Collection{Name: "Shop"}
+	folders := []*Folder{
+		{ID: -1, Name: "orders"},
+		{ID: -2, ParentID: -1, Name: "items"},
+		{ID: -3, Name: "users"},
+	}
+	requests := []*SavedRequest{
+		{Name: "top", URL: "/", Method: "GET"},
+		{Name: "order", URL: "/orders", Method: "GET", FolderID: -1},
+		{Name: "item", URL: "/orders/items", Method: "GET", FolderID: -2},
+		{Name: "user", URL: "/users", Method: "GET", FolderID: -3},
+	}
+	if err := s.ImportCollection(c, folders, requests); err != nil {
+		t.Fatal(err)
+	}
+	if c.ID == 0 || folders[1].ParentID != folders[0].ID || requests[2].FolderID != folders[1].ID || requests[0].FolderID != 0 {
+		t.Fatalf("IDs not replaced: collection %d, folders %+v, requests %+v", c.ID, folders, requests)
+	}
+	if got, want := folderNames(t, s, c.ID), []string{"items", "orders", "users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("folders = %q, want %q", got, want)
+	}
+	if got, want := requestNames(t, s, c.ID), []string{"item", "order", "top", "user"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requests = %q, want %q", got, want)
+	}
+
+	// Deleting a folder takes its subfolders and all their requests
+	if err := s.DeleteFolder(folders[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := folderNames(t, s, c.ID), []string{"users"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("folders after deleting orders = %q, want %q", got, want)
+	}
+	if got, want := requestNames(t, s, c.ID), []string{"top", "user"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("requests after deleting orders = %q, want %q", got, want)
+	}
+
+	// Renaming a folder
+	folders[2].Name = "people"
+	if err := s.SaveFolder(folders[2]); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := folderNames(t, s, c.ID), []string{"people"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("folders after renaming = %q, want %q", got, want)
+	}
+
+	// Deleting the collection takes the rest
+	if err := s.DeleteCollection(c.ID); err != nil {
+		t.Fatal(err)
+	}
+	if got := folderNames(t, s, c.ID); got != nil {
+		t.Errorf("folders after deleting the collection = %q", got)
+	}
+	if got := requestNames(t, s, c.ID); got != nil {
+		t.Errorf("requests after deleting the collection = %q", got)
+	}
+}
+
+func TestSQLiteStoreHistory(t *testing.T) {
+	s, _ := newTestSQLiteStore(t)
+
+	now := time.Now().Unix()
+	entries := []*HistoryEntry{
+		{Method: "GET", URL: "https://example.com/users/1", StatusCode: 200, Timestamp: now - 3, ResponseBody: "{}", ResponseSize: 2},
+		{Method: "POST", URL: "https://example.com/orders", StatusCode: 500, Timestamp: now - 2,
+			RequestParams: `{"query":[{"key":"a","value":"1"}]}`, Environment: "staging", Timing: "{}",
+			RequestTemplate: `{"method":"POST","url":"https://{{host}}/orders"}`},
+		{Method: "GET", URL: "https://down.example.com", Timestamp: now - 1, Error: "connection refused"},
+	}
+	for _, entry := range entries {
+		if err := s.AddToHistory(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := s.GetHistoryEntry(entries[1].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, entries[1]) {
+		t.Errorf("GetHistoryEntry() = %+v, want %+v", got, entries[1])
+	}
+
+	search := func(query string) []int64 {
+		found, err := s.SearchHistory(query, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, entry := range found {
+			ids = append(ids, entry.ID)
+		}
+		return ids
+	}
+	for query, want := range map[string][]int64{
+		"users":   {entries[0].ID},
+		"post":    {entries[1].ID},
+		"500":     {entries[1].ID},
+		"refused": {entries[2].ID},
+		"example": {entries[2].ID, entries[1].ID, entries[0].ID},
+	} {
+		if got := search(query); !reflect.DeepEqual(got, want) {
+			t.Errorf("SearchHistory(%q) = %v, want %v", query, got, want)
+		}
+	}
+
+	if err := s.PruneHistory(2, 0); err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := s.GetHistory(10); len(history) != 2 || history[0].ID != entries[2].ID {
+		t.Errorf("history after pruning to 2 = %+v", history)
+	}
+	if err := s.ClearHistory(); err != nil {
+		t.Fatal(err)
+	}
+	if history, _ := s.GetHistory(10); len(history) != 0 {
+		t.Errorf("history after clearing = %+v", history)
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+)
+
+// Storage backends selectable in the config
+const (
+	BackendPostgres = "postgres"
+	BackendSQLite   = "sqlite"
+)
+
+// Store persists saved requests, collections, environments and history
+type Store interface {
+	SaveRequest(req *SavedRequest) error
+	GetAllRequests() ([]*SavedRequest, error)
+	GetRequestsByCollection(collectionID int64) ([]*SavedRequest, error)
+	SaveRequestFilter(id int64, filter string) error
+	DeleteRequest(id int64) error
+
+	GetCollections() ([]*Collection, error)
+	SaveCollection(c *Collection) error
+	ImportCollection(c *Collection, folders []*Folder, requests []*SavedRequest) error
+	DeleteCollection(id int64) error
+
+	GetFolders() ([]*Folder, error)
+	SaveFolder(f *Folder) error
+	DeleteFolder(id int64) error
+
+	GetEnvironments() ([]*Environment, error)
+	SaveEnvironment(env *Environment) error
+	DeleteEnvironment(id int64) error
+
+	AddToHistory(entry *HistoryEntry) error
+	GetHistory(limit int) ([]*HistoryEntry, error)
+	SearchHistory(query string, limit int) ([]*HistoryEntry, error)
+	GetHistoryEntry(id int64) (*HistoryEntry, error)
+	PruneHistory(maxItems int, maxAge time.Duration) error
+	ClearHistory() error
+
+	Close()
+}
+
+var (
+	_ Store = (*DB)(nil)
+	_ Store = (*SQLiteStore)(nil)
+)
+
+// NewStore opens the backend selected in the config and runs its migrations.
+// With no backend set, PostgreSQL is used when DATABASE_URL is set and the
+// embedded SQLite database otherwise.
+func NewStore(cfg *Config) (Store, error) {
+	// Load .env file if it exists (ignores error if file doesn't exist)
+	_ = godotenv.Load()
+
+	backend := cfg.Storage.Backend
+	if backend == "" {
+		backend = BackendSQLite
+		if os.Getenv("DATABASE_URL") != "" {
+			backend = BackendPostgres
+		}
+	}
+
+	switch backend {
+	case BackendPostgres:
+		db, err := NewDB()
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	case BackendSQLite:
+		path := cfg.Storage.Path
+		if path == "" {
+			var err error
+			if path, err = getDefaultDatabasePath(); err != nil {
+				return nil, err
+			}
+		}
+		store, err := NewSQLiteStore(path)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (use %q or %q)", backend, BackendPostgres, BackendSQLite)
+	}
+}
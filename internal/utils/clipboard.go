@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands lists the clipboard tools to try, in order of preference
+func clipboardCommands() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	}
+
+	var commands [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		commands = append(commands, []string{"wl-copy"})
+	}
+	return append(commands,
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+		[]string{"clip.exe"}, // WSL
+	)
+}
+
+// CopyToClipboard copies text to the system clipboard
+func CopyToClipboard(text string) error {
+	for _, args := range clipboardCommands() {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return errors.New("no clipboard tool found (install xclip, xsel or wl-clipboard)")
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/ohler55/ojg/jp"
+)
+
+// FilterJSON runs a filter over JSON decoded by DecodeJSON and returns the results as
+// indented JSON. Filters starting with '$' are JSONPath, whose matches are
+// returned as an array; anything else is a jq program, whose outputs are
+// returned one after another like jq prints them. jq programs stop with the
+// context's error once it's done.
+func FilterJSON(ctx context.Context, data any, filter string) (string, error) {
+	filter = strings.TrimSpace(filter)
+	if strings.HasPrefix(filter, "$") {
+		path, err := jp.ParseString(filter)
+		if err != nil {
+			return "", err
+		}
+		matches := path.Get(data)
+		if matches == nil {
+			matches = []any{}
+		}
+		return encodeJSON(matches)
+	}
+
+	query, err := gojq.Parse(filter)
+	if err != nil {
+		return "", err
+	}
+
+	var outputs []string
+	iter := query.RunWithContext(ctx, data)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				break
+			}
+			return "", err
+		}
+		out, err := encodeJSON(v)
+		if err != nil {
+			return "", err
+		}
+		outputs = append(outputs, out)
+	}
+	return strings.Join(outputs, "\n"), nil
+}
+
+// DecodeJSON decodes a JSON document for FilterJSON. Numbers are kept as
+// json.Number, so integers too large for a float64 come out as they went in.
+func DecodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	return v, nil
+}
+
+// encodeJSON indents a value without escaping HTML characters
+func encodeJSON(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFilterJSON(t *testing.T) {
+	const body = `{"items": [{"id": 12345678901234567890, "price": 1.10, "name": "a"}, {"id": 2, "price": 3e2, "name": "b"}]}`
+	data, err := DecodeJSON([]byte(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{"jq large integer", ".items[0].id", "12345678901234567890"},
+		{"jq numbers as sent", "[.items[].price]", "[\n  1.10,\n  3e2\n]"},
+		{"jq arithmetic", ".items[1].id + 1", "3"},
+		{"jq several outputs", ".items[].name", "\"a\"\n\"b\""},
+		{"JSONPath large integer", "$.items[0].id", "[\n  12345678901234567890\n]"},
+		{"JSONPath no match", "$.missing", "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FilterJSON(context.Background(), data, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("FilterJSON(%q) = %s, want %s", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeJSONInvalid(t *testing.T) {
+	for _, body := range []string{"", "{", `{"a": 1} x`, `{"a": 1}{}`, "<html>"} {
+		if _, err := DecodeJSON([]byte(body)); err == nil {
+			t.Errorf("DecodeJSON(%q) succeeded", body)
+		}
+	}
+	if _, err := DecodeJSON([]byte(" [1]\n")); err != nil {
+		t.Errorf("DecodeJSON with surrounding whitespace: %v", err)
+	}
+}
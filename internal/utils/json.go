@@ -3,7 +3,6 @@ package utils
 import (
 	"bytes"
 	"encoding/json"
-	"strings"
 )
 
 // FormatJSON formats a JSON string with indentation
@@ -30,36 +29,6 @@ func CompactJSON(input string) (string, error) {
 	return buf.String(), nil
 }
 
-// ParseHeaders parses a string of headers (key: value format) into a map
-func ParseHeaders(input string) map[string]string {
-	headers := make(map[string]string)
-	lines := strings.Split(input, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, ":", 2)
-		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value := strings.TrimSpace(parts[1])
-			if key != "" {
-				headers[key] = value
-			}
-		}
-	}
-	return headers
-}
-
-// FormatHeaders formats a map of headers as a string
-func FormatHeaders(headers map[string]string) string {
-	var lines []string
-	for key, value := range headers {
-		lines = append(lines, key+": "+value)
-	}
-	return strings.Join(lines, "\n")
-}
-
 // TruncateString truncates a string to a maximum length
 func TruncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// ParseVariables parses a string of variables (name=value format, one per line) into a map.
+// Lines starting with # are treated as comments.
+func ParseVariables(input string) map[string]string {
+	vars := make(map[string]string)
+	lines := strings.Split(input, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) == 2 {
+			key := strings.TrimSpace(parts[0])
+			if key != "" {
+				vars[key] = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+	return vars
+}
+
+// FormatVariables formats a map of variables as name=value lines, sorted by name
+func FormatVariables(vars map[string]string) string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, key := range keys {
+		lines[i] = key + "=" + vars[key]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,22 +1,22 @@
 -- name: GetCollections :many
-SELECT id, name, description 
+SELECT id, name, description, variables, skip_history 
 FROM collections 
 ORDER BY name;
 
 -- name: GetCollectionByID :one
-SELECT id, name, description 
+SELECT id, name, description, variables, skip_history 
 FROM collections 
 WHERE id = $1;
 
 -- name: CreateCollection :one
-INSERT INTO collections (name, description) 
-VALUES ($1, $2)
-RETURNING id, name, description;
+INSERT INTO collections (name, description, variables, skip_history) 
+VALUES ($1, $2, $3, $4)
+RETURNING id, name, description, variables, skip_history;
 
 -- name: UpdateCollection :exec
 UPDATE collections 
-SET name = $1, description = $2 
-WHERE id = $3;
+SET name = $1, description = $2, variables = $3, skip_history = $4 
+WHERE id = $5;
 
 -- name: DeleteCollection :exec
 DELETE FROM collections 
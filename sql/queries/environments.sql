@@ -0,0 +1,23 @@
+-- name: GetEnvironments :many
+SELECT id, name, variables 
+FROM environments 
+ORDER BY name;
+
+-- name: GetEnvironmentByID :one
+SELECT id, name, variables 
+FROM environments 
+WHERE id = $1;
+
+-- name: CreateEnvironment :one
+INSERT INTO environments (name, variables) 
+VALUES ($1, $2)
+RETURNING id, name, variables;
+
+-- name: UpdateEnvironment :exec
+UPDATE environments 
+SET name = $1, variables = $2 
+WHERE id = $3;
+
+-- name: DeleteEnvironment :exec
+DELETE FROM environments 
+WHERE id = $1;
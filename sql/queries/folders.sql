@@ -0,0 +1,27 @@
+-- name: GetFolders :many
+SELECT id, collection_id, parent_id, name
+FROM folders
+ORDER BY name;
+
+-- name: CreateFolder :one
+INSERT INTO folders (collection_id, parent_id, name)
+VALUES ($1, $2, $3)
+RETURNING id, collection_id, parent_id, name;
+
+-- name: UpdateFolder :exec
+UPDATE folders
+SET parent_id = $1, name = $2
+WHERE id = $3;
+
+-- name: DeleteFolder :exec
+DELETE FROM folders
+WHERE id = $1;
+
+-- name: DeleteFolderRequests :exec
+WITH RECURSIVE subfolders AS (
+    SELECT id FROM folders WHERE id = $1
+    UNION ALL
+    SELECT folders.id FROM folders JOIN subfolders ON folders.parent_id = subfolders.id
+)
+DELETE FROM requests
+WHERE folder_id IN (SELECT id FROM subfolders);
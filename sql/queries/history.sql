@@ -1,22 +1,49 @@
 -- name: GetHistory :many
-SELECT id, url, method, status_code, duration_ms, timestamp 
+SELECT id, url, method, status_code, duration_ms, timestamp, name, error
 FROM history 
 ORDER BY timestamp DESC 
 LIMIT $1;
 
+-- name: SearchHistory :many
+SELECT id, url, method, status_code, duration_ms, timestamp, name, error
+FROM history
+WHERE url ILIKE '%' || @query::text || '%'
+   OR name ILIKE '%' || @query::text || '%'
+   OR error ILIKE '%' || @query::text || '%'
+   OR method = UPPER(@query::text)
+   OR CAST(status_code AS TEXT) = @query::text
+ORDER BY timestamp DESC
+LIMIT @max_items;
+
 -- name: GetHistoryByID :one
-SELECT id, url, method, status_code, duration_ms, timestamp 
+SELECT id, url, method, status_code, duration_ms, timestamp, name,
+       request_headers, request_body, request_settings, request_auth,
+       response_headers, response_body, response_size, error, timing, request_body_options,
+       request_params, environment, request_template
 FROM history 
 WHERE id = $1;
 
 -- name: AddToHistory :one
-INSERT INTO history (url, method, status_code, duration_ms, timestamp) 
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, url, method, status_code, duration_ms, timestamp;
+INSERT INTO history (
+    url, method, status_code, duration_ms, timestamp, name,
+    request_headers, request_body, request_settings, request_auth,
+    response_headers, response_body, response_size, error, timing, request_body_options,
+    request_params, environment, request_template
+)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+RETURNING id;
 
--- name: DeleteHistoryEntry :exec
-DELETE FROM history 
-WHERE id = $1;
+-- name: DeleteHistoryBefore :exec
+DELETE FROM history
+WHERE timestamp < $1;
+
+-- name: TrimHistory :exec
+DELETE FROM history
+WHERE id NOT IN (
+    SELECT id FROM history
+    ORDER BY timestamp DESC, id DESC
+    LIMIT $1
+);
 
 -- name: ClearHistory :exec
 DELETE FROM history;
@@ -1,29 +1,38 @@
 -- name: GetAllRequests :many
-SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
 FROM requests 
 ORDER BY name;
 
 -- name: GetRequestByID :one
-SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
 FROM requests 
 WHERE id = $1;
 
 -- name: GetRequestsByCollectionID :many
-SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
 FROM requests 
 WHERE collection_id = $1
 ORDER BY name;
 
 -- name: CreateRequest :one
-INSERT INTO requests (name, url, method, headers, body, collection_id) 
-VALUES ($1, $2, $3, $4, $5, $6)
-RETURNING id, name, url, method, headers, body, collection_id;
+INSERT INTO requests (name, url, method, headers, body, collection_id, folder_id, settings, auth, filter, params, body_options) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, name, url, method, headers, body, collection_id, settings, auth, folder_id, filter, params, body_options;
 
 -- name: UpdateRequest :exec
 UPDATE requests 
-SET name = $1, url = $2, method = $3, headers = $4, body = $5, collection_id = $6 
-WHERE id = $7;
+SET name = $1, url = $2, method = $3, headers = $4, body = $5, collection_id = $6, folder_id = $7, settings = $8, auth = $9, filter = $10, params = $11, body_options = $12 
+WHERE id = $13;
+
+-- name: UpdateRequestFilter :exec
+UPDATE requests 
+SET filter = $1 
+WHERE id = $2;
 
 -- name: DeleteRequest :exec
 DELETE FROM requests 
 WHERE id = $1;
+
+-- name: DeleteRequestsByCollectionID :exec
+DELETE FROM requests 
+WHERE collection_id = $1;
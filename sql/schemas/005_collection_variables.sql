@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS variables TEXT NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collections DROP COLUMN IF EXISTS variables;
+-- +goose StatementEnd
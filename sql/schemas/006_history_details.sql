@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS request_headers TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS request_body TEXT NOT NULL DEFAULT '';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS request_settings TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS request_auth TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS response_headers TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS response_body TEXT NOT NULL DEFAULT '';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS response_size BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE history ADD COLUMN IF NOT EXISTS error TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS history_timestamp_idx ON history (timestamp DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS history_timestamp_idx;
+ALTER TABLE history DROP COLUMN IF EXISTS error;
+ALTER TABLE history DROP COLUMN IF EXISTS response_size;
+ALTER TABLE history DROP COLUMN IF EXISTS response_body;
+ALTER TABLE history DROP COLUMN IF EXISTS response_headers;
+ALTER TABLE history DROP COLUMN IF EXISTS request_auth;
+ALTER TABLE history DROP COLUMN IF EXISTS request_settings;
+ALTER TABLE history DROP COLUMN IF EXISTS request_body;
+ALTER TABLE history DROP COLUMN IF EXISTS request_headers;
+ALTER TABLE history DROP COLUMN IF EXISTS name;
+-- +goose StatementEnd
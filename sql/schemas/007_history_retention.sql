@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE collections ADD COLUMN IF NOT EXISTS skip_history BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE collections DROP COLUMN IF EXISTS skip_history;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history ADD COLUMN IF NOT EXISTS timing TEXT NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE history DROP COLUMN IF EXISTS timing;
+-- +goose StatementEnd
@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS filter TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE requests DROP COLUMN IF EXISTS filter;
+-- +goose StatementEnd
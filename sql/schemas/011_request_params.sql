@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE requests ADD COLUMN IF NOT EXISTS params TEXT NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE requests DROP COLUMN IF EXISTS params;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history ADD COLUMN IF NOT EXISTS request_params TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN IF NOT EXISTS environment TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE history DROP COLUMN IF EXISTS environment;
+ALTER TABLE history DROP COLUMN IF EXISTS request_params;
+-- +goose StatementEnd
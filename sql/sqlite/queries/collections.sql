@@ -0,0 +1,18 @@
+-- name: GetCollections :many
+SELECT id, name, description, variables, skip_history 
+FROM collections 
+ORDER BY name;
+
+-- name: CreateCollection :one
+INSERT INTO collections (name, description, variables, skip_history) 
+VALUES (?, ?, ?, ?)
+RETURNING id, name, description, variables, skip_history;
+
+-- name: UpdateCollection :exec
+UPDATE collections 
+SET name = ?, description = ?, variables = ?, skip_history = ? 
+WHERE id = ?;
+
+-- name: DeleteCollection :exec
+DELETE FROM collections 
+WHERE id = ?;
@@ -0,0 +1,23 @@
+-- name: GetEnvironments :many
+SELECT id, name, variables 
+FROM environments 
+ORDER BY name;
+
+-- name: GetEnvironmentByID :one
+SELECT id, name, variables 
+FROM environments 
+WHERE id = ?;
+
+-- name: CreateEnvironment :one
+INSERT INTO environments (name, variables) 
+VALUES (?, ?)
+RETURNING id, name, variables;
+
+-- name: UpdateEnvironment :exec
+UPDATE environments 
+SET name = ?, variables = ? 
+WHERE id = ?;
+
+-- name: DeleteEnvironment :exec
+DELETE FROM environments 
+WHERE id = ?;
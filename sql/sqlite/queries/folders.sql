@@ -0,0 +1,27 @@
+-- name: GetFolders :many
+SELECT id, collection_id, parent_id, name
+FROM folders
+ORDER BY name;
+
+-- name: CreateFolder :one
+INSERT INTO folders (collection_id, parent_id, name)
+VALUES (?, ?, ?)
+RETURNING id, collection_id, parent_id, name;
+
+-- name: UpdateFolder :exec
+UPDATE folders
+SET parent_id = ?, name = ?
+WHERE id = ?;
+
+-- name: DeleteFolder :exec
+DELETE FROM folders
+WHERE id = ?;
+
+-- name: DeleteFolderRequests :exec
+WITH RECURSIVE subfolders AS (
+    SELECT id FROM folders WHERE id = ?
+    UNION ALL
+    SELECT folders.id FROM folders JOIN subfolders ON folders.parent_id = subfolders.id
+)
+DELETE FROM requests
+WHERE folder_id IN (SELECT id FROM subfolders);
@@ -0,0 +1,49 @@
+-- name: GetHistory :many
+SELECT id, url, method, status_code, duration_ms, timestamp, name, error
+FROM history 
+ORDER BY timestamp DESC 
+LIMIT ?;
+
+-- name: SearchHistory :many
+SELECT id, url, method, status_code, duration_ms, timestamp, name, error
+FROM history
+WHERE url LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+   OR name LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+   OR error LIKE '%' || CAST(sqlc.arg(query) AS TEXT) || '%'
+   OR method = UPPER(CAST(sqlc.arg(query) AS TEXT))
+   OR CAST(status_code AS TEXT) = CAST(sqlc.arg(query) AS TEXT)
+ORDER BY timestamp DESC
+LIMIT sqlc.arg(max_items);
+
+-- name: GetHistoryByID :one
+SELECT id, url, method, status_code, duration_ms, timestamp, name,
+       request_headers, request_body, request_settings, request_auth,
+       response_headers, response_body, response_size, error, timing, request_body_options,
+       request_params, environment, request_template
+FROM history 
+WHERE id = ?;
+
+-- name: AddToHistory :one
+INSERT INTO history (
+    url, method, status_code, duration_ms, timestamp, name,
+    request_headers, request_body, request_settings, request_auth,
+    response_headers, response_body, response_size, error, timing, request_body_options,
+    request_params, environment, request_template
+)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id;
+
+-- name: DeleteHistoryBefore :exec
+DELETE FROM history
+WHERE timestamp < ?;
+
+-- name: TrimHistory :exec
+DELETE FROM history
+WHERE id NOT IN (
+    SELECT id FROM history
+    ORDER BY timestamp DESC, id DESC
+    LIMIT ?
+);
+
+-- name: ClearHistory :exec
+DELETE FROM history;
@@ -0,0 +1,38 @@
+-- name: GetAllRequests :many
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
+FROM requests 
+ORDER BY name;
+
+-- name: GetRequestByID :one
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
+FROM requests 
+WHERE id = ?;
+
+-- name: GetRequestsByCollectionID :many
+SELECT id, name, url, method, headers, body, COALESCE(collection_id, 1) as collection_id, folder_id, settings, auth, filter, params, body_options
+FROM requests 
+WHERE collection_id = ?
+ORDER BY name;
+
+-- name: CreateRequest :one
+INSERT INTO requests (name, url, method, headers, body, collection_id, folder_id, settings, auth, filter, params, body_options) 
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+RETURNING id, name, url, method, headers, body, collection_id, settings, auth, folder_id, filter, params, body_options;
+
+-- name: UpdateRequest :exec
+UPDATE requests 
+SET name = ?, url = ?, method = ?, headers = ?, body = ?, collection_id = ?, folder_id = ?, settings = ?, auth = ?, filter = ?, params = ?, body_options = ? 
+WHERE id = ?;
+
+-- name: UpdateRequestFilter :exec
+UPDATE requests 
+SET filter = ? 
+WHERE id = ?;
+
+-- name: DeleteRequest :exec
+DELETE FROM requests 
+WHERE id = ?;
+
+-- name: DeleteRequestsByCollectionID :exec
+DELETE FROM requests 
+WHERE collection_id = ?;
@@ -0,0 +1,61 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS collections (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    description TEXT DEFAULT '',
+    variables TEXT NOT NULL DEFAULT '{}',
+    skip_history BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE IF NOT EXISTS requests (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT 'GET',
+    headers TEXT DEFAULT '{}',
+    body TEXT DEFAULT '',
+    collection_id INTEGER REFERENCES collections(id) ON DELETE SET NULL,
+    settings TEXT NOT NULL DEFAULT '{}',
+    auth TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS environments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    variables TEXT NOT NULL DEFAULT '{}'
+);
+
+CREATE TABLE IF NOT EXISTS history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    url TEXT NOT NULL,
+    method TEXT NOT NULL,
+    status_code INTEGER,
+    duration_ms BIGINT,
+    timestamp BIGINT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    request_headers TEXT NOT NULL DEFAULT '{}',
+    request_body TEXT NOT NULL DEFAULT '',
+    request_settings TEXT NOT NULL DEFAULT '{}',
+    request_auth TEXT NOT NULL DEFAULT '{}',
+    response_headers TEXT NOT NULL DEFAULT '{}',
+    response_body TEXT NOT NULL DEFAULT '',
+    response_size BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS history_timestamp_idx ON history (timestamp DESC);
+
+-- Insert default collection if not exists
+INSERT INTO collections (id, name, description) 
+VALUES (1, 'Default', 'Default collection')
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS history;
+DROP TABLE IF EXISTS environments;
+DROP TABLE IF EXISTS requests;
+DROP TABLE IF EXISTS collections;
+-- +goose StatementEnd
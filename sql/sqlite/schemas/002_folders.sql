@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS folders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+    parent_id INTEGER REFERENCES folders(id) ON DELETE CASCADE,
+    name TEXT NOT NULL
+);
+
+ALTER TABLE requests ADD COLUMN folder_id INTEGER REFERENCES folders(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- SQLite doesn't support dropping a column used in a foreign key, so the
+-- table is rebuilt without it
+CREATE TABLE requests_without_folders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    method TEXT NOT NULL DEFAULT 'GET',
+    headers TEXT DEFAULT '{}',
+    body TEXT DEFAULT '',
+    collection_id INTEGER REFERENCES collections(id) ON DELETE SET NULL,
+    settings TEXT NOT NULL DEFAULT '{}',
+    auth TEXT NOT NULL DEFAULT '{}'
+);
+INSERT INTO requests_without_folders (id, name, url, method, headers, body, collection_id, settings, auth)
+SELECT id, name, url, method, headers, body, collection_id, settings, auth FROM requests;
+DROP TABLE requests;
+ALTER TABLE requests_without_folders RENAME TO requests;
+
+DROP TABLE IF EXISTS folders;
+-- +goose StatementEnd
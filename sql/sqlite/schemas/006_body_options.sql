@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE requests ADD COLUMN body_options TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN request_body_options TEXT NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE history DROP COLUMN request_body_options;
+ALTER TABLE requests DROP COLUMN body_options;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE history ADD COLUMN request_params TEXT NOT NULL DEFAULT '{}';
+ALTER TABLE history ADD COLUMN environment TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE history DROP COLUMN environment;
+ALTER TABLE history DROP COLUMN request_params;
+-- +goose StatementEnd